@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"roundtable/engine"
+)
+
+// askResult is the --json output of roundtable ask
+type askResult struct {
+	Prompt    string            `json:"prompt"`
+	Responses []askResponse     `json:"responses"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	Consensus askConsensus      `json:"consensus"`
+}
+
+type askResponse struct {
+	Model   string `json:"model"`
+	Content string `json:"content"`
+}
+
+type askConsensus struct {
+	Reached    bool     `json:"reached"`
+	Agree      int      `json:"agree"`
+	Object     int      `json:"object"`
+	Add        int      `json:"add"`
+	Unclear    int      `json:"unclear"`
+	Objections []string `json:"objections,omitempty"`
+	Note       string   `json:"note,omitempty"` // Why consensus couldn't be judged
+}
+
+// runAsk implements "roundtable ask [--json] question": one parallel round,
+// printed to stdout. Returns the exit code: 0 on consensus, 1 otherwise,
+// 2 for bad usage.
+func runAsk(ctx context.Context, cfg *engine.Config, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ask", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print the responses and verdict as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: roundtable ask [--json] <question>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	prompt := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if prompt == "" {
+		fs.Usage()
+		return 2
+	}
+
+	result, err := engine.RunDebate(ctx, cfg, prompt, engine.Options{})
+	if err != nil && len(result.Messages) <= 1 && len(result.Errors) == 0 {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	out := newAskResult(prompt, result)
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	} else {
+		writeAskText(stdout, out)
+	}
+
+	if out.Consensus.Reached {
+		return 0
+	}
+	return 1
+}
+
+func newAskResult(prompt string, result engine.DebateResult) askResult {
+	out := askResult{Prompt: prompt}
+	for _, msg := range result.Messages {
+		if msg.Source != "user" {
+			out.Responses = append(out.Responses, askResponse{Model: msg.Source, Content: msg.Content})
+		}
+	}
+	if len(result.Errors) > 0 {
+		out.Errors = make(map[string]string, len(result.Errors))
+		for id, err := range result.Errors {
+			out.Errors[id] = err.Error()
+		}
+	}
+
+	c := result.Consensus
+	out.Consensus = askConsensus{
+		Reached:    c.HasConsensus,
+		Agree:      c.AgreeCount,
+		Object:     c.ObjectCount,
+		Add:        c.AddCount,
+		Unclear:    c.UnknownCount,
+		Objections: c.DescribeObjections(func(id string) string { return id }),
+		Note:       c.ParticipationMessage(),
+	}
+	return out
+}
+
+func writeAskText(w io.Writer, out askResult) {
+	for _, resp := range out.Responses {
+		fmt.Fprintf(w, "=== %s ===\n%s\n\n", resp.Model, strings.TrimSpace(resp.Content))
+	}
+
+	failed := make([]string, 0, len(out.Errors))
+	for id := range out.Errors {
+		failed = append(failed, id)
+	}
+	sort.Strings(failed)
+	for _, id := range failed {
+		fmt.Fprintf(w, "=== %s (failed) ===\n%s\n\n", id, out.Errors[id])
+	}
+
+	c := out.Consensus
+	verdict := "No consensus"
+	if c.Reached {
+		verdict = "Consensus reached"
+	}
+	fmt.Fprintf(w, "%s: %d agree, %d object, %d add, %d unclear.\n", verdict, c.Agree, c.Object, c.Add, c.Unclear)
+	if c.Note != "" {
+		fmt.Fprintf(w, "Note: %s\n", c.Note)
+	}
+	for _, objection := range c.Objections {
+		fmt.Fprintf(w, "  %s\n", objection)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"roundtable/engine"
+	"roundtable/internal/config"
+)
+
+// askConfig enables one exec model per reply
+func askConfig(t *testing.T, replies map[string]string) *engine.Config {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Defaults.MinConsensusParticipants = 2
+	for id, reply := range replies {
+		script := filepath.Join(t.TempDir(), id+".sh")
+		body := "#!/bin/sh\ncat > /dev/null\necho '{\"text\":\"" + reply + "\",\"done\":true}'\n"
+		if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+			t.Fatal(err)
+		}
+		cfg.Models.Exec = append(cfg.Models.Exec, config.ExecModelConfig{ID: id, Enabled: true, Command: script})
+	}
+	return cfg
+}
+
+func TestRunAsk(t *testing.T) {
+	agree := map[string]string{"alpha": "AGREE: alpha, ship it", "beta": "AGREE: alpha"}
+	split := map[string]string{"alpha": "AGREE: alpha, ship it", "beta": "OBJECT: no tests"}
+
+	tests := []struct {
+		name     string
+		replies  map[string]string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{"consensus", agree, []string{"Ship", "it?"}, 0, []string{"=== alpha ===\nAGREE: alpha, ship it", "Consensus reached: 2 agree, 0 object"}},
+		{"objection", split, []string{"Ship it?"}, 1, []string{"=== beta ===\nOBJECT: no tests", "No consensus: 1 agree, 1 object", "beta objects: no tests"}},
+		{"no question", agree, nil, 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runAsk(context.Background(), askConfig(t, tt.replies), tt.args, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunAsk_JSON(t *testing.T) {
+	cfg := askConfig(t, map[string]string{"alpha": "AGREE: alpha", "beta": "AGREE: alpha"})
+
+	var stdout, stderr bytes.Buffer
+	if code := runAsk(context.Background(), cfg, []string{"--json", "Ship it?"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, want 0 (stderr: %s)", code, stderr.String())
+	}
+
+	var out askResult
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, stdout.String())
+	}
+	if out.Prompt != "Ship it?" || len(out.Responses) != 2 {
+		t.Errorf("unexpected result: %+v", out)
+	}
+	if !out.Consensus.Reached || out.Consensus.Agree != 2 {
+		t.Errorf("expected consensus with 2 agree, got %+v", out.Consensus)
+	}
+}
@@ -1,34 +1,149 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"roundtable/engine"
+	"roundtable/internal/config"
+	"roundtable/internal/db"
+	"roundtable/internal/logging"
+	"roundtable/internal/metrics"
+	"roundtable/internal/transcript"
 	"roundtable/internal/ui"
 )
 
 var Version = "0.1.0"
 
 func main() {
-	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
+	// "roundtable ask <question>" runs one round without the TUI
+	if len(os.Args) > 1 && os.Args[1] == "ask" {
+		cfg, err := engine.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		closeLog := setupLogging(cfg, ui.Options{})
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		code := runAsk(ctx, cfg, os.Args[2:], os.Stdout, os.Stderr)
+		stop()
+		closeLog()
+		os.Exit(code)
+	}
+
+	var (
+		showVersion bool
+		opts        ui.Options
+	)
+	flag.BoolVar(&showVersion, "version", false, "print the version and exit")
+	flag.BoolVar(&showVersion, "v", false, "print the version and exit (shorthand)")
+	flag.BoolVar(&opts.NoPersist, "no-persist", false, "keep this session in memory; nothing is written to the database")
+	flag.StringVar(&opts.DataDir, "data-dir", "", "directory for the debate database (overrides $ROUNDTABLE_DATA_DIR and $XDG_DATA_HOME)")
+	flag.StringVar(&opts.Project, "project", "", "project directory debates belong to and models run in (default: current directory)")
+	flag.Parse()
+
+	if showVersion {
 		fmt.Printf("roundtable %s\n", Version)
 		return
 	}
 
-	// Silence log output during TUI operation - it corrupts the display
-	log.SetOutput(io.Discard)
+	// Config problems are reported by the UI; logging falls back to defaults
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+	closeLog := setupLogging(cfg, opts)
+	defer closeLog()
+	stopMetrics := startMetrics(cfg)
+	defer stopMetrics()
+	opts.Transcript = openTranscript(cfg, opts)
+	defer opts.Transcript.Close()
 
-	m := ui.New()
+	m := ui.New(opts)
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	// Set the program reference for async model response handling
 	ui.SetProgram(p)
 
 	if _, err := p.Run(); err != nil {
+		slog.Error("TUI exited", "err", err)
+		stopMetrics()
+		opts.Transcript.Close()
+		closeLog()
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// setupLogging sends log output to roundtable.log in the data dir, never the
+// terminal, since it corrupts the display. Ephemeral sessions write nothing
+// to disk, so their logs are dropped. Returns a function closing the log.
+func setupLogging(cfg *config.Config, opts ui.Options) func() error {
+	logging.Discard()
+	noop := func() error { return nil }
+	if opts.NoPersist || cfg.Defaults.NoPersist {
+		return noop
+	}
+
+	dir := opts.DataDir
+	if dir == "" {
+		var err error
+		if dir, err = db.DataDir(); err != nil {
+			return noop
+		}
+	}
+	level, _ := logging.ParseLevel(cfg.Defaults.LogLevel) // Validate reports a bad level
+	closeLog, err := logging.Setup(filepath.Join(dir, logging.FileName), level, logging.DefaultMaxSize)
+	if err != nil {
+		return noop
+	}
+	slog.Info("roundtable starting", "version", Version, "args", os.Args[1:])
+	return closeLog
+}
+
+// startMetrics serves /metrics if the config sets metrics.port. Returns a
+// function stopping the server.
+func startMetrics(cfg *config.Config) func() {
+	if cfg.Metrics.Port == 0 {
+		return func() {}
+	}
+	server := metrics.NewServer(metrics.Default, cfg.Metrics.Port)
+	if err := server.Start(); err != nil {
+		slog.Error("metrics server failed to start", "port", cfg.Metrics.Port, "err", err)
+		return func() {}
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Stop(ctx)
+	}
+}
+
+// openTranscript opens the JSON Lines transcript if the config sets
+// transcript.path. Ephemeral sessions write nothing to disk, so they get
+// none.
+func openTranscript(cfg *config.Config, opts ui.Options) *transcript.Writer {
+	if cfg.Transcript.Path == "" || opts.NoPersist || cfg.Defaults.NoPersist {
+		return nil
+	}
+	path := cfg.Transcript.Path
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	w, err := transcript.Open(path)
+	if err != nil {
+		slog.Error("transcript failed to open", "path", path, "err", err)
+		return nil
+	}
+	return w
+}
@@ -0,0 +1,132 @@
+// engine/engine.go
+
+// Package engine runs Roundtable debates without the TUI, so other Go
+// programs can ask the models a question and check whether they agree
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"roundtable/internal/config"
+	"roundtable/internal/consensus"
+	"roundtable/internal/models"
+	"roundtable/internal/orchestrator"
+)
+
+// Config is Roundtable's configuration, as read from config.yaml
+type Config = config.Config
+
+// ConsensusResult is the consensus analysis of a round's responses
+type ConsensusResult = consensus.ConsensusResult
+
+// Message is one message of a debate: the user's prompt or a model's response
+type Message = models.Message
+
+// ErrNoModels is returned when the config enables no models
+var ErrNoModels = errors.New("no models enabled")
+
+// LoadConfig reads the user's config file, or the defaults if there is none
+func LoadConfig() (*Config, error) {
+	return config.Load()
+}
+
+// DefaultConfig returns the configuration used when no config file exists
+func DefaultConfig() *Config {
+	return config.Default()
+}
+
+// Options control a headless debate
+type Options struct {
+	// After the first round, ask every model for its position and judge
+	// consensus on those answers
+	ConsensusRound bool
+
+	// Directory CLI and exec models run in; empty means the current directory
+	WorkDir string
+}
+
+// DebateResult is the outcome of RunDebate
+type DebateResult struct {
+	Messages  []Message        // The prompt, then each round's responses
+	Consensus ConsensusResult  // Analysis of the last round
+	Errors    map[string]error // Models that failed, by ID
+}
+
+// RunDebate sends prompt to every enabled model and analyzes their positions.
+// With opts.ConsensusRound, the models are then polled for their positions
+// and consensus is judged on that round instead. If ctx ends early, the
+// responses so far are returned with ctx's error.
+func RunDebate(ctx context.Context, cfg *Config, prompt string, opts Options) (DebateResult, error) {
+	result := DebateResult{Errors: make(map[string]error)}
+
+	registry := models.NewRegistry(cfg)
+	if registry.Count() == 0 {
+		return result, ErrNoModels
+	}
+	registry.SetWorkDir(opts.WorkDir)
+	orch := orchestrator.NewFromConfig(cfg, registry)
+	parser := consensus.NewParser(cfg.Consensus.ParserOptions())
+
+	result.Messages = append(result.Messages, Message{Source: "user", Content: prompt, Timestamp: time.Now()})
+	round := collectRound(orch.ParallelSeed(ctx, result.Messages, prompt), &result)
+
+	if opts.ConsensusRound && ctx.Err() == nil && len(round) > 0 {
+		round = collectRound(orch.ConsensusPrompt(ctx, result.Messages), &result)
+	}
+
+	result.Consensus = consensus.AnalyzeConsensusWithMinimum(roundPositions(parser, prompt, round), cfg.Defaults.MinConsensusParticipants)
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	if len(round) == 0 {
+		return result, fmt.Errorf("no model responded")
+	}
+	return result, nil
+}
+
+// collectRound reads a round's responses to the end, appending each model's
+// full response to result.Messages in the order they started arriving.
+// Returns the round's responses by model ID.
+func collectRound(responses <-chan orchestrator.Response, result *DebateResult) map[string]string {
+	var order []string
+	content := make(map[string]*strings.Builder)
+
+	for resp := range responses {
+		if resp.Error != nil {
+			result.Errors[resp.ModelID] = resp.Error
+			continue
+		}
+		if resp.Content == "" {
+			continue
+		}
+		b, ok := content[resp.ModelID]
+		if !ok {
+			b = &strings.Builder{}
+			content[resp.ModelID] = b
+			order = append(order, resp.ModelID)
+		}
+		b.WriteString(resp.Content)
+	}
+
+	round := make(map[string]string, len(order))
+	for _, id := range order {
+		round[id] = content[id].String()
+		result.Messages = append(result.Messages, Message{Source: id, Content: round[id], Timestamp: time.Now()})
+	}
+	return round
+}
+
+// roundPositions parses each response's position. Low-effort responses
+// (refusals, echoes) don't count, as in the TUI.
+func roundPositions(parser *consensus.Parser, prompt string, round map[string]string) map[string]consensus.ParsedPosition {
+	r := consensus.Round{Prompt: prompt}
+	for id, content := range round {
+		r.Responses = append(r.Responses, consensus.Message{Source: id, Content: content})
+	}
+	return parser.RoundPositions(r)
+}
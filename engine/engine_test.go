@@ -0,0 +1,96 @@
+// engine/engine_test.go
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"roundtable/internal/config"
+)
+
+// mockModel writes an exec model that answers reply, or pollReply when asked
+// for its position in a consensus round
+func mockModel(t *testing.T, id, reply, pollReply string) config.ExecModelConfig {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), id+".sh")
+	body := "#!/bin/sh\n" +
+		"if grep -q 'please state your position'; then\n" +
+		"  echo '{\"text\":\"" + pollReply + "\",\"done\":true}'\n" +
+		"else\n" +
+		"  echo '{\"text\":\"" + reply + "\",\"done\":true}'\n" +
+		"fi\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return config.ExecModelConfig{ID: id, Enabled: true, Command: script}
+}
+
+func TestRunDebate(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Defaults.MinConsensusParticipants = 2
+	cfg.Models.Exec = []config.ExecModelConfig{
+		mockModel(t, "alpha", "AGREE: beta, the diff is safe", "AGREE: beta"),
+		mockModel(t, "beta", "ADD: needs a test", "AGREE: beta, with the test"),
+		mockModel(t, "gamma", "OBJECT: it breaks the API", "AGREE: beta"),
+	}
+
+	tests := []struct {
+		name          string
+		opts          Options
+		wantConsensus bool
+		wantMessages  int
+	}{
+		{"single round", Options{}, false, 4},
+		{"consensus round", Options{ConsensusRound: true}, true, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RunDebate(context.Background(), cfg, "Is this diff safe to merge?", tt.opts)
+			if err != nil {
+				t.Fatalf("RunDebate() error = %v", err)
+			}
+			if got := result.Consensus.HasConsensus; got != tt.wantConsensus {
+				t.Errorf("HasConsensus = %v, want %v (%+v)", got, tt.wantConsensus, result.Consensus)
+			}
+			if len(result.Messages) != tt.wantMessages {
+				t.Errorf("got %d messages, want %d", len(result.Messages), tt.wantMessages)
+			}
+			if result.Messages[0].Source != "user" {
+				t.Errorf("first message should be the prompt, got %+v", result.Messages[0])
+			}
+			if len(result.Errors) != 0 {
+				t.Errorf("unexpected model errors: %v", result.Errors)
+			}
+		})
+	}
+
+	result, _ := RunDebate(context.Background(), cfg, "Is this diff safe to merge?", Options{})
+	if result.Consensus.ObjectCount != 1 || result.Consensus.AgreeCount != 1 || result.Consensus.AddCount != 1 {
+		t.Errorf("expected one agree, add and object, got %+v", result.Consensus)
+	}
+}
+
+func TestRunDebate_NoModels(t *testing.T) {
+	if _, err := RunDebate(context.Background(), &config.Config{}, "hello", Options{}); !errors.Is(err, ErrNoModels) {
+		t.Errorf("RunDebate() error = %v, want ErrNoModels", err)
+	}
+}
+
+func TestRunDebate_AllModelsFail(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Models.Exec = []config.ExecModelConfig{
+		{ID: "broken", Enabled: true, Command: filepath.Join(t.TempDir(), "missing")},
+	}
+
+	result, err := RunDebate(context.Background(), cfg, "hello", Options{})
+	if err == nil {
+		t.Fatal("expected an error when no model responds")
+	}
+	if result.Errors["broken"] == nil {
+		t.Errorf("expected the failure to be reported, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,61 @@
+// engine/replay.go
+package engine
+
+import (
+	"roundtable/internal/consensus"
+	"roundtable/internal/db"
+)
+
+// Store is Roundtable's debate database
+type Store = db.Store
+
+// ParsedPosition is a model's position as read from one response
+type ParsedPosition = consensus.ParsedPosition
+
+// OpenStore opens the debate database in Roundtable's data directory
+func OpenStore() (*Store, error) {
+	return db.Open()
+}
+
+// ReplayRound is the consensus analysis of one stored round
+type ReplayRound struct {
+	Number    int // 1-based
+	Prompt    string
+	Positions map[string]ParsedPosition // By model ID
+	Consensus ConsensusResult
+}
+
+// ReplayDebate re-runs consensus analysis on each round of a stored debate,
+// with the built-in keywords and no participation floor. Models aren't
+// called, so replaying the same debate always gives the same results.
+func ReplayDebate(store *Store, debateID string) ([]ReplayRound, error) {
+	if _, err := store.GetDebate(debateID); err != nil {
+		return nil, err
+	}
+	stored, err := store.GetMessages(debateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []consensus.Message
+	for _, msg := range stored {
+		// Drafts and stopped responses were cut off mid-stream
+		if msg.MsgType == db.MsgTypeDraft || msg.MsgType == db.MsgTypeStopped {
+			continue
+		}
+		messages = append(messages, consensus.Message{Source: msg.Source, Content: msg.Content})
+	}
+
+	parser := consensus.NewParser(consensus.ParserOptions{})
+	var replay []ReplayRound
+	for i, round := range consensus.SliceRounds(messages) {
+		positions := parser.RoundPositions(round)
+		replay = append(replay, ReplayRound{
+			Number:    i + 1,
+			Prompt:    round.Prompt,
+			Positions: positions,
+			Consensus: consensus.AnalyzeConsensus(positions),
+		})
+	}
+	return replay, nil
+}
@@ -0,0 +1,62 @@
+// engine/replay_test.go
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"roundtable/internal/consensus"
+	"roundtable/internal/db"
+)
+
+func TestReplayDebate(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("db.OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("d1", "Caching", "")
+	store.AddRoundMessage("d1", "user", "Which cache should we use?", "user", 1)
+	store.AddRoundMessage("d1", "claude", "AGREE: gpt, Redis works", "model", 1)
+	store.AddRoundMessage("d1", "gpt", "OBJECT: memcached is simpler", "model", 1)
+	store.AddRoundMessage("d1", "user", "Memcached has no persistence, does that matter?", "user", 2)
+	store.AddRoundMessage("d1", "claude", "AGREE: gpt, it doesn't matter", "model", 2)
+	store.AddRoundMessage("d1", "gpt", "AGREE: claude, go with memcached", "model", 2)
+	store.AddRoundMessage("d1", "system", "CONSENSUS REACHED: 2 models agree (no objections). Ready for execution.", "system", 2)
+	store.SaveDraft(0, "d1", "grok", "OBJECT: still stream", 2)
+
+	replay, err := ReplayDebate(store, "d1")
+	if err != nil {
+		t.Fatalf("ReplayDebate() failed: %v", err)
+	}
+	if len(replay) != 2 {
+		t.Fatalf("got %d rounds, want 2", len(replay))
+	}
+
+	first := replay[0]
+	if first.Number != 1 || first.Prompt != "Which cache should we use?" {
+		t.Errorf("round 1 = %d %q", first.Number, first.Prompt)
+	}
+	if first.Consensus.HasConsensus || first.Consensus.ObjectCount != 1 {
+		t.Errorf("round 1 consensus = %+v, want gpt's objection to block it", first.Consensus)
+	}
+
+	second := replay[1]
+	if _, ok := second.Positions["grok"]; ok {
+		t.Error("a draft shouldn't count as a position")
+	}
+	if !second.Consensus.HasConsensus || second.Positions["gpt"].Position != consensus.PositionAgree {
+		t.Errorf("round 2 consensus = %+v, want it reached", second.Consensus)
+	}
+
+	// Replays are deterministic
+	again, _ := ReplayDebate(store, "d1")
+	if !reflect.DeepEqual(again, replay) {
+		t.Error("replaying twice gave different results")
+	}
+
+	if _, err := ReplayDebate(store, "missing"); err == nil {
+		t.Error("ReplayDebate() of an unknown debate should fail")
+	}
+}
@@ -2,6 +2,8 @@
 package commands
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -27,6 +29,33 @@ type CloseDebate struct{}
 
 func (CloseDebate) Type() string { return "close" }
 
+// ListCommands lists the shell commands models have proposed
+type ListCommands struct{}
+
+func (ListCommands) Type() string { return "commands" }
+
+// DebugPaths shows where the config and debates are stored
+type DebugPaths struct{}
+
+func (DebugPaths) Type() string { return "debug_paths" }
+
+// Attach adds an image to send with the next prompt
+type Attach struct {
+	Path string
+}
+
+func (Attach) Type() string { return "attach" }
+
+// DebugDump writes the current debate's stored record to a JSON file
+type DebugDump struct{}
+
+func (DebugDump) Type() string { return "debug_dump" }
+
+// Reopen reopens the most recently closed debate
+type Reopen struct{}
+
+func (Reopen) Type() string { return "reopen" }
+
 // RenameDebate renames the current debate
 type RenameDebate struct {
 	Name string
@@ -48,26 +77,87 @@ type RemoveContext struct {
 
 func (RemoveContext) Type() string { return "context_remove" }
 
+// AddTag tags the current debate
+type AddTag struct {
+	Name string
+}
+
+func (AddTag) Type() string { return "tag_add" }
+
+// RemoveTag removes a tag from the current debate
+type RemoveTag struct {
+	Name string
+}
+
+func (RemoveTag) Type() string { return "tag_remove" }
+
 // ListContext lists all context files
 type ListContext struct{}
 
 func (ListContext) Type() string { return "context_list" }
 
+// RefreshContext re-reads every context file from disk
+type RefreshContext struct{}
+
+func (RefreshContext) Type() string { return "context_refresh" }
+
 // ToggleModels toggles model selection panel
 type ToggleModels struct{}
 
 func (ToggleModels) Type() string { return "models" }
 
-// ForceConsensus forces a consensus check
+// RefreshModels re-runs the model health checks
+type RefreshModels struct{}
+
+func (RefreshModels) Type() string { return "models_refresh" }
+
+// ForceConsensus forces a consensus check by re-polling every model
 type ForceConsensus struct{}
 
 func (ForceConsensus) Type() string { return "consensus" }
 
+// CheckConsensus re-analyzes the positions already given, without polling
+type CheckConsensus struct{}
+
+func (CheckConsensus) Type() string { return "consensus_check" }
+
 // Execute executes a tool or action
 type Execute struct{}
 
 func (Execute) Type() string { return "execute" }
 
+// SetExecutor picks the model /execute sends to; an empty Model shows the
+// current executor
+type SetExecutor struct {
+	Model string
+}
+
+func (SetExecutor) Type() string { return "executor" }
+
+// Retry sends the last round's prompt to the models again
+type Retry struct{}
+
+func (Retry) Type() string { return "retry" }
+
+// MaxTimeoutSeconds is the longest model timeout /timeout accepts
+const MaxTimeoutSeconds = 3600
+
+// SetTimeout changes how long models get to respond; zero shows the
+// current timeout
+type SetTimeout struct {
+	Seconds int
+}
+
+func (SetTimeout) Type() string { return "timeout" }
+
+// Fork copies the debate up to a message into a new debate tab. Index is
+// the 1-based message number; zero forks at the latest consensus.
+type Fork struct {
+	Index int
+}
+
+func (Fork) Type() string { return "fork" }
+
 // Pause pauses the current debate
 type Pause struct{}
 
@@ -79,15 +169,66 @@ type Resume struct{}
 func (Resume) Type() string { return "resume" }
 
 // ShowHistory shows debate history
-type ShowHistory struct{}
+type ShowHistory struct {
+	Tag string // Empty means all debates
+}
 
 func (ShowHistory) Type() string { return "history" }
 
 // Export exports the current debate
-type Export struct{}
+type Export struct {
+	Format string // "markdown" or "json"
+}
 
 func (Export) Type() string { return "export" }
 
+// ExportArchive writes every stored debate to a zip file
+type ExportArchive struct {
+	Path string
+}
+
+func (ExportArchive) Type() string { return "export_archive" }
+
+// Import loads a debate from a JSON export into a new tab
+type Import struct {
+	Path string
+}
+
+func (Import) Type() string { return "import" }
+
+// Reload re-reads the config file
+type Reload struct{}
+
+func (Reload) Type() string { return "reload" }
+
+// Preview shows the exact prompt that would be sent, without sending it
+type Preview struct {
+	Prompt string // Empty means preview the last user prompt
+}
+
+func (Preview) Type() string { return "preview" }
+
+// SetTheme switches the color theme
+type SetTheme struct {
+	Name string // Empty means list the available themes
+}
+
+func (SetTheme) Type() string { return "theme" }
+
+// FilterSource shows only one model's messages in the chat (plus user/system)
+type FilterSource struct {
+	Source string // Empty means show all sources
+}
+
+func (FilterSource) Type() string { return "only" }
+
+// Challenge asks one model to argue against the emerging consensus
+type Challenge struct {
+	Model string
+}
+
+func (Challenge) Type() string { return "challenge" }
+
 // ParseError represents a command parsing error
 type ParseError struct {
 	Message string
@@ -123,6 +264,9 @@ func Parse(input string) Command {
 	case "/close":
 		return CloseDebate{}
 
+	case "/reopen":
+		return Reopen{}
+
 	case "/rename":
 		name := strings.Join(args, " ")
 		if name == "" {
@@ -132,7 +276,7 @@ func Parse(input string) Command {
 
 	case "/context":
 		if len(args) == 0 {
-			return ParseError{Message: "/context requires a subcommand: add, remove, or list"}
+			return ParseError{Message: "/context requires a subcommand: add, remove, list, or refresh"}
 		}
 		subCmd := strings.ToLower(args[0])
 		subArgs := args[1:]
@@ -152,30 +296,178 @@ func Parse(input string) Command {
 			return RemoveContext{Path: path}
 		case "list":
 			return ListContext{}
+		case "refresh":
+			return RefreshContext{}
 		default:
 			return ParseError{Message: "unknown context subcommand: " + subCmd}
 		}
 
+	case "/commands":
+		return ListCommands{}
+
+	case "/attach":
+		if len(args) == 0 {
+			return ParseError{Message: "/attach requires a path, e.g. /attach screenshot.png"}
+		}
+		return Attach{Path: strings.Join(args, " ")}
+
+	case "/whereami":
+		return DebugPaths{}
+
+	case "/debug":
+		if len(args) == 0 {
+			return ParseError{Message: "/debug requires a subcommand: paths or dump"}
+		}
+		switch sub := strings.ToLower(args[0]); sub {
+		case "paths":
+			return DebugPaths{}
+		case "dump":
+			return DebugDump{}
+		default:
+			return ParseError{Message: "unknown debug subcommand: " + sub + " (use paths or dump)"}
+		}
+
+	case "/tag":
+		if len(args) == 0 {
+			return ParseError{Message: "/tag requires a subcommand: add or remove"}
+		}
+		subCmd := strings.ToLower(args[0])
+		subArgs := args[1:]
+
+		if subCmd != "add" && subCmd != "remove" {
+			return ParseError{Message: "unknown tag subcommand: " + subCmd}
+		}
+		if len(subArgs) == 0 {
+			return ParseError{Message: "/tag " + subCmd + " requires a name"}
+		}
+		if len(subArgs) > 1 {
+			return ParseError{Message: "tag names can't contain spaces"}
+		}
+		name := strings.ToLower(subArgs[0])
+		if subCmd == "add" {
+			return AddTag{Name: name}
+		}
+		return RemoveTag{Name: name}
+
 	case "/models":
-		return ToggleModels{}
+		if len(args) == 0 {
+			return ToggleModels{}
+		}
+		switch sub := strings.ToLower(args[0]); sub {
+		case "refresh":
+			return RefreshModels{}
+		default:
+			return ParseError{Message: "unknown models subcommand: " + sub + " (use refresh)"}
+		}
 
 	case "/consensus":
-		return ForceConsensus{}
+		if len(args) == 0 {
+			return ForceConsensus{}
+		}
+		switch sub := strings.ToLower(args[0]); sub {
+		case "poll":
+			return ForceConsensus{}
+		case "check":
+			return CheckConsensus{}
+		default:
+			return ParseError{Message: "unknown consensus subcommand: " + sub + " (use check or poll)"}
+		}
 
 	case "/execute":
 		return Execute{}
 
+	case "/executor":
+		switch len(args) {
+		case 0:
+			return SetExecutor{}
+		case 1:
+			return SetExecutor{Model: strings.ToLower(strings.TrimPrefix(args[0], "@"))}
+		}
+		return ParseError{Message: "/executor takes one model id, e.g. /executor claude"}
+
 	case "/pause":
 		return Pause{}
 
+	case "/retry":
+		return Retry{}
+
+	case "/timeout":
+		if len(args) == 0 {
+			return SetTimeout{}
+		}
+		seconds, err := strconv.Atoi(strings.TrimSuffix(args[0], "s"))
+		if err != nil || len(args) > 1 || seconds < 1 || seconds > MaxTimeoutSeconds {
+			return ParseError{Message: fmt.Sprintf("/timeout takes a number of seconds from 1 to %d, e.g. /timeout 120", MaxTimeoutSeconds)}
+		}
+		return SetTimeout{Seconds: seconds}
+
+	case "/fork":
+		if len(args) == 0 {
+			return Fork{}
+		}
+		index, err := strconv.Atoi(args[0])
+		if err != nil || len(args) > 1 || index < 1 {
+			return ParseError{Message: "/fork takes a message number, e.g. /fork 4"}
+		}
+		return Fork{Index: index}
+
 	case "/resume":
 		return Resume{}
 
 	case "/history":
-		return ShowHistory{}
+		if len(args) > 1 {
+			return ParseError{Message: "/history takes at most one tag"}
+		}
+		return ShowHistory{Tag: strings.ToLower(strings.Join(args, ""))}
 
 	case "/export":
-		return Export{}
+		if len(args) == 0 {
+			return Export{Format: "markdown"}
+		}
+		switch format := strings.ToLower(args[0]); format {
+		case "markdown", "md":
+			return Export{Format: "markdown"}
+		case "json":
+			return Export{Format: "json"}
+		case "archive":
+			if len(args) < 2 {
+				return ParseError{Message: "/export archive requires a path, e.g. ~/roundtable-backup.zip"}
+			}
+			return ExportArchive{Path: strings.Join(args[1:], " ")}
+		default:
+			return ParseError{Message: "unknown export format: " + format}
+		}
+
+	case "/import":
+		if len(args) == 0 {
+			return ParseError{Message: "/import requires a path to a JSON export"}
+		}
+		return Import{Path: strings.Join(args, " ")}
+
+	case "/reload":
+		return Reload{}
+
+	case "/preview":
+		return Preview{Prompt: strings.Join(args, " ")}
+
+	case "/theme":
+		return SetTheme{Name: strings.Join(args, " ")}
+
+	case "/only":
+		if len(args) == 0 {
+			return ParseError{Message: "/only requires a model id or 'all'"}
+		}
+		source := strings.ToLower(args[0])
+		if source == "all" {
+			source = ""
+		}
+		return FilterSource{Source: source}
+
+	case "/challenge":
+		if len(args) != 1 {
+			return ParseError{Message: "/challenge requires one model id, e.g. /challenge gpt"}
+		}
+		return Challenge{Model: strings.ToLower(strings.TrimPrefix(args[0], "@"))}
 
 	default:
 		return ParseError{Message: "unknown command: " + cmd}
@@ -188,15 +480,36 @@ func HelpText() string {
   /help                  - Show this help
   /new [name]            - Start a new debate
   /close                 - Close the current debate
+  /reopen                - Reopen the last closed debate
   /rename <name>         - Rename the current debate
   /context add <path>    - Add a file/directory as context
   /context remove <path> - Remove a context file/directory
   /context list          - List all context files
+  /context refresh       - Re-read context files that changed on disk
+  /tag add <name>        - Tag the current debate
+  /tag remove <name>     - Remove a tag from the current debate
   /models                - Toggle model selection panel
-  /consensus             - Force a consensus check
+  /models refresh        - Re-check which models are reachable
+  /consensus [poll]      - Ask every model for its position again
+  /consensus check       - Tally the positions already given
   /execute               - Execute the agreed-upon action
+  /executor [model]      - Show or change the model that executes
   /pause                 - Pause the current debate
   /resume                - Resume a paused debate
-  /history               - Show debate history
-  /export                - Export the current debate`
+  /retry                 - Send the last round's prompt again
+  /timeout [seconds]     - Show or change the model timeout
+  /fork [message]        - Branch the debate into a new tab
+  /history [tag]         - Show debate history, optionally only one tag
+  /export [json]         - Export the current debate (markdown by default)
+  /export archive <path> - Export every debate to a zip file
+  /import <path>         - Import a debate exported as JSON
+  /reload                - Reload the config file
+  /preview [prompt]      - Show the exact prompt models would receive
+  /theme [name]          - Switch color theme (no name lists themes)
+  /only <model>|all      - Show only one model's messages, or all
+  /challenge <model>     - Have a model argue against the consensus
+  /commands              - List shell commands models have proposed
+  /attach <path>         - Send an image with the next prompt
+  /whereami              - Show the config, data and database paths
+  /debug dump            - Write the debate's stored record to a JSON file`
 }
@@ -102,6 +102,117 @@ func TestParse_CloseDebate(t *testing.T) {
 	}
 }
 
+func TestParse_Reopen(t *testing.T) {
+	for _, input := range []string{"/reopen", "/REOPEN", "  /reopen  "} {
+		result := Parse(input)
+		if _, ok := result.(Reopen); !ok {
+			t.Errorf("Parse(%q) = %T, want Reopen", input, result)
+			continue
+		}
+		if result.Type() != "reopen" {
+			t.Errorf("Parse(%q).Type() = %q, want %q", input, result.Type(), "reopen")
+		}
+	}
+}
+
+func TestParse_ListCommands(t *testing.T) {
+	for _, input := range []string{"/commands", "/COMMANDS", "  /commands  "} {
+		if got := Parse(input); got != (ListCommands{}) {
+			t.Errorf("Parse(%q) = %#v, want ListCommands{}", input, got)
+		}
+	}
+}
+
+func TestParse_Retry(t *testing.T) {
+	for _, input := range []string{"/retry", "/RETRY", "  /retry  "} {
+		if got := Parse(input); got != (Retry{}) {
+			t.Errorf("Parse(%q) = %#v, want Retry{}", input, got)
+		}
+	}
+}
+
+func TestParse_Timeout(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Command
+	}{
+		{"/timeout", SetTimeout{}},
+		{"/timeout 120", SetTimeout{Seconds: 120}},
+		{"/timeout 90s", SetTimeout{Seconds: 90}},
+		{"/timeout 3600", SetTimeout{Seconds: 3600}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.input); got != tt.want {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"/timeout 0", "/timeout -5", "/timeout soon", "/timeout 3601", "/timeout 1.5", "/timeout 60 120"} {
+		if _, ok := Parse(input).(ParseError); !ok {
+			t.Errorf("Parse(%q) = %#v, want a ParseError", input, Parse(input))
+		}
+	}
+}
+
+func TestParse_Fork(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Command
+	}{
+		{"/fork", Fork{}},
+		{"/fork 4", Fork{Index: 4}},
+		{"/FORK 12", Fork{Index: 12}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.input); got != tt.want {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"/fork 0", "/fork -1", "/fork last", "/fork 2 3"} {
+		if _, ok := Parse(input).(ParseError); !ok {
+			t.Errorf("Parse(%q) = %#v, want a ParseError", input, Parse(input))
+		}
+	}
+}
+
+func TestParse_Attach(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Command
+	}{
+		{"/attach shot.png", Attach{Path: "shot.png"}},
+		{"/attach ~/My Pictures/shot.png", Attach{Path: "~/My Pictures/shot.png"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.input); got != tt.want {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+
+	if _, ok := Parse("/attach").(ParseError); !ok {
+		t.Error("Parse(\"/attach\") should be a ParseError")
+	}
+}
+
+func TestParse_Debug(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Command
+	}{
+		{"/whereami", DebugPaths{}},
+		{"/debug paths", DebugPaths{}},
+		{"/DEBUG Dump", DebugDump{}},
+		{"/debug", ParseError{Message: "/debug requires a subcommand: paths or dump"}},
+		{"/debug logs", ParseError{Message: "unknown debug subcommand: logs (use paths or dump)"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.input); got != tt.want {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestParse_RenameDebate(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -287,6 +398,15 @@ func TestParse_ContextList(t *testing.T) {
 	}
 }
 
+func TestParse_ContextRefresh(t *testing.T) {
+	for _, input := range []string{"/context refresh", "/CONTEXT Refresh"} {
+		result := Parse(input)
+		if _, ok := result.(RefreshContext); !ok {
+			t.Errorf("Parse(%q) = %T, want RefreshContext", input, result)
+		}
+	}
+}
+
 func TestParse_ContextNoSubcommand(t *testing.T) {
 	tests := []string{
 		"/context",
@@ -356,11 +476,31 @@ func TestParse_Models(t *testing.T) {
 	}
 }
 
+func TestParse_ModelsRefresh(t *testing.T) {
+	for _, input := range []string{"/models refresh", "/MODELS Refresh"} {
+		if _, ok := Parse(input).(RefreshModels); !ok {
+			t.Errorf("Parse(%q) = %T, want RefreshModels", input, Parse(input))
+		}
+	}
+	if _, ok := Parse("/models").(ToggleModels); !ok {
+		t.Errorf("Parse(%q) = %T, want ToggleModels", "/models", Parse("/models"))
+	}
+
+	pe, ok := Parse("/models reload").(ParseError)
+	if !ok {
+		t.Fatalf("Parse(%q) = %T, want ParseError", "/models reload", Parse("/models reload"))
+	}
+	if !strings.Contains(pe.Message, "unknown models subcommand") {
+		t.Errorf("ParseError.Message = %q, want it to name the unknown subcommand", pe.Message)
+	}
+}
+
 func TestParse_Consensus(t *testing.T) {
 	tests := []string{
 		"/consensus",
 		"/CONSENSUS",
 		"  /consensus  ",
+		"/consensus poll",
 	}
 
 	for _, input := range tests {
@@ -378,6 +518,22 @@ func TestParse_Consensus(t *testing.T) {
 	}
 }
 
+func TestParse_ConsensusCheck(t *testing.T) {
+	for _, input := range []string{"/consensus check", "/consensus CHECK"} {
+		if _, ok := Parse(input).(CheckConsensus); !ok {
+			t.Errorf("Parse(%q) = %T, want CheckConsensus", input, Parse(input))
+		}
+	}
+
+	pe, ok := Parse("/consensus vote").(ParseError)
+	if !ok {
+		t.Fatalf("Parse(%q) = %T, want ParseError", "/consensus vote", Parse("/consensus vote"))
+	}
+	if !strings.Contains(pe.Message, "unknown consensus subcommand") {
+		t.Errorf("ParseError.Message = %q, want it to name the unknown subcommand", pe.Message)
+	}
+}
+
 func TestParse_Execute(t *testing.T) {
 	tests := []string{
 		"/execute",
@@ -466,11 +622,69 @@ func TestParse_History(t *testing.T) {
 	}
 }
 
+func TestParse_HistoryTag(t *testing.T) {
+	result := Parse("/history Backend")
+	sh, ok := result.(ShowHistory)
+	if !ok {
+		t.Fatalf("Parse(%q) = %T, want ShowHistory", "/history Backend", result)
+	}
+	if sh.Tag != "backend" {
+		t.Errorf("Parse(%q).Tag = %q, want %q", "/history Backend", sh.Tag, "backend")
+	}
+
+	if _, ok := Parse("/history a b").(ParseError); !ok {
+		t.Errorf("Parse(%q) = %T, want ParseError", "/history a b", Parse("/history a b"))
+	}
+}
+
+func TestParse_Tag(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Command
+	}{
+		{"/tag add backend", AddTag{Name: "backend"}},
+		{"/TAG ADD Auth", AddTag{Name: "auth"}},
+		{"/tag remove backend", RemoveTag{Name: "backend"}},
+		{"  /tag remove  auth  ", RemoveTag{Name: "auth"}},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.input); got != tt.want {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParse_TagErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantMsg string
+	}{
+		{"/tag", "requires a subcommand"},
+		{"/tag rename x", "unknown tag subcommand"},
+		{"/tag add", "requires a name"},
+		{"/tag remove", "requires a name"},
+		{"/tag add two words", "can't contain spaces"},
+	}
+
+	for _, tt := range tests {
+		pe, ok := Parse(tt.input).(ParseError)
+		if !ok {
+			t.Errorf("Parse(%q) = %T, want ParseError", tt.input, Parse(tt.input))
+			continue
+		}
+		if !strings.Contains(pe.Message, tt.wantMsg) {
+			t.Errorf("Parse(%q).Message = %q, want message containing %q", tt.input, pe.Message, tt.wantMsg)
+		}
+	}
+}
+
 func TestParse_Export(t *testing.T) {
 	tests := []string{
 		"/export",
 		"/EXPORT",
 		"  /export  ",
+		"/export markdown",
 	}
 
 	for _, input := range tests {
@@ -486,6 +700,133 @@ func TestParse_Export(t *testing.T) {
 			t.Errorf("Parse(%q).Type() = %q, want %q", input, result.Type(), "export")
 		}
 	}
+
+	if got, want := Parse("/export JSON"), (Export{Format: "json"}); got != want {
+		t.Errorf("Parse(%q) = %#v, want %#v", "/export JSON", got, want)
+	}
+}
+
+func TestParse_Reload(t *testing.T) {
+	tests := []string{
+		"/reload",
+		"/RELOAD",
+		"  /reload  ",
+	}
+
+	for _, input := range tests {
+		result := Parse(input)
+		if _, ok := result.(Reload); !ok {
+			t.Errorf("Parse(%q) = %T, want Reload", input, result)
+		}
+	}
+}
+
+func TestParse_Preview(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantPrompt string
+	}{
+		{"/preview", ""},
+		{"/preview what about caching?", "what about caching?"},
+		{"/PREVIEW  spaced   out ", "spaced out"},
+	}
+
+	for _, tt := range tests {
+		result := Parse(tt.input)
+		p, ok := result.(Preview)
+		if !ok {
+			t.Errorf("Parse(%q) = %T, want Preview", tt.input, result)
+			continue
+		}
+		if p.Prompt != tt.wantPrompt {
+			t.Errorf("Parse(%q).Prompt = %q, want %q", tt.input, p.Prompt, tt.wantPrompt)
+		}
+	}
+}
+
+func TestParse_Theme(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantName string
+	}{
+		{"/theme", ""},
+		{"/theme light", "light"},
+		{"/THEME high-contrast", "high-contrast"},
+	}
+
+	for _, tt := range tests {
+		result := Parse(tt.input)
+		th, ok := result.(SetTheme)
+		if !ok {
+			t.Errorf("Parse(%q) = %T, want SetTheme", tt.input, result)
+			continue
+		}
+		if th.Name != tt.wantName {
+			t.Errorf("Parse(%q).Name = %q, want %q", tt.input, th.Name, tt.wantName)
+		}
+	}
+}
+
+func TestParse_Only(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantSource string
+	}{
+		{"/only claude", "claude"},
+		{"/only Gemini", "gemini"},
+		{"/only all", ""},
+		{"/ONLY ALL", ""},
+	}
+
+	for _, tt := range tests {
+		result := Parse(tt.input)
+		f, ok := result.(FilterSource)
+		if !ok {
+			t.Errorf("Parse(%q) = %T, want FilterSource", tt.input, result)
+			continue
+		}
+		if f.Source != tt.wantSource {
+			t.Errorf("Parse(%q).Source = %q, want %q", tt.input, f.Source, tt.wantSource)
+		}
+	}
+
+	if _, ok := Parse("/only").(ParseError); !ok {
+		t.Error("Parse(\"/only\") should require an argument")
+	}
+}
+
+func TestParse_Challenge(t *testing.T) {
+	for _, input := range []string{"/challenge gpt", "/CHALLENGE GPT", "/challenge @gpt"} {
+		if got, want := Parse(input), (Challenge{Model: "gpt"}); got != want {
+			t.Errorf("Parse(%q) = %#v, want %#v", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"/challenge", "/challenge gpt grok"} {
+		if _, ok := Parse(input).(ParseError); !ok {
+			t.Errorf("Parse(%q) = %T, want ParseError", input, Parse(input))
+		}
+	}
+}
+
+func TestParse_Executor(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Command
+	}{
+		{"/executor", SetExecutor{}},
+		{"/executor claude", SetExecutor{Model: "claude"}},
+		{"/EXECUTOR @GPT", SetExecutor{Model: "gpt"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.input); got != tt.want {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+
+	if _, ok := Parse("/executor claude gpt").(ParseError); !ok {
+		t.Errorf("Parse(%q) = %T, want ParseError", "/executor claude gpt", Parse("/executor claude gpt"))
+	}
 }
 
 func TestParse_UnknownCommand(t *testing.T) {
@@ -531,6 +872,30 @@ func TestParse_SlashOnly(t *testing.T) {
 	}
 }
 
+func TestParse_ExportArchive(t *testing.T) {
+	result := Parse("/export ARCHIVE ~/backups/roundtable.zip")
+	if got, want := result, (ExportArchive{Path: "~/backups/roundtable.zip"}); got != want {
+		t.Errorf("Parse() = %#v, want %#v", got, want)
+	}
+
+	for _, input := range []string{"/export archive", "/export pdf"} {
+		if _, ok := Parse(input).(ParseError); !ok {
+			t.Errorf("Parse(%q) = %T, want ParseError", input, Parse(input))
+		}
+	}
+}
+
+func TestParse_Import(t *testing.T) {
+	result := Parse("/import ~/backups/queue choice.json")
+	if got, want := result, (Import{Path: "~/backups/queue choice.json"}); got != want {
+		t.Errorf("Parse() = %#v, want %#v", got, want)
+	}
+
+	if _, ok := Parse("/import").(ParseError); !ok {
+		t.Errorf("Parse(%q) = %T, want ParseError", "/import", Parse("/import"))
+	}
+}
+
 func TestHelpText(t *testing.T) {
 	help := HelpText()
 
@@ -543,17 +908,38 @@ func TestHelpText(t *testing.T) {
 		"/help",
 		"/new",
 		"/close",
+		"/commands",
+		"/attach",
+		"/whereami",
+		"/debug dump",
+		"/reopen",
 		"/rename",
 		"/context add",
 		"/context remove",
 		"/context list",
+		"/context refresh",
+		"/tag add",
+		"/tag remove",
 		"/models",
+		"/models refresh",
 		"/consensus",
+		"/consensus check",
 		"/execute",
+		"/executor",
 		"/pause",
 		"/resume",
+		"/retry",
+		"/timeout",
+		"/fork",
 		"/history",
 		"/export",
+		"/export archive",
+		"/import",
+		"/reload",
+		"/preview",
+		"/theme",
+		"/only",
+		"/challenge",
 	}
 
 	for _, cmd := range expectedCommands {
@@ -572,17 +958,38 @@ func TestCommandTypes(t *testing.T) {
 		{Help{}, "help"},
 		{NewDebate{}, "new"},
 		{CloseDebate{}, "close"},
+		{Reopen{}, "reopen"},
+		{ListCommands{}, "commands"},
+		{Attach{}, "attach"},
+		{DebugPaths{}, "debug_paths"},
+		{DebugDump{}, "debug_dump"},
 		{RenameDebate{}, "rename"},
 		{AddContext{}, "context_add"},
 		{RemoveContext{}, "context_remove"},
 		{ListContext{}, "context_list"},
+		{RefreshContext{}, "context_refresh"},
+		{AddTag{}, "tag_add"},
+		{RemoveTag{}, "tag_remove"},
 		{ToggleModels{}, "models"},
+		{RefreshModels{}, "models_refresh"},
 		{ForceConsensus{}, "consensus"},
+		{CheckConsensus{}, "consensus_check"},
 		{Execute{}, "execute"},
+		{SetExecutor{}, "executor"},
 		{Pause{}, "pause"},
 		{Resume{}, "resume"},
+		{Retry{}, "retry"},
+		{SetTimeout{}, "timeout"},
+		{Fork{}, "fork"},
 		{ShowHistory{}, "history"},
 		{Export{}, "export"},
+		{ExportArchive{}, "export_archive"},
+		{Import{}, "import"},
+		{Reload{}, "reload"},
+		{Preview{}, "preview"},
+		{SetTheme{}, "theme"},
+		{FilterSource{}, "only"},
+		{Challenge{}, "challenge"},
 		{ParseError{}, "error"},
 	}
 
@@ -2,10 +2,16 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"roundtable/internal/consensus"
 )
 
 type ModelConfig struct {
@@ -13,14 +19,94 @@ type ModelConfig struct {
 	CLIPath      string `yaml:"cli_path,omitempty"`
 	APIKey       string `yaml:"api_key,omitempty"`
 	DefaultModel string `yaml:"default_model,omitempty"`
+	Color        string `yaml:"color,omitempty"`   // Display color, e.g. "#FF8800"
+	Stream       bool   `yaml:"stream,omitempty"`  // Claude only: show output as it's generated
+	Persona      string `yaml:"persona,omitempty"` // Role the model argues from, e.g. "security reviewer"
+}
+
+// ExecModelConfig configures an external model backend that speaks the
+// JSON stdin/stdout protocol (see models.ExecModel)
+type ExecModelConfig struct {
+	ID      string   `yaml:"id"`
+	Name    string   `yaml:"name,omitempty"`
+	Enabled bool     `yaml:"enabled"`
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args,omitempty"`
+	Color   string   `yaml:"color,omitempty"`
+	Persona string   `yaml:"persona,omitempty"`
+}
+
+// UIConfig holds layout preferences, some of which the UI saves back to the file
+type UIConfig struct {
+	// Side pane widths as a percentage of the terminal width
+	ContextWidthPct int `yaml:"context_width_pct"`
+	ModelsWidthPct  int `yaml:"models_width_pct"`
+
+	HideContext bool `yaml:"hide_context"`
+	HideModels  bool `yaml:"hide_models"`
+
+	// Color theme (dark, light, high-contrast) and per-color overrides
+	// such as accent: "#FF8800"
+	Theme  string            `yaml:"theme"`
+	Colors map[string]string `yaml:"colors,omitempty"`
+
+	// Model IDs in the order they're shown and queried, set by reordering
+	// the models pane; models not listed follow in the default order
+	ModelOrder []string `yaml:"model_order,omitempty"`
+}
+
+// ConsensusConfig tunes how model positions are detected in responses that
+// lack an explicit AGREE:/OBJECT:/ADD: marker
+type ConsensusConfig struct {
+	// Keywords added to the built-in sets
+	AgreeKeywords  []string `yaml:"agree_keywords,omitempty"`
+	ObjectKeywords []string `yaml:"object_keywords,omitempty"`
+	AddKeywords    []string `yaml:"add_keywords,omitempty"`
+
+	// Each keyword list given replaces its built-in set instead
+	ReplaceKeywords bool `yaml:"replace_keywords,omitempty"`
+
+	// Only explicit markers count
+	DisableKeywordFallback bool `yaml:"disable_keyword_fallback,omitempty"`
+}
+
+// ParserOptions converts the config into options for consensus.NewParser
+func (c ConsensusConfig) ParserOptions() consensus.ParserOptions {
+	return consensus.ParserOptions{
+		AgreeKeywords:          c.AgreeKeywords,
+		ObjectKeywords:         c.ObjectKeywords,
+		AddKeywords:            c.AddKeywords,
+		ReplaceKeywords:        c.ReplaceKeywords,
+		DisableKeywordFallback: c.DisableKeywordFallback,
+	}
+}
+
+// MetricsConfig controls the Prometheus metrics endpoint
+type MetricsConfig struct {
+	// Serve /metrics on this localhost port; 0 disables the endpoint
+	Port int `yaml:"port"`
+}
+
+// ExportConfig controls /export
+type ExportConfig struct {
+	// Leave context notes, such as context files added, out of exports
+	ExcludeContext bool `yaml:"exclude_context"`
+}
+
+// TranscriptConfig controls the live JSON Lines transcript
+type TranscriptConfig struct {
+	// Append every finalized message and consensus event to this file;
+	// empty disables the transcript
+	Path string `yaml:"path,omitempty"`
 }
 
 type Config struct {
 	Models struct {
-		Claude ModelConfig `yaml:"claude"`
-		Gemini ModelConfig `yaml:"gemini"`
-		GPT    ModelConfig `yaml:"gpt"`
-		Grok   ModelConfig `yaml:"grok"`
+		Claude ModelConfig       `yaml:"claude"`
+		Gemini ModelConfig       `yaml:"gemini"`
+		GPT    ModelConfig       `yaml:"gpt"`
+		Grok   ModelConfig       `yaml:"grok"`
+		Exec   []ExecModelConfig `yaml:"exec,omitempty"`
 	} `yaml:"models"`
 	Defaults struct {
 		AutoDebate       bool `yaml:"auto_debate"`
@@ -28,37 +114,132 @@ type Config struct {
 		ModelTimeout     int  `yaml:"model_timeout"`
 		RetryAttempts    int  `yaml:"retry_attempts"`
 		RetryDelay       int  `yaml:"retry_delay"` // milliseconds
+
+		// Minimum models that must take a position before consensus can be
+		// declared; 0 turns the floor off
+		MinConsensusParticipants int `yaml:"min_consensus_participants"`
+
+		// Maximum models queried at once; 0 means unlimited
+		MaxConcurrency int `yaml:"max_concurrency"`
+
+		// End a round once most models agree, stopping the ones still responding
+		StopOnConsensus bool `yaml:"stop_on_consensus"`
+
+		// After each round, have the moderator model sum up the responses
+		AutoSummarize bool   `yaml:"auto_summarize"`
+		Moderator     string `yaml:"moderator"`
+
+		// After this many rounds without consensus, ask every model for its
+		// position; 0 means only on /consensus
+		AutoConsensusAfterRounds int `yaml:"auto_consensus_after_rounds"`
+
+		// Most recent messages sent to models each round, besides the first
+		// user prompt and system messages; 0 means all
+		MaxHistoryMessages int `yaml:"max_history_messages"`
+
+		// Keep debates in memory only; nothing is written to the database
+		NoPersist bool `yaml:"no_persist"`
+
+		// Seconds between saving responses that are still streaming, so a
+		// crash loses at most this much of them
+		AutosaveInterval int `yaml:"autosave_interval"`
+
+		// Lowest level written to roundtable.log: debug, info, warn or error
+		LogLevel string `yaml:"log_level,omitempty"`
+
+		// Debate framing sent to every model, as a text/template with
+		// {{.ModelName}} and {{.OtherModels}}; empty means each model's built-in text
+		Preamble string `yaml:"preamble,omitempty"`
+
+		// Model /execute sends to; empty means the first enabled model that
+		// can execute tools
+		Executor string `yaml:"executor,omitempty"`
+
+		// File of standing project facts added, pinned, to every new
+		// debate's context
+		PreambleFile string `yaml:"preamble_file,omitempty"`
 	} `yaml:"defaults"`
-}
+	Consensus  ConsensusConfig  `yaml:"consensus"`
+	UI         UIConfig         `yaml:"ui"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Transcript TranscriptConfig `yaml:"transcript"`
+	Export     ExportConfig     `yaml:"export"`
 
-func Load() (*Config, error) {
-	configDir, err := os.UserConfigDir()
-	if err != nil {
-		configDir = os.ExpandEnv("$HOME/.config")
-	}
+	// Keys under "models" that don't name a known backend, recorded by Load for Validate
+	unknownModels []string
+}
 
-	path := filepath.Join(configDir, "roundtable", "config.yaml")
+// knownModelKeys are the valid keys under "models" in the config file
+var knownModelKeys = map[string]bool{
+	"claude": true,
+	"gemini": true,
+	"gpt":    true,
+	"grok":   true,
+	"exec":   true,
+}
 
-	data, err := os.ReadFile(path)
+func Load() (*Config, error) {
+	data, err := os.ReadFile(ConfigPath())
 	if err != nil {
 		// Return defaults if no config file
 		return defaultConfig(), nil
 	}
 
-	// Expand environment variables in config
-	expanded := os.ExpandEnv(string(data))
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	// Expand ${VAR} references in values, e.g. api_key: ${OPENAI_API_KEY}
+	unset := expandConfigEnv(&doc)
 
 	var cfg Config
-	if err := yaml.Unmarshal([]byte(expanded), &cfg); err != nil {
+	if len(doc.Content) > 0 {
+		if err := doc.Decode(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := unsetEnvErrors(&cfg, unset); err != nil {
 		return nil, err
 	}
 
+	// Record model keys the struct silently dropped so Validate can flag typos
+	var raw struct {
+		Models   map[string]yaml.Node `yaml:"models"`
+		Defaults map[string]yaml.Node `yaml:"defaults"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err == nil {
+		for id := range raw.Models {
+			if !knownModelKeys[id] {
+				cfg.unknownModels = append(cfg.unknownModels, id)
+			}
+		}
+		sort.Strings(cfg.unknownModels)
+	}
+
 	// Apply defaults for unset values
 	applyDefaults(&cfg)
 
+	// An explicit 0 turns the participation floor off, so only an unset
+	// value gets the default
+	if _, set := raw.Defaults["min_consensus_participants"]; !set {
+		cfg.Defaults.MinConsensusParticipants = 2
+	}
+
 	return &cfg, nil
 }
 
+// Default returns the configuration used when no config file exists
+func Default() *Config {
+	return defaultConfig()
+}
+
+// Reload re-reads the config file so changes take effect without a restart.
+// On error the caller should keep its current config.
+func Reload() (*Config, error) {
+	return Load()
+}
+
 func defaultConfig() *Config {
 	cfg := &Config{}
 	cfg.Models.Claude.Enabled = true
@@ -73,6 +254,12 @@ func defaultConfig() *Config {
 	cfg.Defaults.ModelTimeout = 60
 	cfg.Defaults.RetryAttempts = 3
 	cfg.Defaults.RetryDelay = 1000 // 1 second
+	cfg.Defaults.MinConsensusParticipants = 2
+	cfg.Defaults.Moderator = "claude"
+	cfg.Defaults.AutosaveInterval = 5
+	cfg.UI.ContextWidthPct = 20
+	cfg.UI.ModelsWidthPct = 12
+	cfg.UI.Theme = "dark"
 	return cfg
 }
 
@@ -95,6 +282,300 @@ func applyDefaults(cfg *Config) {
 	if cfg.Defaults.RetryDelay == 0 {
 		cfg.Defaults.RetryDelay = 1000
 	}
+	if cfg.Defaults.Moderator == "" {
+		cfg.Defaults.Moderator = "claude"
+	}
+	if cfg.Defaults.AutosaveInterval == 0 {
+		cfg.Defaults.AutosaveInterval = 5
+	}
+	if cfg.UI.ContextWidthPct == 0 {
+		cfg.UI.ContextWidthPct = 20
+	}
+	if cfg.UI.ModelsWidthPct == 0 {
+		cfg.UI.ModelsWidthPct = 12
+	}
+	if cfg.UI.Theme == "" {
+		cfg.UI.Theme = "dark"
+	}
+}
+
+// SaveUI writes cfg.UI to the config file, leaving the rest of the file as is
+// so ${VAR} references aren't replaced with their values. Only the ui
+// settings that changed are rewritten, so hand-edited layout and comments
+// survive. Creates the file from the defaults if it doesn't exist.
+func SaveUI(cfg *Config) error {
+	path := ConfigPath()
+
+	var ui yaml.Node
+	if err := ui.Encode(cfg.UI); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		data, err = replaceUI(path, data, &ui)
+		if err != nil {
+			return err
+		}
+	case os.IsNotExist(err):
+		base := defaultConfig()
+		base.UI = cfg.UI
+		if data, err = yaml.Marshal(base); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// lineEdit replaces lines [start, end) of a file with text
+type lineEdit struct {
+	start, end int
+	text       []string
+}
+
+// replaceUI returns the config file data with its ui section set to ui
+func replaceUI(path string, data []byte, ui *yaml.Node) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	if len(doc.Content) == 0 {
+		// Empty or comment-only file
+		return appendUI(lines, ui)
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: top level is not a mapping", path)
+	}
+	i := mapKeyIndex(root, "ui")
+	if i < 0 {
+		return appendUI(lines, ui)
+	}
+	key, section := root.Content[i], root.Content[i+1]
+
+	// The section runs to the next top-level key, less the blank lines and
+	// comments leading into it
+	start, end := key.Line-1, len(lines)
+	if i+2 < len(root.Content) {
+		end = root.Content[i+2].Line - 1
+	}
+	end = trimTrailing(lines, start, end)
+
+	var edits []lineEdit
+	if section.Kind != yaml.MappingNode || section.Style&yaml.FlowStyle != 0 || len(section.Content) == 0 {
+		// Nothing to edit in place; write the whole section
+		text, err := encodeLines(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "ui"}, ui,
+		}}, "", 2)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, lineEdit{start, end, text})
+	} else {
+		var err error
+		if edits, err = sectionEdits(lines, section, ui, section.Content[0].Column-key.Column, end); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply from the bottom up so earlier line numbers stay valid
+	slices.SortStableFunc(edits, func(a, b lineEdit) int { return b.start - a.start })
+	for _, e := range edits {
+		lines = slices.Concat(lines[:e.start], e.text, lines[e.end:])
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// sectionEdits returns the line edits that set a block mapping section to
+// ui. A changed plain value is edited where it stands, other changed
+// settings are rewritten, and new ones are added at the end of the section.
+func sectionEdits(lines []string, section, ui *yaml.Node, indent, end int) ([]lineEdit, error) {
+	prefix := strings.Repeat(" ", section.Content[0].Column-1)
+	if indent <= 0 {
+		indent = 2
+	}
+
+	var edits []lineEdit
+	added := &yaml.Node{Kind: yaml.MappingNode}
+	for j := 0; j+1 < len(ui.Content); j += 2 {
+		key, value := ui.Content[j], ui.Content[j+1]
+		k := mapKeyIndex(section, key.Value)
+		if k < 0 {
+			added.Content = append(added.Content, key, value)
+			continue
+		}
+		old := section.Content[k+1]
+		if sameYAML(old, value) {
+			continue
+		}
+
+		// A plain one-line value is swapped in place, keeping its comment
+		line, col := old.Line-1, old.Column-1
+		if old.Kind == yaml.ScalarNode && value.Kind == yaml.ScalarNode && old.Style == 0 && value.Style == 0 &&
+			!strings.Contains(value.Value, "\n") && strings.HasPrefix(lines[line][col:], old.Value) {
+			edits = append(edits, lineEdit{line, line + 1, []string{lines[line][:col] + value.Value + lines[line][col+len(old.Value):]}})
+			continue
+		}
+
+		value.LineComment = old.LineComment
+		if old.Kind == value.Kind {
+			value.Style = old.Style
+		}
+		entryEnd := end
+		if k+2 < len(section.Content) {
+			entryEnd = section.Content[k+2].Line - 1
+		}
+		text, err := encodeLines(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, value}}, prefix, indent)
+		if err != nil {
+			return nil, err
+		}
+		start := section.Content[k].Line - 1
+		edits = append(edits, lineEdit{start, trimTrailing(lines, start, entryEnd), text})
+	}
+
+	if len(added.Content) > 0 {
+		text, err := encodeLines(added, prefix, indent)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, lineEdit{end, end, text})
+	}
+	return edits, nil
+}
+
+// trimTrailing moves end back past blank and comment-only lines, stopping
+// short of start's line
+func trimTrailing(lines []string, start, end int) int {
+	for end > start+1 {
+		line := strings.TrimSpace(lines[end-1])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			break
+		}
+		end--
+	}
+	return end
+}
+
+// appendUI returns the file's lines with a ui section added at the end
+func appendUI(lines []string, ui *yaml.Node) ([]byte, error) {
+	text, err := encodeLines(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "ui"}, ui,
+	}}, "", 2)
+	if err != nil {
+		return nil, err
+	}
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	}
+	return []byte(strings.Join(append(lines, text...), "\n") + "\n"), nil
+}
+
+// encodeLines marshals node with the given indentation, returning its
+// lines each led by prefix
+func encodeLines(node *yaml.Node, prefix string, indent int) ([]string, error) {
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(indent)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+	return lines, nil
+}
+
+// sameYAML reports whether two nodes hold the same data, ignoring style,
+// position and comments
+func sameYAML(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !sameYAML(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// mapKeyIndex returns the index of key in a YAML mapping node's content, or
+// -1 if it's absent
+func mapKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// ModelColors returns the colors configured for models, keyed by model ID
+func (cfg *Config) ModelColors() map[string]string {
+	colors := make(map[string]string)
+	builtin := map[string]ModelConfig{
+		"claude": cfg.Models.Claude,
+		"gemini": cfg.Models.Gemini,
+		"gpt":    cfg.Models.GPT,
+		"grok":   cfg.Models.Grok,
+	}
+	for id, mc := range builtin {
+		if mc.Color != "" {
+			colors[id] = mc.Color
+		}
+	}
+	for _, ec := range cfg.Models.Exec {
+		if ec.ID != "" && ec.Color != "" {
+			colors[ec.ID] = ec.Color
+		}
+	}
+	return colors
+}
+
+// PreambleFilePath returns defaults.preamble_file with a leading "~/"
+// expanded, or "" if it isn't set
+func (cfg *Config) PreambleFilePath() string {
+	path := cfg.Defaults.PreambleFile
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// ModelPersonas returns the personas configured for models, keyed by model ID
+func (cfg *Config) ModelPersonas() map[string]string {
+	personas := make(map[string]string)
+	builtin := map[string]ModelConfig{
+		"claude": cfg.Models.Claude,
+		"gemini": cfg.Models.Gemini,
+		"gpt":    cfg.Models.GPT,
+		"grok":   cfg.Models.Grok,
+	}
+	for id, mc := range builtin {
+		if mc.Persona != "" {
+			personas[id] = mc.Persona
+		}
+	}
+	for _, ec := range cfg.Models.Exec {
+		if ec.ID != "" && ec.Persona != "" {
+			personas[ec.ID] = ec.Persona
+		}
+	}
+	return personas
 }
 
 func ConfigPath() string {
@@ -2,6 +2,10 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -28,3 +32,209 @@ func TestLoad(t *testing.T) {
 		t.Fatal("Load() returned nil config")
 	}
 }
+
+func TestLoad_MinConsensusParticipants(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{"unset gets the default", "defaults:\n  model_timeout: 30\n", 2},
+		{"explicit 0 turns the floor off", "defaults:\n  min_consensus_participants: 0\n", 0},
+		{"explicit value", "defaults:\n  min_consensus_participants: 3\n", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			if got := cfg.Defaults.MinConsensusParticipants; got != tt.want {
+				t.Errorf("MinConsensusParticipants = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReload_PicksUpChangedTimeout(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(timeout int) {
+		t.Helper()
+		data := fmt.Sprintf("defaults:\n  model_timeout: %d\n", timeout)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(30)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Defaults.ModelTimeout != 30 {
+		t.Fatalf("ModelTimeout = %d, want 30", cfg.Defaults.ModelTimeout)
+	}
+
+	write(90)
+	cfg, err = Reload()
+	if err != nil {
+		t.Fatalf("Reload() failed: %v", err)
+	}
+	if cfg.Defaults.ModelTimeout != 90 {
+		t.Errorf("ModelTimeout after reload = %d, want 90", cfg.Defaults.ModelTimeout)
+	}
+}
+
+func TestReload_InvalidYAML(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("defaults: [unclosed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Reload(); err == nil {
+		t.Error("Reload() should fail on invalid YAML")
+	}
+}
+
+func TestSaveUI_PreservesRestOfFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TEST_GPT_KEY", "sk-secret")
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := "models:\n  gpt:\n    enabled: true\n    api_key: ${TEST_GPT_KEY}\nui:\n  context_width_pct: 25\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	cfg.UI.HideModels = true
+	if err := SaveUI(cfg); err != nil {
+		t.Fatalf("SaveUI() failed: %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(saved), "${TEST_GPT_KEY}") || strings.Contains(string(saved), "sk-secret") {
+		t.Errorf("SaveUI should keep env references unexpanded, got:\n%s", saved)
+	}
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() after SaveUI failed: %v", err)
+	}
+	if !cfg.UI.HideModels || cfg.UI.ContextWidthPct != 25 {
+		t.Errorf("UI = %+v, want HideModels and ContextWidthPct 25", cfg.UI)
+	}
+	if cfg.Models.GPT.APIKey != "sk-secret" {
+		t.Errorf("GPT api key = %q, want it to still expand", cfg.Models.GPT.APIKey)
+	}
+}
+
+func TestSaveUI_KeepsLayout(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	head := "# My config\nmodels:\n    claude:\n        enabled: true\n\n"
+	tail := "\n# Defaults below\ndefaults:\n    max_rounds: 4\n"
+	data := head +
+		"ui:\n" +
+		"    context_width_pct: 25     # wide\n" +
+		"    models_width_pct: 12\n" +
+		"    hide_context: false\n" +
+		"    hide_models: false\n" +
+		"    theme: dark\n" +
+		"    model_order: [claude, gemini]\n" +
+		tail
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func() string {
+		saved, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(saved)
+	}
+
+	// Toggling a pane edits just that line
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	cfg.UI.HideContext = true
+	if err := SaveUI(cfg); err != nil {
+		t.Fatalf("SaveUI() failed: %v", err)
+	}
+	if want := strings.Replace(data, "hide_context: false", "hide_context: true", 1); read() != want {
+		t.Errorf("SaveUI changed more than hide_context:\n%s", read())
+	}
+
+	// Reordering rewrites only the model_order line, keeping its flow style
+	cfg.UI.ModelOrder = []string{"gemini", "claude"}
+	if err := SaveUI(cfg); err != nil {
+		t.Fatalf("SaveUI() failed: %v", err)
+	}
+	want := strings.NewReplacer("hide_context: false", "hide_context: true", "[claude, gemini]", "[gemini, claude]").Replace(data)
+	if read() != want {
+		t.Errorf("SaveUI changed more than model_order:\n%s", read())
+	}
+
+	// A new setting is added at the end of the ui section
+	cfg.UI.Colors = map[string]string{"accent": "#FF8800"}
+	if err := SaveUI(cfg); err != nil {
+		t.Fatalf("SaveUI() failed: %v", err)
+	}
+	want = strings.Replace(want, tail, "    colors:\n        accent: '#FF8800'\n"+tail, 1)
+	if read() != want {
+		t.Errorf("SaveUI didn't just add colors:\n%s", read())
+	}
+}
+
+func TestSaveUI_CreatesFileFromDefaults(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg := Default()
+	cfg.UI.HideContext = true
+	if err := SaveUI(cfg); err != nil {
+		t.Fatalf("SaveUI() failed: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !loaded.UI.HideContext {
+		t.Error("expected HideContext to be saved")
+	}
+	if !loaded.Models.Claude.Enabled {
+		t.Error("a newly created config should keep the default models enabled")
+	}
+}
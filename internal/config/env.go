@@ -0,0 +1,114 @@
+// internal/config/env.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// expandEnv replaces ${VAR} and $VAR in s with their environment values.
+// Unset variables expand to "" and are reported in the error.
+func expandEnv(s string) (string, error) {
+	var unset []string
+	expanded := os.Expand(s, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			unset = append(unset, name)
+		}
+		return value
+	})
+	if len(unset) > 0 {
+		return expanded, fmt.Errorf("environment variable %s is not set", strings.Join(unset, ", "))
+	}
+	return expanded, nil
+}
+
+// unsetEnv is a config value that referenced an unset variable
+type unsetEnv struct {
+	model string // e.g. "models.gpt" or "models.exec[0]"; "" outside models
+	err   error
+}
+
+// expandConfigEnv expands environment variables in every scalar value of
+// the parsed config file, before it's decoded, so any setting can use them,
+// numbers included. It returns the unset variables found.
+func expandConfigEnv(doc *yaml.Node) []unsetEnv {
+	var unset []unsetEnv
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, path)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				if path != "" {
+					key = path + "." + key
+				}
+				walk(node.Content[i+1], key)
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				walk(child, fmt.Sprintf("%s[%d]", path, i))
+			}
+		case yaml.ScalarNode:
+			expanded, err := expandEnv(node.Value)
+			if expanded == node.Value {
+				return
+			}
+			node.Value = expanded
+			if node.Style == 0 {
+				// Resolve the type again, so "${TIMEOUT}" can be a number
+				node.Tag = ""
+			}
+			if err != nil {
+				unset = append(unset, unsetEnv{modelPath(path), fmt.Errorf("%s: %w", path, err)})
+			}
+		}
+	}
+	walk(doc, "")
+	return unset
+}
+
+// modelPath returns the model a config path belongs to, e.g. "models.gpt"
+// for "models.gpt.api_key", or "" for a path outside models
+func modelPath(path string) string {
+	rest, ok := strings.CutPrefix(path, "models.")
+	if !ok {
+		return ""
+	}
+	if strings.HasPrefix(rest, "exec[") {
+		index, _, _ := strings.Cut(rest, "]")
+		return "models." + index + "]"
+	}
+	id, _, _ := strings.Cut(rest, ".")
+	return "models." + id
+}
+
+// unsetEnvErrors reports the unset variables of enabled models. Anywhere
+// else an unset variable expands to "", as it always has.
+func unsetEnvErrors(cfg *Config, unset []unsetEnv) error {
+	enabled := map[string]bool{
+		"models.claude": cfg.Models.Claude.Enabled,
+		"models.gemini": cfg.Models.Gemini.Enabled,
+		"models.gpt":    cfg.Models.GPT.Enabled,
+		"models.grok":   cfg.Models.Grok.Enabled,
+	}
+	for i, ec := range cfg.Models.Exec {
+		enabled[fmt.Sprintf("models.exec[%d]", i)] = ec.Enabled
+	}
+
+	var errs []error
+	for _, u := range unset {
+		if enabled[u.model] {
+			errs = append(errs, u.err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+// internal/config/env_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("RT_TEST_KEY", "sk-secret")
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"set", "${RT_TEST_KEY}", "sk-secret", ""},
+		{"set without braces", "Bearer $RT_TEST_KEY", "Bearer sk-secret", ""},
+		{"unset", "${RT_TEST_MISSING}", "", "RT_TEST_MISSING is not set"},
+		{"literal", "sk-plain-key", "sk-plain-key", ""},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnv(tt.in)
+			if got != tt.want {
+				t.Errorf("expandEnv(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("expandEnv(%q) error = %v", tt.in, err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("expandEnv(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_ExpandsEnv(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("RT_TEST_KEY", "sk-secret")
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("models:\n  gpt:\n    enabled: true\n    api_key: ${RT_TEST_KEY}\n")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Models.GPT.APIKey != "sk-secret" {
+		t.Errorf("APIKey = %q, want sk-secret", cfg.Models.GPT.APIKey)
+	}
+
+	// Unset for an enabled model: Load names the field and the variable
+	write("models:\n  gpt:\n    enabled: true\n    api_key: ${RT_TEST_MISSING}\n")
+	_, err = Load()
+	if err == nil || !strings.Contains(err.Error(), "models.gpt.api_key: environment variable RT_TEST_MISSING is not set") {
+		t.Errorf("Load() error = %v, want the unset variable reported", err)
+	}
+
+	// Disabled models don't need their variables
+	write("models:\n  grok:\n    enabled: false\n    api_key: ${RT_TEST_MISSING}\n")
+	if _, err := Load(); err != nil {
+		t.Errorf("Load() failed for a disabled model: %v", err)
+	}
+
+	// Any setting can use variables, including ones added later
+	t.Setenv("RT_TEST_PERSONA", "security reviewer")
+	t.Setenv("RT_TEST_LEVEL", "debug")
+	write("models:\n  claude:\n    enabled: true\n    persona: ${RT_TEST_PERSONA}\ndefaults:\n  log_level: ${RT_TEST_LEVEL}\n")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Models.Claude.Persona != "security reviewer" || cfg.Defaults.LogLevel != "debug" {
+		t.Errorf("persona = %q, log_level = %q; want both expanded", cfg.Models.Claude.Persona, cfg.Defaults.LogLevel)
+	}
+
+	// Unquoted numbers expand to numbers; quoted values stay strings
+	t.Setenv("RT_TEST_TIMEOUT", "90")
+	write("defaults:\n  model_timeout: ${RT_TEST_TIMEOUT}\n  moderator: \"${RT_TEST_TIMEOUT}\"\n")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed for a numeric variable: %v", err)
+	}
+	if cfg.Defaults.ModelTimeout != 90 || cfg.Defaults.Moderator != "90" {
+		t.Errorf("model_timeout = %d, moderator = %q; want 90 and \"90\"", cfg.Defaults.ModelTimeout, cfg.Defaults.Moderator)
+	}
+
+	// An unset variable in an enabled exec model is named by its index
+	write("models:\n  exec:\n    - id: local\n      enabled: true\n      command: run\n      args: [\"--key\", \"${RT_TEST_MISSING}\"]\n")
+	_, err = Load()
+	if err == nil || !strings.Contains(err.Error(), "models.exec[0].args[1]: environment variable RT_TEST_MISSING is not set") {
+		t.Errorf("Load() error = %v, want the exec model's unset variable reported", err)
+	}
+}
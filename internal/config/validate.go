@@ -0,0 +1,209 @@
+// internal/config/validate.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	ctxloader "roundtable/internal/context"
+	"roundtable/internal/logging"
+)
+
+// Validate checks a loaded config for mistakes that would otherwise surface
+// as missing models or confusing runtime errors. Returns nil if the config is usable.
+func Validate(cfg *Config) []error {
+	var errs []error
+
+	for _, id := range cfg.unknownModels {
+		errs = append(errs, fmt.Errorf("models.%s: unknown model (expected claude, gemini, gpt, grok or exec)", id))
+	}
+
+	// HTTP models are silently skipped without a key, usually because an
+	// environment variable referenced in the config isn't set
+	if cfg.Models.GPT.Enabled && cfg.Models.GPT.APIKey == "" {
+		errs = append(errs, fmt.Errorf("models.gpt: enabled but api_key is empty"))
+	}
+	if cfg.Models.Grok.Enabled && cfg.Models.Grok.APIKey == "" {
+		errs = append(errs, fmt.Errorf("models.grok: enabled but api_key is empty"))
+	}
+
+	seen := map[string]bool{"claude": true, "gemini": true, "gpt": true, "grok": true}
+	for i, ec := range cfg.Models.Exec {
+		switch {
+		case ec.ID == "":
+			errs = append(errs, fmt.Errorf("models.exec[%d]: id is required", i))
+		case seen[ec.ID]:
+			errs = append(errs, fmt.Errorf("models.exec[%d]: duplicate model id %q", i, ec.ID))
+		}
+		if ec.ID != "" {
+			seen[ec.ID] = true
+		}
+		if ec.Enabled && ec.Command == "" {
+			errs = append(errs, fmt.Errorf("models.exec[%d]: command is required", i))
+		}
+	}
+
+	errs = append(errs, validateColors("models.%s.color", cfg.ModelColors())...)
+	errs = append(errs, validateColors("ui.colors.%s", cfg.UI.Colors)...)
+
+	if !anyModelEnabled(cfg) {
+		errs = append(errs, fmt.Errorf("models: no models are enabled"))
+	}
+
+	d := cfg.Defaults
+	if d.ModelTimeout < 0 {
+		errs = append(errs, fmt.Errorf("defaults.model_timeout: must be positive, got %d", d.ModelTimeout))
+	}
+	if d.ConsensusTimeout < 0 {
+		errs = append(errs, fmt.Errorf("defaults.consensus_timeout: must be positive, got %d", d.ConsensusTimeout))
+	}
+	if d.RetryAttempts < 0 {
+		errs = append(errs, fmt.Errorf("defaults.retry_attempts: must not be negative, got %d", d.RetryAttempts))
+	}
+	if d.RetryDelay < 0 {
+		errs = append(errs, fmt.Errorf("defaults.retry_delay: must not be negative, got %d", d.RetryDelay))
+	}
+	if d.MinConsensusParticipants < 0 {
+		errs = append(errs, fmt.Errorf("defaults.min_consensus_participants: must not be negative, got %d", d.MinConsensusParticipants))
+	}
+	if d.MaxConcurrency < 0 {
+		errs = append(errs, fmt.Errorf("defaults.max_concurrency: must not be negative, got %d", d.MaxConcurrency))
+	}
+	if d.AutosaveInterval < 0 {
+		errs = append(errs, fmt.Errorf("defaults.autosave_interval: must not be negative, got %d", d.AutosaveInterval))
+	}
+	if d.AutoConsensusAfterRounds < 0 {
+		errs = append(errs, fmt.Errorf("defaults.auto_consensus_after_rounds: must not be negative, got %d", d.AutoConsensusAfterRounds))
+	}
+	if d.MaxHistoryMessages < 0 {
+		errs = append(errs, fmt.Errorf("defaults.max_history_messages: must not be negative, got %d", d.MaxHistoryMessages))
+	}
+	if _, err := logging.ParseLevel(d.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.log_level: %w", err))
+	}
+	if err := validatePreamble(d.Preamble); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.preamble: %w", err))
+	}
+	if err := validatePreambleFile(cfg.PreambleFilePath()); err != nil {
+		errs = append(errs, fmt.Errorf("defaults.preamble_file: %w", err))
+	}
+
+	c := cfg.Consensus
+	errs = append(errs, validateKeywords("consensus.agree_keywords", c.AgreeKeywords)...)
+	errs = append(errs, validateKeywords("consensus.object_keywords", c.ObjectKeywords)...)
+	errs = append(errs, validateKeywords("consensus.add_keywords", c.AddKeywords)...)
+
+	ui := cfg.UI
+	if ui.ContextWidthPct < 0 || ui.ContextWidthPct > 100 {
+		errs = append(errs, fmt.Errorf("ui.context_width_pct: must be between 0 and 100, got %d", ui.ContextWidthPct))
+	}
+	if ui.ModelsWidthPct < 0 || ui.ModelsWidthPct > 100 {
+		errs = append(errs, fmt.Errorf("ui.models_width_pct: must be between 0 and 100, got %d", ui.ModelsWidthPct))
+	}
+	if ui.ContextWidthPct+ui.ModelsWidthPct >= 100 {
+		errs = append(errs, fmt.Errorf("ui: context_width_pct + models_width_pct must be under 100, got %d", ui.ContextWidthPct+ui.ModelsWidthPct))
+	}
+
+	if p := cfg.Metrics.Port; p < 0 || p > 65535 {
+		errs = append(errs, fmt.Errorf("metrics.port: must be between 0 and 65535, got %d", p))
+	}
+
+	return errs
+}
+
+// validateKeywords flags blank keywords, which would match every response
+func validateKeywords(key string, keywords []string) []error {
+	var errs []error
+	for i, kw := range keywords {
+		if strings.TrimSpace(kw) == "" {
+			errs = append(errs, fmt.Errorf("%s[%d]: must not be blank", key, i))
+		}
+	}
+	return errs
+}
+
+// anyModelEnabled reports whether at least one model is enabled
+func anyModelEnabled(cfg *Config) bool {
+	m := cfg.Models
+	if m.Claude.Enabled || m.Gemini.Enabled || m.GPT.Enabled || m.Grok.Enabled {
+		return true
+	}
+	for _, ec := range m.Exec {
+		if ec.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePreamble checks that a preamble template parses and only uses the
+// fields models.PreambleData provides
+func validatePreamble(tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	t, err := template.New("preamble").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	sample := struct{ ModelName, OtherModels string }{"Claude", "Gemini, GPT"}
+	return t.Execute(io.Discard, sample)
+}
+
+// validatePreambleFile checks that a preamble file exists and is small
+// enough for the context loader
+func validatePreambleFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s does not exist", path)
+	} else if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > ctxloader.MaxFileSize {
+		return fmt.Errorf("%s is too large (%d bytes, max %d)", path, info.Size(), ctxloader.MaxFileSize)
+	}
+	return nil
+}
+
+// validateColors checks each color in a map, naming bad ones with keyFormat
+func validateColors(keyFormat string, colors map[string]string) []error {
+	keys := make([]string, 0, len(colors))
+	for k := range colors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, k := range keys {
+		if !validColor(colors[k]) {
+			errs = append(errs, fmt.Errorf(keyFormat+": %q is not a hex color (#RGB or #RRGGBB) or ANSI color number", k, colors[k]))
+		}
+	}
+	return errs
+}
+
+// validColor reports whether s is a color lipgloss understands: #RGB, #RRGGBB
+// or an ANSI color number 0-255
+func validColor(s string) bool {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n >= 0 && n <= 255
+	}
+	if !strings.HasPrefix(s, "#") || (len(s) != 4 && len(s) != 7) {
+		return false
+	}
+	_, err := strconv.ParseUint(s[1:], 16, 32)
+	return err == nil
+}
@@ -0,0 +1,185 @@
+// internal/config/validate_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_DefaultConfigIsValid(t *testing.T) {
+	if errs := Validate(defaultConfig()); len(errs) != 0 {
+		t.Errorf("default config should be valid, got %v", errs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   []string
+	}{
+		{
+			name: "enabled GPT without key",
+			modify: func(cfg *Config) {
+				cfg.Models.GPT.Enabled = true
+			},
+			want: []string{"models.gpt: enabled but api_key is empty"},
+		},
+		{
+			name: "negative timeout",
+			modify: func(cfg *Config) {
+				cfg.Defaults.ModelTimeout = -5
+			},
+			want: []string{"defaults.model_timeout: must be positive"},
+		},
+		{
+			name: "preamble uses an unknown field",
+			modify: func(cfg *Config) {
+				cfg.Defaults.Preamble = "You are {{.Name}}."
+			},
+			want: []string{"defaults.preamble:"},
+		},
+		{
+			name: "metrics port out of range",
+			modify: func(cfg *Config) {
+				cfg.Metrics.Port = 70000
+			},
+			want: []string{"metrics.port: must be between 0 and 65535, got 70000"},
+		},
+		{
+			name: "unknown log level",
+			modify: func(cfg *Config) {
+				cfg.Defaults.LogLevel = "verbose"
+			},
+			want: []string{`defaults.log_level: unknown log level "verbose"`},
+		},
+		{
+			name: "blank consensus keyword",
+			modify: func(cfg *Config) {
+				cfg.Consensus.ObjectKeywords = []string{"i object", " "}
+			},
+			want: []string{"consensus.object_keywords[1]: must not be blank"},
+		},
+		{
+			name: "no models enabled",
+			modify: func(cfg *Config) {
+				cfg.Models.Claude.Enabled = false
+				cfg.Models.Gemini.Enabled = false
+			},
+			want: []string{"no models are enabled"},
+		},
+		{
+			name: "pane widths leave no room for chat",
+			modify: func(cfg *Config) {
+				cfg.UI.ContextWidthPct = 60
+				cfg.UI.ModelsWidthPct = 40
+			},
+			want: []string{"ui: context_width_pct + models_width_pct must be under 100"},
+		},
+		{
+			name: "bad colors",
+			modify: func(cfg *Config) {
+				cfg.Models.Claude.Color = "orange"
+				cfg.Models.Gemini.Color = "#FA0"
+				cfg.UI.Colors = map[string]string{"accent": "#GGGGGG", "dim": "240"}
+			},
+			want: []string{
+				`models.claude.color: "orange" is not a hex color`,
+				`ui.colors.accent: "#GGGGGG" is not a hex color`,
+			},
+		},
+		{
+			name: "exec model problems",
+			modify: func(cfg *Config) {
+				cfg.Models.Exec = []ExecModelConfig{
+					{ID: "claude", Enabled: true, Command: "x"},
+					{Enabled: true},
+				}
+			},
+			want: []string{
+				`models.exec[0]: duplicate model id "claude"`,
+				"models.exec[1]: id is required",
+				"models.exec[1]: command is required",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			tt.modify(cfg)
+			errs := Validate(cfg)
+
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Validate() returned %d errors %v, want %d", len(errs), errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_UnknownModelFromFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := "models:\n  claude:\n    enabled: true\n  cluade:\n    enabled: true\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	errs := Validate(cfg)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "models.cluade: unknown model") {
+		t.Errorf("Validate() = %v, want a single unknown model error for cluade", errs)
+	}
+}
+
+func TestValidate_PreambleFile(t *testing.T) {
+	dir := t.TempDir()
+	facts := filepath.Join(dir, "facts.md")
+	if err := os.WriteFile(facts, []byte("We deploy on Fridays."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	huge := filepath.Join(dir, "huge.md")
+	if err := os.WriteFile(huge, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(huge, 2<<20); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string // Empty means valid
+	}{
+		{facts, ""},
+		{filepath.Join(dir, "missing.md"), "does not exist"},
+		{dir, "is a directory"},
+		{huge, "too large"},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.Defaults.PreambleFile = tt.path
+		errs := Validate(cfg)
+		if tt.want == "" {
+			if len(errs) != 0 {
+				t.Errorf("preamble_file %s: got %v, want no errors", tt.path, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "defaults.preamble_file: ") || !strings.Contains(errs[0].Error(), tt.want) {
+			t.Errorf("preamble_file %s: got %v, want an error containing %q", tt.path, errs, tt.want)
+		}
+	}
+}
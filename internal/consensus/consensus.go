@@ -2,7 +2,9 @@
 package consensus
 
 import (
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -38,26 +40,95 @@ type ParsedPosition struct {
 	RawContent string // The original content
 }
 
-// Patterns for parsing model responses
+// Defaults for parsing model responses; parsers share the compiled patterns
 var (
-	agreePattern  = regexp.MustCompile(`(?i)AGREE:\s*\[?([^\]\n]+)\]?`)
-	objectPattern = regexp.MustCompile(`(?i)OBJECT:\s*(.+?)(?:\n|$)`)
-	addPattern    = regexp.MustCompile(`(?i)ADD:\s*(.+?)(?:\n|$)`)
+	defaultAgreePattern  = regexp.MustCompile(`(?i)AGREE:\s*\[?([^\]\n]+)\]?`)
+	defaultObjectPattern = regexp.MustCompile(`(?i)OBJECT:\s*(.+?)(?:\n|$)`)
+	defaultAddPattern    = regexp.MustCompile(`(?i)ADD:\s*(.+?)(?:\n|$)`)
 
 	// Fallback keyword patterns for implicit positions
-	agreeKeywords  = []string{"i agree", "agreed", "concur", "support this", "that's correct", "exactly right"}
-	objectKeywords = []string{"i disagree", "i object", "however", "but i think", "that's wrong", "incorrect"}
-	addKeywords    = []string{"i would add", "additionally", "also consider", "one more thing", "to expand on"}
+	defaultAgreeKeywords  = []string{"i agree", "agreed", "concur", "support this", "that's correct", "exactly right"}
+	defaultObjectKeywords = []string{"i disagree", "i object", "however", "but i think", "that's wrong", "incorrect"}
+	defaultAddKeywords    = []string{"i would add", "additionally", "also consider", "one more thing", "to expand on"}
 )
 
-// DetectPosition analyzes content and returns the detected position type
+// ParserOptions tune how responses without an explicit AGREE:/OBJECT:/ADD:
+// marker are classified
+type ParserOptions struct {
+	// Keywords added to the defaults, matched case-insensitively
+	AgreeKeywords  []string
+	ObjectKeywords []string
+	AddKeywords    []string
+
+	// Use each non-empty keyword list instead of its defaults
+	ReplaceKeywords bool
+
+	// Only explicit markers count; keywords are never consulted
+	DisableKeywordFallback bool
+}
+
+// Parser detects positions in model responses. It's safe for concurrent use.
+type Parser struct {
+	agreePattern  *regexp.Regexp
+	objectPattern *regexp.Regexp
+	addPattern    *regexp.Regexp
+
+	agreeKeywords   []string
+	objectKeywords  []string
+	addKeywords     []string
+	keywordFallback bool
+}
+
+// NewParser creates a parser with the keyword sets described by opts
+func NewParser(opts ParserOptions) *Parser {
+	return &Parser{
+		agreePattern:    defaultAgreePattern,
+		objectPattern:   defaultObjectPattern,
+		addPattern:      defaultAddPattern,
+		agreeKeywords:   mergeKeywords(defaultAgreeKeywords, opts.AgreeKeywords, opts.ReplaceKeywords),
+		objectKeywords:  mergeKeywords(defaultObjectKeywords, opts.ObjectKeywords, opts.ReplaceKeywords),
+		addKeywords:     mergeKeywords(defaultAddKeywords, opts.AddKeywords, opts.ReplaceKeywords),
+		keywordFallback: !opts.DisableKeywordFallback,
+	}
+}
+
+// mergeKeywords lowercases extra and adds it to defaults, or uses it alone
+// if replace is set and extra isn't empty
+func mergeKeywords(defaults, extra []string, replace bool) []string {
+	var merged []string
+	if !replace || len(extra) == 0 {
+		merged = append(merged, defaults...)
+	}
+	for _, kw := range extra {
+		kw = strings.ToLower(strings.TrimSpace(kw))
+		if kw != "" && !slices.Contains(merged, kw) {
+			merged = append(merged, kw)
+		}
+	}
+	return merged
+}
+
+// defaultParser backs the package-level parsing functions
+var defaultParser = NewParser(ParserOptions{})
+
+// DetectPosition analyzes content with the default parser and returns the
+// detected position type
 func DetectPosition(content string) Position {
-	parsed := ParseResponse(content)
-	return parsed.Position
+	return defaultParser.DetectPosition(content)
 }
 
-// ParseResponse parses a model response and extracts structured position data
+// DetectPosition analyzes content and returns the detected position type
+func (p *Parser) DetectPosition(content string) Position {
+	return p.ParseResponse(content).Position
+}
+
+// ParseResponse parses a model response with the default keyword sets
 func ParseResponse(content string) ParsedPosition {
+	return defaultParser.ParseResponse(content)
+}
+
+// ParseResponse parses a model response and extracts structured position data
+func (p *Parser) ParseResponse(content string) ParsedPosition {
 	result := ParsedPosition{
 		Position:   PositionUnknown,
 		RawContent: content,
@@ -66,40 +137,44 @@ func ParseResponse(content string) ParsedPosition {
 	lower := strings.ToLower(content)
 
 	// Check explicit patterns first (highest priority)
-	if match := agreePattern.FindStringSubmatch(content); match != nil {
+	if match := p.agreePattern.FindStringSubmatch(content); match != nil {
 		result.Position = PositionAgree
 		result.Target = strings.TrimSpace(match[1])
 		return result
 	}
 
-	if match := objectPattern.FindStringSubmatch(content); match != nil {
+	if match := p.objectPattern.FindStringSubmatch(content); match != nil {
 		result.Position = PositionObject
 		result.Reason = strings.TrimSpace(match[1])
 		return result
 	}
 
-	if match := addPattern.FindStringSubmatch(content); match != nil {
+	if match := p.addPattern.FindStringSubmatch(content); match != nil {
 		result.Position = PositionAdd
 		result.Point = strings.TrimSpace(match[1])
 		return result
 	}
 
+	if !p.keywordFallback {
+		return result
+	}
+
 	// Fall back to keyword detection
-	for _, kw := range agreeKeywords {
+	for _, kw := range p.agreeKeywords {
 		if strings.Contains(lower, kw) {
 			result.Position = PositionAgree
 			return result
 		}
 	}
 
-	for _, kw := range objectKeywords {
+	for _, kw := range p.objectKeywords {
 		if strings.Contains(lower, kw) {
 			result.Position = PositionObject
 			return result
 		}
 	}
 
-	for _, kw := range addKeywords {
+	for _, kw := range p.addKeywords {
 		if strings.Contains(lower, kw) {
 			result.Position = PositionAdd
 			return result
@@ -109,28 +184,51 @@ func ParseResponse(content string) ParsedPosition {
 	return result
 }
 
+// ParseAgree extracts the target model from an AGREE statement using the
+// default parser
+func ParseAgree(content string) (string, bool) {
+	return defaultParser.ParseAgree(content)
+}
+
+// ParseObject extracts the reason from an OBJECT statement using the
+// default parser
+func ParseObject(content string) (string, bool) {
+	return defaultParser.ParseObject(content)
+}
+
+// ParseAdd extracts the point from an ADD statement using the default parser
+func ParseAdd(content string) (string, bool) {
+	return defaultParser.ParseAdd(content)
+}
+
 // ParseAgree extracts the target model from an AGREE statement
 // Returns the model name and true if found, empty string and false otherwise
-func ParseAgree(content string) (string, bool) {
-	if match := agreePattern.FindStringSubmatch(content); match != nil {
-		return strings.TrimSpace(match[1]), true
-	}
-	return "", false
+func (p *Parser) ParseAgree(content string) (string, bool) {
+	return firstGroup(p.agreePattern, content)
 }
 
 // ParseObject extracts the reason from an OBJECT statement
 // Returns the reason and true if found, empty string and false otherwise
-func ParseObject(content string) (string, bool) {
-	if match := objectPattern.FindStringSubmatch(content); match != nil {
-		return strings.TrimSpace(match[1]), true
-	}
-	return "", false
+func (p *Parser) ParseObject(content string) (string, bool) {
+	return firstGroup(p.objectPattern, content)
 }
 
 // ParseAdd extracts the point from an ADD statement
 // Returns the point and true if found, empty string and false otherwise
-func ParseAdd(content string) (string, bool) {
-	if match := addPattern.FindStringSubmatch(content); match != nil {
+func (p *Parser) ParseAdd(content string) (string, bool) {
+	return firstGroup(p.addPattern, content)
+}
+
+// HasExplicitMarker reports whether content carries an AGREE/OBJECT/ADD marker
+func (p *Parser) HasExplicitMarker(content string) bool {
+	return p.agreePattern.MatchString(content) ||
+		p.objectPattern.MatchString(content) ||
+		p.addPattern.MatchString(content)
+}
+
+// firstGroup returns pattern's trimmed first capture group in content
+func firstGroup(pattern *regexp.Regexp, content string) (string, bool) {
+	if match := pattern.FindStringSubmatch(content); match != nil {
 		return strings.TrimSpace(match[1]), true
 	}
 	return "", false
@@ -192,24 +290,125 @@ type ConsensusResult struct {
 	AddCount        int
 	UnknownCount    int
 	TotalCount      int
-	AgreementTarget string   // Most agreed-upon model, if any
-	Objections      []string // List of objection reasons
-	Additions       []string // List of additional points
+	AgreementTarget string         // Most agreed-upon model, if any
+	TargetCounts    map[string]int // AGREE votes per target model
+	SplitAgreement  bool           // No target has a majority of the targeted AGREE votes
+	Objections      []Objection    // Objections with a reason, ordered by model ID
+	Additions       []string       // List of additional points
+
+	// Participation tracking
+	Participants              int  // Models that took a parseable (non-UNKNOWN) position
+	MinParticipants           int  // Participants required before consensus can be declared
+	InsufficientParticipation bool // True if too few models took a position
+}
+
+// Objection is one model's objection and its stated reason
+type Objection struct {
+	ModelID string
+	Reason  string
+}
+
+// ObjectionGroup is a reason and every model that objected for it
+type ObjectionGroup struct {
+	ModelIDs []string
+	Reason   string
+}
+
+// ObjectionReasons returns just the objection reasons, one per objecting model
+func (r ConsensusResult) ObjectionReasons() []string {
+	reasons := make([]string, len(r.Objections))
+	for i, o := range r.Objections {
+		reasons[i] = o.Reason
+	}
+	return reasons
+}
+
+// GroupObjections merges objections giving the same reason, ignoring case
+// and trailing punctuation. Groups keep the first model's wording and are
+// ordered by their first objector.
+func (r ConsensusResult) GroupObjections() []ObjectionGroup {
+	var groups []ObjectionGroup
+	index := make(map[string]int)
+	for _, o := range r.Objections {
+		key := strings.ToLower(strings.TrimRight(strings.TrimSpace(o.Reason), ".!"))
+		if i, ok := index[key]; ok {
+			groups[i].ModelIDs = append(groups[i].ModelIDs, o.ModelID)
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, ObjectionGroup{ModelIDs: []string{o.ModelID}, Reason: o.Reason})
+	}
+	return groups
+}
+
+// DescribeObjections renders each objection group as a line such as
+// "GPT and Grok object: performance", naming models with nameOf
+func (r ConsensusResult) DescribeObjections(nameOf func(string) string) []string {
+	var lines []string
+	for _, g := range r.GroupObjections() {
+		names := make([]string, len(g.ModelIDs))
+		for i, id := range g.ModelIDs {
+			names[i] = nameOf(id)
+		}
+		verb := "objects"
+		if len(names) > 1 {
+			verb = "object"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %s", joinNames(names, "and"), verb, g.Reason))
+	}
+	return lines
+}
+
+// DescribeSplit renders the agreement breakdown, such as
+// "agreement is split across proposals: GPT (2), Gemini (1)", or "" if
+// agreement isn't split. nameOf converts model IDs to display names.
+func (r ConsensusResult) DescribeSplit(nameOf func(string) string) string {
+	if !r.SplitAgreement {
+		return ""
+	}
+	targets := make([]string, 0, len(r.TargetCounts))
+	for target := range r.TargetCounts {
+		targets = append(targets, target)
+	}
+	slices.SortFunc(targets, func(a, b string) int {
+		if c := r.TargetCounts[b] - r.TargetCounts[a]; c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	parts := make([]string, len(targets))
+	for i, target := range targets {
+		parts[i] = fmt.Sprintf("%s (%d)", nameOf(target), r.TargetCounts[target])
+	}
+	return "agreement is split across proposals: " + strings.Join(parts, ", ")
 }
 
+// DefaultMinParticipants is the minimum number of models that must take a
+// position before consensus can be declared
+const DefaultMinParticipants = 2
+
 // AnalyzeConsensus performs detailed consensus analysis on parsed positions
 func AnalyzeConsensus(positions map[string]ParsedPosition) ConsensusResult {
+	return AnalyzeConsensusWithMinimum(positions, 0)
+}
+
+// AnalyzeConsensusWithMinimum performs consensus analysis but refuses to declare
+// consensus unless at least minParticipants models took a parseable position.
+// This prevents a single surviving model (others timed out) from "agreeing" alone.
+func AnalyzeConsensusWithMinimum(positions map[string]ParsedPosition, minParticipants int) ConsensusResult {
 	result := ConsensusResult{
-		TotalCount: len(positions),
+		TotalCount:      len(positions),
+		MinParticipants: minParticipants,
 	}
 
 	if len(positions) == 0 {
+		result.InsufficientParticipation = minParticipants > 0
 		return result
 	}
 
 	targetCounts := make(map[string]int)
 
-	for _, parsed := range positions {
+	for model, parsed := range positions {
 		switch parsed.Position {
 		case PositionAgree:
 			result.AgreeCount++
@@ -219,7 +418,7 @@ func AnalyzeConsensus(positions map[string]ParsedPosition) ConsensusResult {
 		case PositionObject:
 			result.ObjectCount++
 			if parsed.Reason != "" {
-				result.Objections = append(result.Objections, parsed.Reason)
+				result.Objections = append(result.Objections, Objection{ModelID: model, Reason: parsed.Reason})
 			}
 		case PositionAdd:
 			result.AddCount++
@@ -231,18 +430,43 @@ func AnalyzeConsensus(positions map[string]ParsedPosition) ConsensusResult {
 		}
 	}
 
+	slices.SortFunc(result.Objections, func(a, b Objection) int {
+		return strings.Compare(a.ModelID, b.ModelID)
+	})
+
 	// Find most agreed-upon target
-	maxCount := 0
+	maxCount, targeted := 0, 0
 	for target, count := range targetCounts {
-		if count > maxCount {
+		targeted += count
+		if count > maxCount || (count == maxCount && target < result.AgreementTarget) {
 			maxCount = count
 			result.AgreementTarget = target
 		}
 	}
+	if len(targetCounts) > 0 {
+		result.TargetCounts = targetCounts
+	}
+	result.SplitAgreement = len(targetCounts) > 1 && maxCount < targeted/2+1
 
 	// Determine consensus
 	majority := len(positions)/2 + 1
 	result.HasConsensus = result.AgreeCount >= majority && result.ObjectCount == 0
 
+	// Degenerate rounds can't produce consensus regardless of agreement
+	result.Participants = result.TotalCount - result.UnknownCount
+	if result.Participants < minParticipants {
+		result.InsufficientParticipation = true
+		result.HasConsensus = false
+	}
+
 	return result
 }
+
+// ParticipationMessage describes why consensus couldn't be declared due to
+// insufficient participation. Returns empty string if participation was sufficient.
+func (r ConsensusResult) ParticipationMessage() string {
+	if !r.InsufficientParticipation {
+		return ""
+	}
+	return fmt.Sprintf("insufficient participation: %d of %d required models took a position", r.Participants, r.MinParticipants)
+}
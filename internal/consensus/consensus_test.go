@@ -2,6 +2,9 @@
 package consensus
 
 import (
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -286,6 +289,119 @@ func TestCheckStrictConsensus(t *testing.T) {
 	}
 }
 
+func TestParser_DefaultMatchesPackageFunctions(t *testing.T) {
+	inputs := []string{
+		"AGREE: [GPT]\nGood plan.",
+		"OBJECT: no rollback plan",
+		"ADD: consider caching",
+		"I agree with Claude.",
+		"However, this ignores load.",
+		"Additionally, log the retries.",
+		"Here is some code.",
+	}
+
+	p := NewParser(ParserOptions{})
+	for _, input := range inputs {
+		if got, want := p.ParseResponse(input), ParseResponse(input); got != want {
+			t.Errorf("ParseResponse(%q) = %+v, package function gives %+v", input, got, want)
+		}
+		if got, want := p.DetectPosition(input), DetectPosition(input); got != want {
+			t.Errorf("DetectPosition(%q) = %v, package function gives %v", input, got, want)
+		}
+		gotTarget, gotOK := p.ParseAgree(input)
+		wantTarget, wantOK := ParseAgree(input)
+		if gotTarget != wantTarget || gotOK != wantOK {
+			t.Errorf("ParseAgree(%q) = %q, %v, package function gives %q, %v", input, gotTarget, gotOK, wantTarget, wantOK)
+		}
+		gotReason, gotOK := p.ParseObject(input)
+		wantReason, wantOK := ParseObject(input)
+		if gotReason != wantReason || gotOK != wantOK {
+			t.Errorf("ParseObject(%q) = %q, %v, package function gives %q, %v", input, gotReason, gotOK, wantReason, wantOK)
+		}
+		gotPoint, gotOK := p.ParseAdd(input)
+		wantPoint, wantOK := ParseAdd(input)
+		if gotPoint != wantPoint || gotOK != wantOK {
+			t.Errorf("ParseAdd(%q) = %q, %v, package function gives %q, %v", input, gotPoint, gotOK, wantPoint, wantOK)
+		}
+	}
+}
+
+func TestParser_IndependentInstances(t *testing.T) {
+	strict := NewParser(ParserOptions{DisableKeywordFallback: true})
+	custom := NewParser(ParserOptions{AgreeKeywords: []string{"ship it"}})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := strict.DetectPosition("Ship it, I agree."); got != PositionUnknown {
+				t.Errorf("strict parser = %v, want UNKNOWN", got)
+			}
+			if got := custom.DetectPosition("Ship it."); got != PositionAgree {
+				t.Errorf("custom parser = %v, want AGREE", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Customizing one parser leaves the package default alone
+	if got := DetectPosition("Ship it."); got != PositionUnknown {
+		t.Errorf("DetectPosition() = %v, want UNKNOWN with the default keywords", got)
+	}
+}
+
+func TestParser_TunedKeywords(t *testing.T) {
+	caveat := "Looks good to me. However, we should add metrics later."
+	tests := []struct {
+		name    string
+		opts    ParserOptions
+		content string
+		want    Position
+	}{
+		{"default keywords", ParserOptions{}, caveat, PositionObject},
+		{
+			name:    "replaced object keywords drop however",
+			opts:    ParserOptions{ObjectKeywords: []string{"I object", "that's wrong"}, ReplaceKeywords: true},
+			content: caveat,
+			want:    PositionUnknown,
+		},
+		{
+			name:    "extra agree keyword",
+			opts:    ParserOptions{AgreeKeywords: []string{"Looks Good To Me"}},
+			content: caveat,
+			want:    PositionAgree,
+		},
+		{
+			name:    "extra keywords keep the defaults",
+			opts:    ParserOptions{AgreeKeywords: []string{"ship it"}},
+			content: "I concur with the plan.",
+			want:    PositionAgree,
+		},
+		{
+			name:    "fallback disabled",
+			opts:    ParserOptions{DisableKeywordFallback: true},
+			content: "I agree with everything said.",
+			want:    PositionUnknown,
+		},
+		{
+			name:    "fallback disabled still reads markers",
+			opts:    ParserOptions{DisableKeywordFallback: true},
+			content: "OBJECT: no rollback plan",
+			want:    PositionObject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewParser(tt.opts).ParseResponse(tt.content).Position
+			if got != tt.want {
+				t.Errorf("ParseResponse(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestAnalyzeConsensus(t *testing.T) {
 	positions := map[string]ParsedPosition{
 		"claude": {Position: PositionAgree, Target: "gpt"},
@@ -318,3 +434,166 @@ func TestAnalyzeConsensus(t *testing.T) {
 		t.Errorf("Additions count = %d, want 1", len(result.Additions))
 	}
 }
+
+func TestAnalyzeConsensus_GroupsObjections(t *testing.T) {
+	positions := map[string]ParsedPosition{
+		"claude": {Position: PositionAgree, Target: "gpt"},
+		"grok":   {Position: PositionObject, Reason: "performance"},
+		"gpt":    {Position: PositionObject, Reason: "Performance."},
+		"gemini": {Position: PositionObject, Reason: "no migration path"},
+	}
+
+	result := AnalyzeConsensus(positions)
+
+	want := []Objection{
+		{ModelID: "gemini", Reason: "no migration path"},
+		{ModelID: "gpt", Reason: "Performance."},
+		{ModelID: "grok", Reason: "performance"},
+	}
+	if !slices.Equal(result.Objections, want) {
+		t.Errorf("Objections = %+v, want %+v", result.Objections, want)
+	}
+	if got := result.ObjectionReasons(); !slices.Equal(got, []string{"no migration path", "Performance.", "performance"}) {
+		t.Errorf("ObjectionReasons() = %v", got)
+	}
+
+	groups := result.GroupObjections()
+	if len(groups) != 2 || !slices.Equal(groups[1].ModelIDs, []string{"gpt", "grok"}) {
+		t.Fatalf("GroupObjections() = %+v, want gpt and grok grouped", groups)
+	}
+
+	lines := result.DescribeObjections(strings.ToUpper)
+	wantLines := []string{"GEMINI objects: no migration path", "GPT and GROK object: Performance."}
+	if !slices.Equal(lines, wantLines) {
+		t.Errorf("DescribeObjections() = %q, want %q", lines, wantLines)
+	}
+}
+
+func TestAnalyzeConsensus_SplitAgreement(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions map[string]ParsedPosition
+		wantSplit bool
+	}{
+		{
+			name: "diverging targets",
+			positions: map[string]ParsedPosition{
+				"claude": {Position: PositionAgree, Target: "gpt"},
+				"gpt":    {Position: PositionAgree, Target: "gemini"},
+				"gemini": {Position: PositionAgree, Target: "grok"},
+			},
+			wantSplit: true,
+		},
+		{
+			name: "majority on one target",
+			positions: map[string]ParsedPosition{
+				"claude": {Position: PositionAgree, Target: "gpt"},
+				"gemini": {Position: PositionAgree, Target: "gpt"},
+				"gpt":    {Position: PositionAgree, Target: "claude"},
+			},
+			wantSplit: false,
+		},
+		{
+			name: "single target",
+			positions: map[string]ParsedPosition{
+				"claude": {Position: PositionAgree, Target: "gpt"},
+				"gpt":    {Position: PositionAgree},
+			},
+			wantSplit: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AnalyzeConsensus(tt.positions)
+			if result.SplitAgreement != tt.wantSplit {
+				t.Errorf("SplitAgreement = %v, want %v (targets %v)", result.SplitAgreement, tt.wantSplit, result.TargetCounts)
+			}
+			if got := result.DescribeSplit(strings.ToUpper); (got != "") != tt.wantSplit {
+				t.Errorf("DescribeSplit() = %q, want a description only for split agreement", got)
+			}
+		})
+	}
+
+	result := AnalyzeConsensus(tests[0].positions)
+	want := "agreement is split across proposals: GEMINI (1), GPT (1), GROK (1)"
+	if got := result.DescribeSplit(strings.ToUpper); got != want {
+		t.Errorf("DescribeSplit() = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyzeConsensusWithMinimum(t *testing.T) {
+	tests := []struct {
+		name             string
+		positions        map[string]ParsedPosition
+		minParticipants  int
+		wantConsensus    bool
+		wantInsufficient bool
+	}{
+		{
+			name: "single survivor cannot reach consensus",
+			positions: map[string]ParsedPosition{
+				"claude": {Position: PositionAgree, Target: "claude"},
+			},
+			minParticipants:  2,
+			wantConsensus:    false,
+			wantInsufficient: true,
+		},
+		{
+			name: "unknown positions do not count as participation",
+			positions: map[string]ParsedPosition{
+				"claude": {Position: PositionAgree, Target: "gpt"},
+				"gpt":    {Position: PositionUnknown},
+				"gemini": {Position: PositionUnknown},
+			},
+			minParticipants:  2,
+			wantConsensus:    false,
+			wantInsufficient: true,
+		},
+		{
+			name: "enough participants allows consensus",
+			positions: map[string]ParsedPosition{
+				"claude": {Position: PositionAgree, Target: "gpt"},
+				"gpt":    {Position: PositionAgree, Target: "claude"},
+			},
+			minParticipants:  2,
+			wantConsensus:    true,
+			wantInsufficient: false,
+		},
+		{
+			name:             "empty round is insufficient",
+			positions:        map[string]ParsedPosition{},
+			minParticipants:  2,
+			wantConsensus:    false,
+			wantInsufficient: true,
+		},
+		{
+			name: "zero minimum preserves legacy behavior",
+			positions: map[string]ParsedPosition{
+				"claude": {Position: PositionAgree, Target: "claude"},
+			},
+			minParticipants:  0,
+			wantConsensus:    true,
+			wantInsufficient: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AnalyzeConsensusWithMinimum(tt.positions, tt.minParticipants)
+			if result.HasConsensus != tt.wantConsensus {
+				t.Errorf("HasConsensus = %v, want %v", result.HasConsensus, tt.wantConsensus)
+			}
+			if result.InsufficientParticipation != tt.wantInsufficient {
+				t.Errorf("InsufficientParticipation = %v, want %v", result.InsufficientParticipation, tt.wantInsufficient)
+			}
+			msg := result.ParticipationMessage()
+			if tt.wantInsufficient && !strings.Contains(msg, "insufficient participation") {
+				t.Errorf("ParticipationMessage() = %q, want insufficient participation message", msg)
+			}
+			if !tt.wantInsufficient && msg != "" {
+				t.Errorf("ParticipationMessage() = %q, want empty", msg)
+			}
+		})
+	}
+}
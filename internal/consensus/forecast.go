@@ -0,0 +1,121 @@
+// internal/consensus/forecast.go
+package consensus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GapAnalysis describes what would need to change for a round that fell short
+// of consensus to reach it, so the user can judge whether another round is worthwhile
+type GapAnalysis struct {
+	Reached       bool     // Consensus was already reached
+	NearConsensus bool     // A single model changing position would reach consensus
+	Blockers      []string // Models whose objection blocks consensus
+	Candidates    []string // Non-agreeing, non-objecting models that could supply missing votes
+	VotesNeeded   int      // Additional AGREE votes needed for a majority
+	ChangesNeeded int      // Minimum number of models that must change position
+
+	InsufficientParticipation bool
+	Participants              int
+	MinParticipants           int
+}
+
+// AnalyzeGap predicts how close a round is to consensus based on its positions
+func AnalyzeGap(positions map[string]ParsedPosition, minParticipants int) GapAnalysis {
+	result := AnalyzeConsensusWithMinimum(positions, minParticipants)
+
+	gap := GapAnalysis{
+		Reached:                   result.HasConsensus,
+		InsufficientParticipation: result.InsufficientParticipation,
+		Participants:              result.Participants,
+		MinParticipants:           result.MinParticipants,
+	}
+	if gap.Reached {
+		return gap
+	}
+
+	for id, p := range positions {
+		switch p.Position {
+		case PositionObject:
+			gap.Blockers = append(gap.Blockers, id)
+		case PositionAgree:
+		default:
+			gap.Candidates = append(gap.Candidates, id)
+		}
+	}
+	sort.Strings(gap.Blockers)
+	sort.Strings(gap.Candidates)
+
+	majority := len(positions)/2 + 1
+	if needed := majority - result.AgreeCount; needed > 0 {
+		gap.VotesNeeded = needed
+	}
+
+	// An objector switching to AGREE both removes the objection and adds a vote
+	gap.ChangesNeeded = max(len(gap.Blockers), gap.VotesNeeded)
+	gap.NearConsensus = !gap.InsufficientParticipation && len(positions) > 0 && gap.ChangesNeeded == 1
+
+	return gap
+}
+
+// Describe renders the gap analysis as a one-line hint. nameOf converts model
+// IDs to display names; pass nil to use the IDs as-is.
+func (g GapAnalysis) Describe(nameOf func(string) string) string {
+	if nameOf == nil {
+		nameOf = func(id string) string { return id }
+	}
+	names := func(ids []string, conj string) string {
+		out := make([]string, len(ids))
+		for i, id := range ids {
+			out[i] = nameOf(id)
+		}
+		return joinNames(out, conj)
+	}
+
+	switch {
+	case g.Reached:
+		return "Consensus already reached."
+
+	case g.InsufficientParticipation:
+		return fmt.Sprintf("Only %d of %d required models took a position - another round won't help unless more models respond.",
+			g.Participants, g.MinParticipants)
+
+	case g.NearConsensus && len(g.Blockers) == 1 && g.VotesNeeded == 0:
+		return fmt.Sprintf("If %s drops its objection, consensus is reached.", names(g.Blockers, "and"))
+
+	case g.NearConsensus && len(g.Blockers) == 1:
+		return fmt.Sprintf("If %s switches to AGREE, consensus is reached.", names(g.Blockers, "and"))
+
+	case g.NearConsensus && len(g.Candidates) > 0:
+		return fmt.Sprintf("One more AGREE (from %s) would reach consensus.", names(g.Candidates, "or"))
+
+	case g.NearConsensus:
+		return "One more AGREE would reach consensus."
+	}
+
+	var parts []string
+	if len(g.Blockers) > 0 {
+		parts = append(parts, fmt.Sprintf("%d objection(s) from %s", len(g.Blockers), names(g.Blockers, "and")))
+	}
+	if g.VotesNeeded > 0 {
+		parts = append(parts, fmt.Sprintf("%d more AGREE vote(s) needed", g.VotesNeeded))
+	}
+	if len(parts) == 0 {
+		return "Consensus not reached."
+	}
+	return fmt.Sprintf("Consensus is %d position change(s) away: %s.", g.ChangesNeeded, strings.Join(parts, " and "))
+}
+
+// joinNames joins names as "a", "a or b", "a, b or c" (with the given conjunction)
+func joinNames(names []string, conj string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	default:
+		return strings.Join(names[:len(names)-1], ", ") + " " + conj + " " + names[len(names)-1]
+	}
+}
@@ -0,0 +1,125 @@
+// internal/consensus/forecast_test.go
+package consensus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeGap_NearConsensusSingleObjection(t *testing.T) {
+	positions := map[string]ParsedPosition{
+		"claude": {Position: PositionAgree, Target: "claude"},
+		"gemini": {Position: PositionAgree, Target: "claude"},
+		"gpt":    {Position: PositionObject, Reason: "too slow"},
+	}
+
+	gap := AnalyzeGap(positions, 2)
+
+	if gap.Reached {
+		t.Fatal("Reached should be false with an objection")
+	}
+	if !gap.NearConsensus {
+		t.Error("expected NearConsensus with a single objection and majority agreement")
+	}
+	if len(gap.Blockers) != 1 || gap.Blockers[0] != "gpt" {
+		t.Errorf("Blockers = %v, want [gpt]", gap.Blockers)
+	}
+	if gap.VotesNeeded != 0 {
+		t.Errorf("VotesNeeded = %d, want 0", gap.VotesNeeded)
+	}
+
+	got := gap.Describe(strings.ToUpper)
+	if got != "If GPT drops its objection, consensus is reached." {
+		t.Errorf("Describe() = %q", got)
+	}
+}
+
+func TestAnalyzeGap_NearConsensusObjectorMustAgree(t *testing.T) {
+	positions := map[string]ParsedPosition{
+		"claude": {Position: PositionAgree},
+		"gemini": {Position: PositionAgree},
+		"gpt":    {Position: PositionObject},
+		"grok":   {Position: PositionAdd},
+	}
+
+	gap := AnalyzeGap(positions, 2)
+
+	if !gap.NearConsensus {
+		t.Fatalf("expected NearConsensus, got %+v", gap)
+	}
+	if got := gap.Describe(nil); !strings.Contains(got, "If gpt switches to AGREE") {
+		t.Errorf("Describe() = %q, want switch-to-agree hint", got)
+	}
+}
+
+func TestAnalyzeGap_NearConsensusOneVoteShort(t *testing.T) {
+	positions := map[string]ParsedPosition{
+		"claude": {Position: PositionAgree},
+		"gemini": {Position: PositionAdd},
+		"gpt":    {Position: PositionUnknown},
+	}
+
+	gap := AnalyzeGap(positions, 1)
+
+	if !gap.NearConsensus {
+		t.Fatalf("expected NearConsensus, got %+v", gap)
+	}
+	got := gap.Describe(nil)
+	if got != "One more AGREE (from gemini or gpt) would reach consensus." {
+		t.Errorf("Describe() = %q", got)
+	}
+}
+
+func TestAnalyzeGap_FarFromConsensus(t *testing.T) {
+	positions := map[string]ParsedPosition{
+		"claude": {Position: PositionAgree},
+		"gemini": {Position: PositionObject},
+		"gpt":    {Position: PositionObject},
+		"grok":   {Position: PositionUnknown},
+	}
+
+	gap := AnalyzeGap(positions, 2)
+
+	if gap.NearConsensus {
+		t.Error("NearConsensus should be false with two objections")
+	}
+	if gap.ChangesNeeded != 2 {
+		t.Errorf("ChangesNeeded = %d, want 2", gap.ChangesNeeded)
+	}
+	got := gap.Describe(nil)
+	if !strings.Contains(got, "2 objection(s) from gemini and gpt") || !strings.Contains(got, "2 more AGREE vote(s) needed") {
+		t.Errorf("Describe() = %q", got)
+	}
+}
+
+func TestAnalyzeGap_InsufficientParticipation(t *testing.T) {
+	positions := map[string]ParsedPosition{
+		"claude": {Position: PositionAgree},
+		"gpt":    {Position: PositionUnknown},
+	}
+
+	gap := AnalyzeGap(positions, 2)
+
+	if gap.NearConsensus {
+		t.Error("NearConsensus should be false when participation is insufficient")
+	}
+	if got := gap.Describe(nil); !strings.Contains(got, "Only 1 of 2") {
+		t.Errorf("Describe() = %q", got)
+	}
+}
+
+func TestAnalyzeGap_AlreadyReached(t *testing.T) {
+	positions := map[string]ParsedPosition{
+		"claude": {Position: PositionAgree},
+		"gpt":    {Position: PositionAgree},
+	}
+
+	gap := AnalyzeGap(positions, 2)
+
+	if !gap.Reached {
+		t.Error("Reached should be true")
+	}
+	if gap.NearConsensus {
+		t.Error("NearConsensus should be false once consensus is reached")
+	}
+}
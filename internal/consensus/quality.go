@@ -0,0 +1,105 @@
+// internal/consensus/quality.go
+package consensus
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MinResponseLength is the shortest response (in characters) not considered
+// suspiciously short. Responses carrying an explicit AGREE/OBJECT/ADD marker are
+// exempt since "AGREE: Claude" is a perfectly valid answer.
+const MinResponseLength = 15
+
+// maxRefusalLength caps how long a response can be and still be treated as a
+// refusal. Longer answers that merely contain a refusal phrase usually go on to
+// say something useful, so we leave them alone.
+const maxRefusalLength = 300
+
+// refusalPhrases are openers that indicate the model declined to engage
+var refusalPhrases = []string{
+	"i can't help with that",
+	"i cannot help with that",
+	"i can't assist with that",
+	"i cannot assist with that",
+	"i'm unable to help",
+	"i am unable to help",
+	"i'm not able to help",
+	"i won't be able to help",
+	"sorry, but i can't",
+	"sorry, i can't",
+	"as an ai language model, i cannot",
+}
+
+// ResponseQuality flags low-effort model responses
+type ResponseQuality struct {
+	TooShort bool // Response is suspiciously short
+	Refusal  bool // Response is a refusal to engage
+	Echo     bool // Response just repeats the prompt
+}
+
+// LowEffort returns true if any heuristic flagged the response
+func (q ResponseQuality) LowEffort() bool {
+	return q.TooShort || q.Refusal || q.Echo
+}
+
+// Warning returns a short human-readable description of the problem,
+// or empty string if the response looks fine
+func (q ResponseQuality) Warning() string {
+	switch {
+	case q.Refusal:
+		return "refusal"
+	case q.Echo:
+		return "echoes prompt"
+	case q.TooShort:
+		return "too short"
+	default:
+		return ""
+	}
+}
+
+// AssessQuality applies conservative heuristics to a model response.
+// prompt is the prompt the model was answering; it may be empty.
+func AssessQuality(prompt, response string) ResponseQuality {
+	var q ResponseQuality
+
+	trimmed := strings.TrimSpace(response)
+	normalized := normalizeForComparison(trimmed)
+
+	if utf8.RuneCountInString(trimmed) < MinResponseLength && !hasExplicitMarker(trimmed) {
+		q.TooShort = true
+	}
+
+	if len(trimmed) <= maxRefusalLength {
+		for _, phrase := range refusalPhrases {
+			if strings.HasPrefix(normalized, phrase) {
+				q.Refusal = true
+				break
+			}
+		}
+	}
+
+	// Only flag echoes that are long enough to be meaningful - a one-word
+	// answer that happens to appear in the prompt is not an echo
+	normalizedPrompt := normalizeForComparison(prompt)
+	if normalizedPrompt != "" && utf8.RuneCountInString(normalized) >= MinResponseLength {
+		if normalized == normalizedPrompt || strings.Contains(normalizedPrompt, normalized) {
+			q.Echo = true
+		}
+	}
+
+	return q
+}
+
+// hasExplicitMarker reports whether content carries an AGREE/OBJECT/ADD marker
+func hasExplicitMarker(content string) bool {
+	return defaultParser.HasExplicitMarker(content)
+}
+
+// normalizeForComparison lowercases and collapses whitespace and curly quotes
+// so trivially reformatted echoes still match
+func normalizeForComparison(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "’", "'")
+	return strings.Join(strings.Fields(s), " ")
+}
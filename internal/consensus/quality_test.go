@@ -0,0 +1,86 @@
+// internal/consensus/quality_test.go
+package consensus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssessQuality(t *testing.T) {
+	prompt := "What is the best way to implement a cache for the user service?"
+
+	tests := []struct {
+		name        string
+		response    string
+		wantShort   bool
+		wantRefusal bool
+		wantEcho    bool
+	}{
+		{
+			name:     "substantive answer",
+			response: "Use an LRU cache keyed on user ID with a 5 minute TTL.",
+		},
+		{
+			name:      "too short",
+			response:  "Sure.",
+			wantShort: true,
+		},
+		{
+			name:     "short explicit agree is fine",
+			response: "AGREE: Claude",
+		},
+		{
+			name:        "refusal",
+			response:    "I can't help with that.",
+			wantRefusal: true,
+		},
+		{
+			name:        "refusal with curly apostrophe",
+			response:    "I can’t help with that request.",
+			wantRefusal: true,
+		},
+		{
+			name:     "long answer mentioning refusal phrase is not a refusal",
+			response: "I can't help with that part directly, but " + strings.Repeat("here is a detailed plan for the cache layer. ", 10),
+		},
+		{
+			name:     "exact echo",
+			response: "What is the best way to implement a cache for the user service?",
+			wantEcho: true,
+		},
+		{
+			name:     "echo with different whitespace and case",
+			response: "what is the best way to implement   a cache",
+			wantEcho: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := AssessQuality(prompt, tt.response)
+			if q.TooShort != tt.wantShort {
+				t.Errorf("TooShort = %v, want %v", q.TooShort, tt.wantShort)
+			}
+			if q.Refusal != tt.wantRefusal {
+				t.Errorf("Refusal = %v, want %v", q.Refusal, tt.wantRefusal)
+			}
+			if q.Echo != tt.wantEcho {
+				t.Errorf("Echo = %v, want %v", q.Echo, tt.wantEcho)
+			}
+			wantLowEffort := tt.wantShort || tt.wantRefusal || tt.wantEcho
+			if q.LowEffort() != wantLowEffort {
+				t.Errorf("LowEffort() = %v, want %v", q.LowEffort(), wantLowEffort)
+			}
+			if wantLowEffort && q.Warning() == "" {
+				t.Error("Warning() should describe a low-effort response")
+			}
+		})
+	}
+}
+
+func TestAssessQuality_NoPrompt(t *testing.T) {
+	q := AssessQuality("", "A reasonable answer without any prompt to compare to.")
+	if q.LowEffort() {
+		t.Errorf("expected no flags without prompt, got %+v", q)
+	}
+}
@@ -0,0 +1,67 @@
+// internal/consensus/rounds.go
+package consensus
+
+// Message is the part of a debate message round slicing looks at
+type Message struct {
+	Source  string // user, system, moderator, or a model ID
+	Content string
+}
+
+// Round is a user prompt and the model responses that followed it
+type Round struct {
+	Prompt    string
+	Responses []Message // In order; a model may appear more than once
+}
+
+// isParticipant reports whether messages from source take a position
+func isParticipant(source string) bool {
+	return source != "user" && source != "system" && source != "moderator"
+}
+
+// SliceRounds splits messages into rounds, one per user message. Messages
+// before the first user message, and system or moderator messages anywhere,
+// belong to no round.
+func SliceRounds(messages []Message) []Round {
+	var rounds []Round
+	for _, msg := range messages {
+		switch {
+		case msg.Source == "user":
+			rounds = append(rounds, Round{Prompt: msg.Content})
+		case len(rounds) > 0 && isParticipant(msg.Source):
+			last := &rounds[len(rounds)-1]
+			last.Responses = append(last.Responses, msg)
+		}
+	}
+	return rounds
+}
+
+// ExtractLatestRound parses each model's position in the round after the
+// most recent user message, with the built-in keywords. Returns nil if
+// there is no user message.
+func ExtractLatestRound(messages []Message) map[string]ParsedPosition {
+	return defaultParser.ExtractLatestRound(messages)
+}
+
+// ExtractLatestRound parses each model's position in the round after the
+// most recent user message. Returns nil if there is no user message.
+func (p *Parser) ExtractLatestRound(messages []Message) map[string]ParsedPosition {
+	rounds := SliceRounds(messages)
+	if len(rounds) == 0 {
+		return nil
+	}
+	return p.RoundPositions(rounds[len(rounds)-1])
+}
+
+// RoundPositions parses each model's position in round; a model's latest
+// response wins. Low-effort responses (refusals, echoes) count as unknown.
+func (p *Parser) RoundPositions(round Round) map[string]ParsedPosition {
+	positions := make(map[string]ParsedPosition)
+	for _, msg := range round.Responses {
+		if AssessQuality(round.Prompt, msg.Content).LowEffort() {
+			positions[msg.Source] = ParsedPosition{Position: PositionUnknown, RawContent: msg.Content}
+			continue
+		}
+		positions[msg.Source] = p.ParseResponse(msg.Content)
+	}
+	return positions
+}
@@ -0,0 +1,143 @@
+// internal/consensus/rounds_test.go
+package consensus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSliceRounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []Message
+		want     []Round
+	}{
+		{"empty", nil, nil},
+		{
+			"no user message",
+			[]Message{{"system", "Debate started"}, {"claude", "AGREE: gpt"}},
+			nil,
+		},
+		{
+			"one round",
+			[]Message{
+				{"system", "Debate started"},
+				{"user", "Which cache?"},
+				{"claude", "Redis."},
+				{"system", "context file added"},
+				{"gpt", "AGREE: claude"},
+			},
+			[]Round{{Prompt: "Which cache?", Responses: []Message{{"claude", "Redis."}, {"gpt", "AGREE: claude"}}}},
+		},
+		{
+			"several rounds, moderator skipped",
+			[]Message{
+				{"user", "Q1"},
+				{"claude", "A1"},
+				{"moderator", "Summary"},
+				{"user", "Q2"},
+				{"user", "Q3"},
+				{"gpt", "A3"},
+				{"gpt", "A3 again"},
+			},
+			[]Round{
+				{Prompt: "Q1", Responses: []Message{{"claude", "A1"}}},
+				{Prompt: "Q2"},
+				{Prompt: "Q3", Responses: []Message{{"gpt", "A3"}, {"gpt", "A3 again"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SliceRounds(tt.messages); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SliceRounds() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_RoundPositions(t *testing.T) {
+	round := Round{
+		Prompt: "Should we use Redis for caching?",
+		Responses: []Message{
+			{"claude", "OBJECT: too heavy for this service"},
+			{"claude", "AGREE: gpt, on reflection Redis is fine"},
+			{"gpt", "Redis is the right choice for caching here because it is fast."},
+			{"grok", "I can't help with that."},
+		},
+	}
+	positions := NewParser(ParserOptions{}).RoundPositions(round)
+
+	if len(positions) != 3 {
+		t.Fatalf("got %d positions, want 3: %+v", len(positions), positions)
+	}
+	if positions["claude"].Position != PositionAgree {
+		t.Errorf("claude = %v, want its latest response (AGREE) to win", positions["claude"].Position)
+	}
+	if positions["grok"].Position != PositionUnknown {
+		t.Errorf("grok = %v, want a refusal to count as UNKNOWN", positions["grok"].Position)
+	}
+}
+
+func TestExtractLatestRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []Message
+		want     map[string]Position // nil means no round at all
+	}{
+		{"no messages", nil, nil},
+		{
+			"no user message",
+			[]Message{{"system", "Debate started"}, {"claude", "AGREE: gpt"}},
+			nil,
+		},
+		{
+			"user message without responses",
+			[]Message{{"user", "Q1"}, {"claude", "AGREE: gpt"}, {"user", "Q2"}},
+			map[string]Position{},
+		},
+		{
+			"trailing system messages",
+			[]Message{
+				{"user", "Which cache should we use?"},
+				{"claude", "AGREE: gpt"},
+				{"gpt", "OBJECT: too slow"},
+				{"system", "=== Discussion Round 2 of 3 ==="},
+				{"moderator", "They disagree on speed."},
+			},
+			map[string]Position{"claude": PositionAgree, "gpt": PositionObject},
+		},
+		{
+			"interleaved rounds",
+			[]Message{
+				{"user", "Q1"},
+				{"claude", "OBJECT: no"},
+				{"gpt", "OBJECT: no"},
+				{"user", "Q2"},
+				{"gpt", "AGREE: claude"},
+				{"system", "context file added"},
+				{"claude", "ADD: also add tests"},
+			},
+			map[string]Position{"gpt": PositionAgree, "claude": PositionAdd},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractLatestRound(tt.messages)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("ExtractLatestRound() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || len(got) != len(tt.want) {
+				t.Fatalf("ExtractLatestRound() = %+v, want %v", got, tt.want)
+			}
+			for source, position := range tt.want {
+				if got[source].Position != position {
+					t.Errorf("%s = %v, want %v", source, got[source].Position, position)
+				}
+			}
+		})
+	}
+}
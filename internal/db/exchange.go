@@ -0,0 +1,263 @@
+// internal/db/exchange.go
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exportVersion is the ExportedDebate format written by ExportDebate
+const exportVersion = 1
+
+// ExportedDebate is a debate in the JSON export format that ImportDebate
+// reads back
+type ExportedDebate struct {
+	Version      int                   `json:"version"`
+	ID           string                `json:"id"`
+	Name         string                `json:"name"`
+	ProjectPath  string                `json:"project_path,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+	Status       string                `json:"status,omitempty"`
+	Consensus    string                `json:"consensus,omitempty"`
+	Tags         []string              `json:"tags,omitempty"`
+	Messages     []ExportedMessage     `json:"messages"`
+	ContextFiles []ExportedContextFile `json:"context_files,omitempty"`
+}
+
+// ExportedMessage is one message of an ExportedDebate
+type ExportedMessage struct {
+	Source    string    `json:"source"`
+	Content   string    `json:"content"`
+	MsgType   string    `json:"msg_type,omitempty"`
+	Round     int       `json:"round,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportedContextFile is one context file of an ExportedDebate
+type ExportedContextFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ExportDebate reads a debate with its messages, context files and tags.
+// Drafts of responses still streaming are left out.
+func (s *Store) ExportDebate(id string) (*ExportedDebate, error) {
+	d, err := s.GetDebate(id)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.GetMessages(id)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.GetContextFiles(id)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.GetTags(id)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &ExportedDebate{
+		Version:     exportVersion,
+		ID:          d.ID,
+		Name:        d.Name,
+		ProjectPath: d.ProjectPath,
+		CreatedAt:   d.CreatedAt,
+		Status:      d.Status,
+		Consensus:   d.Consensus,
+		Tags:        tags,
+		Messages:    make([]ExportedMessage, 0, len(messages)),
+	}
+	for _, m := range messages {
+		if m.MsgType == MsgTypeDraft {
+			continue
+		}
+		record.Messages = append(record.Messages, ExportedMessage{
+			Source:    m.Source,
+			Content:   m.Content,
+			MsgType:   m.MsgType,
+			Round:     m.Round,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+	for _, f := range files {
+		record.ContextFiles = append(record.ContextFiles, ExportedContextFile{Path: f.Path, Content: f.Content})
+	}
+	return record, nil
+}
+
+// WithoutContext returns a copy of d without its MsgTypeContext messages
+func (d *ExportedDebate) WithoutContext() *ExportedDebate {
+	filtered := *d
+	filtered.Messages = make([]ExportedMessage, 0, len(d.Messages))
+	for _, m := range d.Messages {
+		if m.MsgType != MsgTypeContext {
+			filtered.Messages = append(filtered.Messages, m)
+		}
+	}
+	return &filtered
+}
+
+// ParseExportedDebate decodes and validates a JSON debate export
+func ParseExportedDebate(data []byte) (*ExportedDebate, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var record ExportedDebate
+	if err := dec.Decode(&record); err != nil {
+		return nil, fmt.Errorf("not a roundtable debate export: %w", err)
+	}
+	if err := record.Validate(); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Validate checks that a record has what ImportDebate needs
+func (r *ExportedDebate) Validate() error {
+	if r.Version != exportVersion {
+		return fmt.Errorf("unsupported export version %d (expected %d)", r.Version, exportVersion)
+	}
+	if r.Name == "" {
+		return fmt.Errorf("debate has no name")
+	}
+	if r.Messages == nil {
+		return fmt.Errorf("debate has no messages list")
+	}
+	for i, m := range r.Messages {
+		if m.Source == "" {
+			return fmt.Errorf("messages[%d]: source is required", i)
+		}
+	}
+	for i, f := range r.ContextFiles {
+		if f.Path == "" {
+			return fmt.Errorf("context_files[%d]: path is required", i)
+		}
+	}
+	return nil
+}
+
+// ImportDebate stores an exported debate under a new ID, so importing never
+// collides with an existing debate, and returns that ID. Drafts in older
+// exports are skipped so they aren't recovered as interrupted responses.
+func (s *Store) ImportDebate(record ExportedDebate) (string, error) {
+	if err := record.Validate(); err != nil {
+		return "", err
+	}
+
+	id := uuid.New().String()[:8]
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	status := record.Status
+	if status == "" {
+		status = "active"
+	}
+	created := record.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO debates (id, name, project_path, created_at, status, consensus) VALUES (?, ?, ?, ?, ?, ?)`,
+		id, record.Name, record.ProjectPath, created, status, record.Consensus,
+	); err != nil {
+		return "", err
+	}
+
+	for _, m := range record.Messages {
+		if m.MsgType == MsgTypeDraft {
+			continue
+		}
+		msgType := m.MsgType
+		if msgType == "" {
+			msgType = "model"
+		}
+		at := m.CreatedAt
+		if at.IsZero() {
+			at = created
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO messages (debate_id, source, content, msg_type, round, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, m.Source, m.Content, msgType, m.Round, at,
+		); err != nil {
+			return "", err
+		}
+	}
+
+	for _, f := range record.ContextFiles {
+		if _, err := tx.Exec(
+			`INSERT INTO context_files (debate_id, path, content) VALUES (?, ?, ?)`,
+			id, f.Path, f.Content,
+		); err != nil {
+			return "", err
+		}
+	}
+
+	for _, tag := range record.Tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO tags (debate_id, tag) VALUES (?, ?)`, id, tag); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// ForkDebate starts a new debate named name from the first upTo messages of
+// debate parentID, with its project and context files, and links it to the
+// parent. upTo <= 0 copies every message. Returns the new debate's ID.
+func (s *Store) ForkDebate(parentID, name string, upTo int) (string, error) {
+	parent, err := s.GetDebate(parentID)
+	if err != nil {
+		return "", err
+	}
+	if upTo <= 0 {
+		upTo = -1 // SQLite: no limit
+	}
+
+	id := uuid.New().String()[:8]
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`INSERT INTO debates (id, name, project_path, parent_debate_id) VALUES (?, ?, ?, ?)`,
+		id, name, parent.ProjectPath, parentID,
+	); err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO messages (debate_id, source, content, msg_type, round, created_at)
+		 SELECT ?, source, content, msg_type, round, created_at FROM (
+			SELECT * FROM messages WHERE debate_id = ? AND msg_type != ? ORDER BY id LIMIT ?
+		 ) ORDER BY id`,
+		id, parentID, MsgTypeDraft, upTo,
+	); err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO context_files (debate_id, path, content)
+		 SELECT ?, path, content FROM context_files WHERE debate_id = ? ORDER BY id`,
+		id, parentID,
+	); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,223 @@
+// internal/db/exchange_test.go
+package db
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("orig", "Queue Choice", "/src/app")
+	store.AddRoundMessage("orig", "user", "Kafka or NATS?", "user", 1)
+	store.AddRoundMessage("orig", "claude", "NATS for this size.", "model", 1)
+	store.AddRoundMessage("orig", "system", "All models have responded.", "system", 1)
+	store.AddContextFile("orig", "docs/load.md", "10k msgs/s")
+	store.AddTag("orig", "infra")
+
+	record, err := store.ExportDebate("orig")
+	if err != nil {
+		t.Fatalf("ExportDebate() failed: %v", err)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+
+	parsed, err := ParseExportedDebate(data)
+	if err != nil {
+		t.Fatalf("ParseExportedDebate() failed: %v", err)
+	}
+	newID, err := store.ImportDebate(*parsed)
+	if err != nil {
+		t.Fatalf("ImportDebate() failed: %v", err)
+	}
+	if newID == "orig" {
+		t.Fatal("import should get a new ID")
+	}
+
+	imported, err := store.ExportDebate(newID)
+	if err != nil {
+		t.Fatalf("ExportDebate(%q) failed: %v", newID, err)
+	}
+	if imported.Name != record.Name || imported.ProjectPath != record.ProjectPath {
+		t.Errorf("imported debate = %q at %q, want %q at %q", imported.Name, imported.ProjectPath, record.Name, record.ProjectPath)
+	}
+	if len(imported.Messages) != len(record.Messages) {
+		t.Fatalf("imported %d messages, want %d", len(imported.Messages), len(record.Messages))
+	}
+	for i, want := range record.Messages {
+		got := imported.Messages[i]
+		if got.Source != want.Source || got.Content != want.Content || got.MsgType != want.MsgType || got.Round != want.Round {
+			t.Errorf("message %d = %+v, want %+v", i, got, want)
+		}
+		if !got.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("message %d created at %v, want %v", i, got.CreatedAt, want.CreatedAt)
+		}
+	}
+	if len(imported.ContextFiles) != 1 || imported.ContextFiles[0] != record.ContextFiles[0] {
+		t.Errorf("context files = %+v, want %+v", imported.ContextFiles, record.ContextFiles)
+	}
+	if len(imported.Tags) != 1 || imported.Tags[0] != "infra" {
+		t.Errorf("tags = %v, want [infra]", imported.Tags)
+	}
+}
+
+func TestExportImport_SkipsDrafts(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("orig", "Queue Choice", "")
+	store.AddRoundMessage("orig", "user", "Kafka or NATS?", "user", 1)
+	store.AddRoundMessage("orig", "claude", "NATS for this size.", "model", 1)
+	if _, err := store.SaveDraft(0, "orig", "gpt", "Kafka beca", 1); err != nil {
+		t.Fatalf("SaveDraft() failed: %v", err)
+	}
+
+	record, err := store.ExportDebate("orig")
+	if err != nil {
+		t.Fatalf("ExportDebate() failed: %v", err)
+	}
+	if len(record.Messages) != 2 {
+		t.Errorf("exported %d messages, want 2 without the draft", len(record.Messages))
+	}
+
+	// Exports written before drafts were left out may still contain them
+	record.Messages = append(record.Messages, ExportedMessage{Source: "gpt", Content: "Kafka beca", MsgType: MsgTypeDraft, Round: 1})
+	newID, err := store.ImportDebate(*record)
+	if err != nil {
+		t.Fatalf("ImportDebate() failed: %v", err)
+	}
+	messages, err := store.GetMessages(newID)
+	if err != nil {
+		t.Fatalf("GetMessages() failed: %v", err)
+	}
+	for _, m := range messages {
+		if m.MsgType == MsgTypeDraft {
+			t.Errorf("imported draft %q from %s", m.Content, m.Source)
+		}
+	}
+	if len(messages) != 2 {
+		t.Errorf("imported %d messages, want 2", len(messages))
+	}
+}
+
+func TestParseExportedDebate_Malformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"not JSON", `# Queue Choice`, "not a roundtable debate export"},
+		{"unknown field", `{"version":1,"name":"x","messages":[],"speaker":"me"}`, "not a roundtable debate export"},
+		{"wrong version", `{"version":9,"name":"x","messages":[]}`, "unsupported export version 9"},
+		{"no name", `{"version":1,"messages":[]}`, "no name"},
+		{"no messages", `{"version":1,"name":"x"}`, "no messages list"},
+		{"message without source", `{"version":1,"name":"x","messages":[{"content":"hi"}]}`, "messages[0]: source is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExportedDebate([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseExportedDebate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExportedDebate_WithoutContext(t *testing.T) {
+	record := &ExportedDebate{
+		ID: "d1",
+		Messages: []ExportedMessage{
+			{Source: "user", Content: "Question", MsgType: "user"},
+			{Source: "system", Content: "Added context: main.go", MsgType: MsgTypeContext},
+			{Source: "claude", Content: "AGREE", MsgType: "model"},
+			{Source: "system", Content: "=== Discussion Round 1 of 3 ===", MsgType: "system"},
+			{Source: "system", Content: "Context files over the 100 KB budget were not sent: big.log", MsgType: MsgTypeContext},
+			{Source: "gpt", Content: "OBJECT: no", MsgType: "model"},
+		},
+	}
+
+	filtered := record.WithoutContext()
+	var got []string
+	for _, m := range filtered.Messages {
+		got = append(got, m.Content)
+	}
+	want := []string{"Question", "AGREE", "=== Discussion Round 1 of 3 ===", "OBJECT: no"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("WithoutContext() messages = %q, want %q", got, want)
+	}
+	if filtered.ID != "d1" || len(record.Messages) != 6 {
+		t.Error("WithoutContext() should copy the record and leave the original alone")
+	}
+}
+
+func TestStore_ForkDebate(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("parent", "Caching", "/work/app")
+	for i, content := range []string{"Question", "AGREE: redis", "OBJECT: memcached", "Follow-up", "AGREE: both"} {
+		source := "claude"
+		if i == 0 || i == 3 {
+			source = "user"
+		}
+		store.AddRoundMessage("parent", source, content, "model", 1+i/3)
+	}
+	store.SaveDraft(0, "parent", "gpt", "still streaming", 2)
+	store.AddContextFile("parent", "main.go", "package main")
+
+	id, err := store.ForkDebate("parent", "Fork of Caching", 3)
+	if err != nil {
+		t.Fatalf("ForkDebate() failed: %v", err)
+	}
+	fork, err := store.GetDebate(id)
+	if err != nil {
+		t.Fatalf("GetDebate(fork) failed: %v", err)
+	}
+	if id == "parent" || fork.Name != "Fork of Caching" || fork.ParentID != "parent" || fork.ProjectPath != "/work/app" || fork.Status != "active" {
+		t.Errorf("fork = %+v", fork)
+	}
+
+	messages, _ := store.GetMessages(id)
+	var got []string
+	for _, m := range messages {
+		got = append(got, m.Source+": "+m.Content)
+	}
+	want := []string{"user: Question", "claude: AGREE: redis", "claude: OBJECT: memcached"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("fork messages = %q, want %q", got, want)
+	}
+	if files, _ := store.GetContextFiles(id); len(files) != 1 || files[0].Path != "main.go" {
+		t.Errorf("fork context files = %+v, want main.go", files)
+	}
+
+	// Without a limit every message is copied, but not drafts
+	id, err = store.ForkDebate("parent", "Whole", 0)
+	if err != nil {
+		t.Fatalf("ForkDebate() failed: %v", err)
+	}
+	if messages, _ := store.GetMessages(id); len(messages) != 5 {
+		t.Errorf("fork of everything has %d messages, want 5", len(messages))
+	}
+	if parent, _ := store.GetMessages("parent"); len(parent) != 6 {
+		t.Errorf("parent has %d messages, want it unchanged at 6", len(parent))
+	}
+
+	if _, err := store.ForkDebate("missing", "Nope", 0); err == nil {
+		t.Error("ForkDebate() of an unknown debate should fail")
+	}
+}
@@ -0,0 +1,190 @@
+// internal/db/migrate.go
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrations upgrade the schema one version at a time; migrations[i] takes
+// the database to version i+1. Append new migrations, never edit old ones.
+// Databases created before versioning report version 0 and replay them all,
+// so each is written to tolerate objects that already exist.
+var migrations = []func(tx *sql.Tx) error{
+	// 1: initial schema
+	execMigration(`
+	CREATE TABLE IF NOT EXISTS debates (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		project_path TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		status TEXT DEFAULT 'active',
+		consensus TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		debate_id TEXT NOT NULL REFERENCES debates(id),
+		source TEXT NOT NULL,
+		content TEXT NOT NULL,
+		msg_type TEXT DEFAULT 'model',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_messages_debate ON messages(debate_id);
+
+	CREATE TABLE IF NOT EXISTS context_files (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		debate_id TEXT NOT NULL REFERENCES debates(id),
+		path TEXT NOT NULL,
+		content TEXT NOT NULL,
+		added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_context_debate ON context_files(debate_id);
+
+	CREATE TABLE IF NOT EXISTS model_state (
+		debate_id TEXT NOT NULL REFERENCES debates(id),
+		model_id TEXT NOT NULL,
+		last_seen_msg INTEGER REFERENCES messages(id),
+		status TEXT DEFAULT 'idle',
+		PRIMARY KEY (debate_id, model_id)
+	);
+	`),
+
+	// 2: round each message belongs to
+	func(tx *sql.Tx) error {
+		return addColumnIfMissing(tx, "messages", "round", "INTEGER DEFAULT 0")
+	},
+
+	// 3: token/cost usage per response
+	execMigration(`
+	CREATE TABLE IF NOT EXISTS usage (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		debate_id TEXT NOT NULL REFERENCES debates(id),
+		model_id TEXT NOT NULL,
+		prompt_tokens INTEGER DEFAULT 0,
+		completion_tokens INTEGER DEFAULT 0,
+		cost REAL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_usage_debate ON usage(debate_id);
+	`),
+
+	// 4: key/value app state (e.g. the active tab)
+	execMigration(`
+	CREATE TABLE IF NOT EXISTS app_state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);
+	`),
+
+	// 5: debate tags
+	execMigration(`
+	CREATE TABLE IF NOT EXISTS tags (
+		debate_id TEXT NOT NULL REFERENCES debates(id),
+		tag TEXT NOT NULL,
+		PRIMARY KEY (debate_id, tag)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_tags_tag ON tags(tag);
+	`),
+
+	// 6: debate a fork was branched from
+	func(tx *sql.Tx) error {
+		return addColumnIfMissing(tx, "debates", "parent_debate_id", "TEXT REFERENCES debates(id)")
+	},
+
+	// 7: when a draft was last checkpointed
+	func(tx *sql.Tx) error {
+		return addColumnIfMissing(tx, "messages", "updated_at", "TIMESTAMP")
+	},
+}
+
+// migrate applies any migrations the database hasn't seen yet, each in its
+// own transaction
+func (s *Store) migrate() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`); err != nil {
+		return err
+	}
+
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this build supports (%d)", current, len(migrations))
+	}
+
+	for i := current; i < len(migrations); i++ {
+		if err := s.applyMigration(i+1, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration and records the new version atomically
+func (s *Store) applyMigration(version int, apply func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := apply(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM schema_version`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version) VALUES (?)`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SchemaVersion returns the database's current schema version; 0 means
+// it predates versioning
+func (s *Store) SchemaVersion() (int, error) {
+	var version sql.NullInt64
+	if err := s.db.QueryRow(`SELECT MAX(version) FROM schema_version`).Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// execMigration returns a migration that runs a fixed set of statements
+func execMigration(statements string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(statements)
+		return err
+	}
+}
+
+// addColumnIfMissing adds a column to an existing table if it isn't there yet
+func addColumnIfMissing(tx *sql.Tx, table, column, decl string) error {
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + decl)
+	return err
+}
@@ -3,15 +3,19 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 type Store struct {
-	db *sql.DB
+	db   *sql.DB
+	path string // Database file; empty for an in-memory store
 }
 
 type Debate struct {
@@ -22,6 +26,7 @@ type Debate struct {
 	UpdatedAt   time.Time
 	Status      string // active, resolved, abandoned
 	Consensus   string
+	ParentID    string // Debate this one was forked from, if any
 }
 
 type Message struct {
@@ -30,9 +35,21 @@ type Message struct {
 	Source    string // claude, gpt, gemini, grok, user, system
 	Content   string
 	MsgType   string // model, user, system, tool, meta
+	Round     int    // User-prompt round the message belongs to
 	CreatedAt time.Time
 }
 
+// Usage records tokens and cost spent by one model response
+type Usage struct {
+	ID               int64
+	DebateID         string
+	ModelID          string
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+	CreatedAt        time.Time
+}
+
 type ContextFile struct {
 	ID       int64
 	DebateID string
@@ -41,22 +58,54 @@ type ContextFile struct {
 	AddedAt  time.Time
 }
 
+// DataDirEnv overrides the data directory, taking precedence over XDG_DATA_HOME
+const DataDirEnv = "ROUNDTABLE_DATA_DIR"
+
 func Open() (*Store, error) {
-	dataDir, err := dataDir()
+	dataDir, err := DataDir()
 	if err != nil {
 		return nil, err
 	}
+	return OpenDir(dataDir)
+}
+
+// OpenDir opens debates.db in dir, creating both if needed
+func OpenDir(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return OpenAt(filepath.Join(dir, "debates.db"))
+}
 
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
+// OpenAt opens the database at path, creating it if needed
+func OpenAt(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+	store, err := openDB(db)
+	if err != nil {
 		return nil, err
 	}
+	store.path = path
+	return store, nil
+}
 
-	dbPath := filepath.Join(dataDir, "debates.db")
-	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL")
+// OpenInMemory opens a database that is never written to disk and is gone
+// once the store is closed
+func OpenInMemory() (*Store, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
 	if err != nil {
 		return nil, err
 	}
+	// Every connection to :memory: is a separate database; keep just one
+	db.SetMaxOpenConns(1)
+	return openDB(db)
+}
 
+// openDB wraps an opened database and brings its schema up to date
+func openDB(db *sql.DB) (*Store, error) {
 	store := &Store{db: db}
 	if err := store.migrate(); err != nil {
 		db.Close()
@@ -66,7 +115,12 @@ func Open() (*Store, error) {
 	return store, nil
 }
 
-func dataDir() (string, error) {
+// DataDir returns the directory Open keeps debates.db in: $ROUNDTABLE_DATA_DIR,
+// else $XDG_DATA_HOME/roundtable, else ~/.local/share/roundtable
+func DataDir() (string, error) {
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		return dir, nil
+	}
 	dataHome := os.Getenv("XDG_DATA_HOME")
 	if dataHome == "" {
 		home, err := os.UserHomeDir()
@@ -78,49 +132,9 @@ func dataDir() (string, error) {
 	return filepath.Join(dataHome, "roundtable"), nil
 }
 
-func (s *Store) migrate() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS debates (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		project_path TEXT,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		status TEXT DEFAULT 'active',
-		consensus TEXT
-	);
-
-	CREATE TABLE IF NOT EXISTS messages (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		debate_id TEXT NOT NULL REFERENCES debates(id),
-		source TEXT NOT NULL,
-		content TEXT NOT NULL,
-		msg_type TEXT DEFAULT 'model',
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_messages_debate ON messages(debate_id);
-
-	CREATE TABLE IF NOT EXISTS context_files (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		debate_id TEXT NOT NULL REFERENCES debates(id),
-		path TEXT NOT NULL,
-		content TEXT NOT NULL,
-		added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_context_debate ON context_files(debate_id);
-
-	CREATE TABLE IF NOT EXISTS model_state (
-		debate_id TEXT NOT NULL REFERENCES debates(id),
-		model_id TEXT NOT NULL,
-		last_seen_msg INTEGER REFERENCES messages(id),
-		status TEXT DEFAULT 'idle',
-		PRIMARY KEY (debate_id, model_id)
-	);
-	`
-	_, err := s.db.Exec(schema)
-	return err
+// Path returns the database file, or "" if the store is in memory
+func (s *Store) Path() string {
+	return s.path
 }
 
 func (s *Store) Close() error {
@@ -139,27 +153,55 @@ func (s *Store) CreateDebate(id, name, projectPath string) error {
 // GetDebate retrieves a debate by ID
 func (s *Store) GetDebate(id string) (*Debate, error) {
 	row := s.db.QueryRow(
-		`SELECT id, name, project_path, created_at, updated_at, status, consensus
+		`SELECT id, name, project_path, created_at, updated_at, status, consensus, parent_debate_id
 		 FROM debates WHERE id = ?`, id,
 	)
 
 	var d Debate
-	var projectPath, consensus sql.NullString
-	err := row.Scan(&d.ID, &d.Name, &projectPath, &d.CreatedAt, &d.UpdatedAt, &d.Status, &consensus)
+	var projectPath, consensus, parentID sql.NullString
+	err := row.Scan(&d.ID, &d.Name, &projectPath, &d.CreatedAt, &d.UpdatedAt, &d.Status, &consensus, &parentID)
 	if err != nil {
 		return nil, err
 	}
 	d.ProjectPath = projectPath.String
 	d.Consensus = consensus.String
+	d.ParentID = parentID.String
 	return &d, nil
 }
 
 // ListDebates returns all debates ordered by update time
 func (s *Store) ListDebates() ([]Debate, error) {
-	rows, err := s.db.Query(
-		`SELECT id, name, project_path, created_at, updated_at, status, consensus
+	return s.queryDebates(
+		`SELECT id, name, project_path, created_at, updated_at, status, consensus, parent_debate_id
 		 FROM debates ORDER BY updated_at DESC`,
 	)
+}
+
+// ListDebatesByTag lists debates carrying tag, most recently updated first
+func (s *Store) ListDebatesByTag(tag string) ([]Debate, error) {
+	return s.queryDebates(
+		`SELECT d.id, d.name, d.project_path, d.created_at, d.updated_at, d.status, d.consensus, d.parent_debate_id
+		 FROM debates d JOIN tags t ON t.debate_id = d.id
+		 WHERE t.tag = ? ORDER BY d.updated_at DESC`,
+		tag,
+	)
+}
+
+// ListDebatesByProject lists debates started in projectPath, most recently
+// updated first. Debates with no project, such as those saved before debates
+// recorded one, are listed for every project.
+func (s *Store) ListDebatesByProject(projectPath string) ([]Debate, error) {
+	return s.queryDebates(
+		`SELECT id, name, project_path, created_at, updated_at, status, consensus, parent_debate_id
+		 FROM debates WHERE project_path = ? OR project_path = '' OR project_path IS NULL
+		 ORDER BY updated_at DESC`,
+		projectPath,
+	)
+}
+
+// queryDebates runs a query selecting debate columns and scans the rows
+func (s *Store) queryDebates(query string, args ...any) ([]Debate, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -168,22 +210,35 @@ func (s *Store) ListDebates() ([]Debate, error) {
 	var debates []Debate
 	for rows.Next() {
 		var d Debate
-		var projectPath, consensus sql.NullString
-		if err := rows.Scan(&d.ID, &d.Name, &projectPath, &d.CreatedAt, &d.UpdatedAt, &d.Status, &consensus); err != nil {
+		var projectPath, consensus, parentID sql.NullString
+		if err := rows.Scan(&d.ID, &d.Name, &projectPath, &d.CreatedAt, &d.UpdatedAt, &d.Status, &consensus, &parentID); err != nil {
 			return nil, err
 		}
 		d.ProjectPath = projectPath.String
 		d.Consensus = consensus.String
+		d.ParentID = parentID.String
 		debates = append(debates, d)
 	}
 	return debates, rows.Err()
 }
 
+// CountMessages returns how many messages a debate has
+func (s *Store) CountMessages(debateID string) (int, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM messages WHERE debate_id = ?`, debateID).Scan(&n)
+	return n, err
+}
+
 // AddMessage adds a message to a debate
 func (s *Store) AddMessage(debateID, source, content, msgType string) (int64, error) {
+	return s.AddRoundMessage(debateID, source, content, msgType, 0)
+}
+
+// AddRoundMessage adds a message to a debate, tagged with its round
+func (s *Store) AddRoundMessage(debateID, source, content, msgType string, round int) (int64, error) {
 	result, err := s.db.Exec(
-		`INSERT INTO messages (debate_id, source, content, msg_type) VALUES (?, ?, ?, ?)`,
-		debateID, source, content, msgType,
+		`INSERT INTO messages (debate_id, source, content, msg_type, round) VALUES (?, ?, ?, ?, ?)`,
+		debateID, source, content, msgType, round,
 	)
 	if err != nil {
 		return 0, err
@@ -195,13 +250,104 @@ func (s *Store) AddMessage(debateID, source, content, msgType string) (int64, er
 	return result.LastInsertId()
 }
 
+// MsgTypeDraft marks a response saved while it was still streaming
+const MsgTypeDraft = "draft"
+
+// MsgTypeContext marks system notes about context injected into the
+// prompts, such as context files added, rather than the discussion itself
+const MsgTypeContext = "context"
+
+// MsgTypeStopped marks a model response cut short because the other models
+// reached consensus; it isn't the model's position for the round
+const MsgTypeStopped = "stopped"
+
+// SaveDraft checkpoints a response that is still streaming. With id 0 it
+// adds a draft message; otherwise it replaces the content of draft id and
+// notes that it's still live. Returns the draft's id.
+func (s *Store) SaveDraft(id int64, debateID, source, content string, round int) (int64, error) {
+	if id == 0 {
+		return s.AddRoundMessage(debateID, source, content, MsgTypeDraft, round)
+	}
+	_, err := s.db.Exec(
+		`UPDATE messages SET content = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND msg_type = ?`,
+		content, id, MsgTypeDraft,
+	)
+	return id, err
+}
+
+// FinalizeDraft saves a draft's final content and turns it into a regular
+// message of msgType
+func (s *Store) FinalizeDraft(id int64, content, msgType string) error {
+	res, err := s.db.Exec(
+		`UPDATE messages SET content = ?, msg_type = ? WHERE id = ? AND msg_type = ?`,
+		content, msgType, id, MsgTypeDraft,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	return fmt.Errorf("no draft message %d", id)
+}
+
+// GetDrafts returns every message still marked as a draft, e.g. responses
+// that were streaming when Roundtable crashed
+func (s *Store) GetDrafts() ([]Message, error) {
+	return s.queryMessages(
+		`SELECT id, debate_id, source, content, msg_type, round, created_at
+		 FROM messages WHERE msg_type = ? ORDER BY id`,
+		MsgTypeDraft,
+	)
+}
+
+// GetStaleDrafts returns the drafts not checkpointed for at least idle, i.e.
+// left by a session that ended, not ones another running session is
+// still streaming
+func (s *Store) GetStaleDrafts(idle time.Duration) ([]Message, error) {
+	return s.queryMessages(
+		`SELECT id, debate_id, source, content, msg_type, round, created_at
+		 FROM messages WHERE msg_type = ? AND COALESCE(updated_at, created_at) <= datetime('now', ?)
+		 ORDER BY id`,
+		MsgTypeDraft, fmt.Sprintf("-%d seconds", int(idle.Seconds())),
+	)
+}
+
 // GetMessages retrieves all messages for a debate
 func (s *Store) GetMessages(debateID string) ([]Message, error) {
-	rows, err := s.db.Query(
-		`SELECT id, debate_id, source, content, msg_type, created_at
+	return s.queryMessages(
+		`SELECT id, debate_id, source, content, msg_type, round, created_at
 		 FROM messages WHERE debate_id = ? ORDER BY id`,
 		debateID,
 	)
+}
+
+// GetMessagesTail retrieves the last n messages for a debate, oldest first
+func (s *Store) GetMessagesTail(debateID string, n int) ([]Message, error) {
+	return s.GetMessagesBefore(debateID, math.MaxInt64, n)
+}
+
+// GetMessagesBefore retrieves up to n messages older than the message with
+// beforeID, oldest first. n <= 0 means no limit.
+func (s *Store) GetMessagesBefore(debateID string, beforeID int64, n int) ([]Message, error) {
+	if n <= 0 {
+		n = -1 // SQLite: no limit
+	}
+	messages, err := s.queryMessages(
+		`SELECT id, debate_id, source, content, msg_type, round, created_at
+		 FROM messages WHERE debate_id = ? AND id < ? ORDER BY id DESC LIMIT ?`,
+		debateID, beforeID, n,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// queryMessages runs a query selecting message columns and scans the rows
+func (s *Store) queryMessages(query string, args ...any) ([]Message, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +356,7 @@ func (s *Store) GetMessages(debateID string) ([]Message, error) {
 	var messages []Message
 	for rows.Next() {
 		var m Message
-		if err := rows.Scan(&m.ID, &m.DebateID, &m.Source, &m.Content, &m.MsgType, &m.CreatedAt); err != nil {
+		if err := rows.Scan(&m.ID, &m.DebateID, &m.Source, &m.Content, &m.MsgType, &m.Round, &m.CreatedAt); err != nil {
 			return nil, err
 		}
 		messages = append(messages, m)
@@ -227,6 +373,22 @@ func (s *Store) AddContextFile(debateID, path, content string) error {
 	return err
 }
 
+// UpdateContextFile replaces the stored content of a debate's context file,
+// adding the file if the debate doesn't have it yet
+func (s *Store) UpdateContextFile(debateID, path, content string) error {
+	res, err := s.db.Exec(
+		`UPDATE context_files SET content = ? WHERE debate_id = ? AND path = ?`,
+		content, debateID, path,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	return s.AddContextFile(debateID, path, content)
+}
+
 // GetContextFiles retrieves all context files for a debate
 func (s *Store) GetContextFiles(debateID string) ([]ContextFile, error) {
 	rows, err := s.db.Query(
@@ -259,6 +421,21 @@ func (s *Store) UpdateDebateStatus(id, status, consensus string) error {
 	return err
 }
 
+// ReactivateDebate marks a closed (abandoned) debate active again
+func (s *Store) ReactivateDebate(id string) error {
+	res, err := s.db.Exec(
+		`UPDATE debates SET status = 'active', updated_at = CURRENT_TIMESTAMP WHERE id = ? AND status = 'abandoned'`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	return fmt.Errorf("debate %s is not closed", id)
+}
+
 // UpdateDebateName updates the name of a debate
 func (s *Store) UpdateDebateName(id, name string) error {
 	_, err := s.db.Exec(
@@ -276,3 +453,100 @@ func (s *Store) RemoveContextFile(debateID, path string) error {
 	)
 	return err
 }
+
+// AddTag tags a debate; adding a tag it already has is a no-op
+func (s *Store) AddTag(debateID, tag string) error {
+	_, err := s.db.Exec(
+		`INSERT OR IGNORE INTO tags (debate_id, tag) VALUES (?, ?)`,
+		debateID, tag,
+	)
+	return err
+}
+
+// RemoveTag removes a tag from a debate
+func (s *Store) RemoveTag(debateID, tag string) error {
+	_, err := s.db.Exec(
+		`DELETE FROM tags WHERE debate_id = ? AND tag = ?`,
+		debateID, tag,
+	)
+	return err
+}
+
+// GetTags returns a debate's tags, sorted
+func (s *Store) GetTags(debateID string) ([]string, error) {
+	rows, err := s.db.Query(
+		`SELECT tag FROM tags WHERE debate_id = ? ORDER BY tag`,
+		debateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// AddUsage records the tokens and cost of a single model response
+func (s *Store) AddUsage(debateID, modelID string, promptTokens, completionTokens int, cost float64) error {
+	_, err := s.db.Exec(
+		`INSERT INTO usage (debate_id, model_id, prompt_tokens, completion_tokens, cost) VALUES (?, ?, ?, ?, ?)`,
+		debateID, modelID, promptTokens, completionTokens, cost,
+	)
+	return err
+}
+
+// GetUsage retrieves all usage records for a debate
+func (s *Store) GetUsage(debateID string) ([]Usage, error) {
+	rows, err := s.db.Query(
+		`SELECT id, debate_id, model_id, prompt_tokens, completion_tokens, cost, created_at
+		 FROM usage WHERE debate_id = ? ORDER BY id`,
+		debateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Usage
+	for rows.Next() {
+		var u Usage
+		if err := rows.Scan(&u.ID, &u.DebateID, &u.ModelID, &u.PromptTokens, &u.CompletionTokens, &u.Cost, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, u)
+	}
+	return records, rows.Err()
+}
+
+// App state keys
+const (
+	StateActiveDebate = "active_debate" // ID of the debate whose tab was last active
+)
+
+// SetState stores a small piece of app state under key, replacing any previous value
+func (s *Store) SetState(key, value string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO app_state (key, value) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		key, value,
+	)
+	return err
+}
+
+// GetState returns the value stored under key, or "" if there is none
+func (s *Store) GetState(key string) (string, error) {
+	var value string
+	err := s.db.QueryRow(`SELECT value FROM app_state WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
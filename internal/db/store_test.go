@@ -2,8 +2,14 @@
 package db
 
 import (
+	"database/sql"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestStore(t *testing.T) {
@@ -16,8 +22,143 @@ func TestStore(t *testing.T) {
 	}
 	defer store.Close()
 
+	testStoreCRUD(t, store)
+}
+
+func TestStore_InMemory(t *testing.T) {
+	dataHome := t.TempDir()
+	os.Setenv("XDG_DATA_HOME", dataHome)
+
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	testStoreCRUD(t, store)
+
+	// Nothing should have been written to disk
+	entries, err := os.ReadDir(dataHome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no files in the data dir, found %d", len(entries))
+	}
+}
+
+func TestOpenAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.db")
+
+	store, err := OpenAt(path)
+	if err != nil {
+		t.Fatalf("OpenAt() failed: %v", err)
+	}
+	if err := store.CreateDebate("d", "Kept", ""); err != nil {
+		t.Fatalf("CreateDebate() failed: %v", err)
+	}
+	store.Close()
+
+	// Reopening the same path sees the data
+	store, err = OpenAt(path)
+	if err != nil {
+		t.Fatalf("OpenAt() reopen failed: %v", err)
+	}
+	defer store.Close()
+	if d, err := store.GetDebate("d"); err != nil || d.Name != "Kept" {
+		t.Errorf("Expected debate to persist, got %+v, %v", d, err)
+	}
+}
+
+func TestOpenDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "project-a")
+
+	store, err := OpenDir(dir)
+	if err != nil {
+		t.Fatalf("OpenDir() failed: %v", err)
+	}
+	store.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, "debates.db")); err != nil {
+		t.Errorf("Expected debates.db in %s: %v", dir, err)
+	}
+}
+
+func TestOpen_DataDirEnv(t *testing.T) {
+	dataHome := t.TempDir()
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv(DataDirEnv, dir)
+
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	store.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, "debates.db")); err != nil {
+		t.Errorf("Expected debates.db in %s: %v", dir, err)
+	}
+	if entries, _ := os.ReadDir(dataHome); len(entries) != 0 {
+		t.Errorf("Expected nothing under XDG_DATA_HOME, found %d entries", len(entries))
+	}
+}
+
+func TestDataDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		dataHome string
+		want     string
+	}{
+		{"override wins", "/srv/roundtable", "/xdg", "/srv/roundtable"},
+		{"XDG_DATA_HOME", "", "/xdg", "/xdg/roundtable"},
+		{"home fallback", "", "", "/home/tester/.local/share/roundtable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", "/home/tester")
+			t.Setenv(DataDirEnv, tt.override)
+			t.Setenv("XDG_DATA_HOME", tt.dataHome)
+
+			got, err := DataDir()
+			if err != nil {
+				t.Fatalf("DataDir() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DataDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Path(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debates.db")
+	store, err := OpenAt(path)
+	if err != nil {
+		t.Fatalf("OpenAt() failed: %v", err)
+	}
+	defer store.Close()
+	if store.Path() != path {
+		t.Errorf("Path() = %q, want %q", store.Path(), path)
+	}
+
+	mem, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer mem.Close()
+	if mem.Path() != "" {
+		t.Errorf("in-memory Path() = %q, want empty", mem.Path())
+	}
+}
+
+// testStoreCRUD runs create/read/update/delete operations against a fresh store
+func testStoreCRUD(t *testing.T, store *Store) {
+	t.Helper()
+
 	// Test create debate
-	err = store.CreateDebate("test-1", "Test Debate", "/home/test/project")
+	err := store.CreateDebate("test-1", "Test Debate", "/home/test/project")
 	if err != nil {
 		t.Fatalf("CreateDebate() failed: %v", err)
 	}
@@ -117,4 +258,461 @@ func TestStore(t *testing.T) {
 	if len(contextFiles) != 0 {
 		t.Errorf("Expected 0 context files after removal, got %d", len(contextFiles))
 	}
+
+	// Test usage tracking
+	if err := store.AddUsage("test-1", "claude", 120, 40, 0.01); err != nil {
+		t.Fatalf("AddUsage() failed: %v", err)
+	}
+	if err := store.AddUsage("test-1", "gpt", 80, 30, 0); err != nil {
+		t.Fatalf("AddUsage() failed: %v", err)
+	}
+	usage, err := store.GetUsage("test-1")
+	if err != nil {
+		t.Fatalf("GetUsage() failed: %v", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("Expected 2 usage records, got %d", len(usage))
+	}
+	if usage[0].ModelID != "claude" || usage[0].PromptTokens != 120 || usage[0].CompletionTokens != 40 || usage[0].Cost != 0.01 {
+		t.Errorf("Unexpected first usage record: %+v", usage[0])
+	}
+}
+
+func TestStore_MigratesOldSchema(t *testing.T) {
+	dataHome := t.TempDir()
+	os.Setenv("XDG_DATA_HOME", dataHome)
+
+	// Create a database with the original, unversioned schema (no round column)
+	dir := filepath.Join(dataHome, "roundtable")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old, err := sql.Open("sqlite3", filepath.Join(dir, "debates.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = old.Exec(`
+	CREATE TABLE debates (id TEXT PRIMARY KEY, name TEXT NOT NULL, project_path TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		status TEXT DEFAULT 'active', consensus TEXT);
+	CREATE TABLE messages (id INTEGER PRIMARY KEY AUTOINCREMENT, debate_id TEXT NOT NULL,
+		source TEXT NOT NULL, content TEXT NOT NULL, msg_type TEXT DEFAULT 'model',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+	INSERT INTO debates (id, name) VALUES ('old', 'Old Debate');
+	INSERT INTO messages (debate_id, source, content) VALUES ('old', 'user', 'from before rounds');
+	`)
+	old.Close()
+	if err != nil {
+		t.Fatalf("failed to create old schema: %v", err)
+	}
+
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() on old schema failed: %v", err)
+	}
+	defer store.Close()
+
+	version, err := store.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion() failed: %v", err)
+	}
+	if version != len(migrations) {
+		t.Errorf("Expected schema version %d after migrating, got %d", len(migrations), version)
+	}
+
+	// Tables added by later migrations exist
+	if err := store.AddUsage("old", "claude", 1, 1, 0); err != nil {
+		t.Errorf("AddUsage() on migrated database failed: %v", err)
+	}
+	if err := store.SetState(StateActiveDebate, "old"); err != nil {
+		t.Errorf("SetState() on migrated database failed: %v", err)
+	}
+
+	if _, err := store.AddRoundMessage("old", "claude", "new reply", "model", 2); err != nil {
+		t.Fatalf("AddRoundMessage() failed: %v", err)
+	}
+
+	messages, err := store.GetMessages("old")
+	if err != nil {
+		t.Fatalf("GetMessages() failed: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].Round != 0 {
+		t.Errorf("Expected pre-migration message in round 0, got %d", messages[0].Round)
+	}
+	if messages[1].Round != 2 {
+		t.Errorf("Expected round 2, got %d", messages[1].Round)
+	}
+}
+
+func TestStore_State(t *testing.T) {
+	os.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer store.Close()
+
+	got, err := store.GetState(StateActiveDebate)
+	if err != nil {
+		t.Fatalf("GetState() on missing key failed: %v", err)
+	}
+	if got != "" {
+		t.Errorf("Expected empty value for missing key, got %q", got)
+	}
+
+	for _, id := range []string{"debate-1", "debate-2"} {
+		if err := store.SetState(StateActiveDebate, id); err != nil {
+			t.Fatalf("SetState(%q) failed: %v", id, err)
+		}
+		got, err := store.GetState(StateActiveDebate)
+		if err != nil {
+			t.Fatalf("GetState() failed: %v", err)
+		}
+		if got != id {
+			t.Errorf("Expected active debate %q, got %q", id, got)
+		}
+	}
+}
+
+func TestStore_ListDebatesByProject(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("a1", "API versioning", "/src/api")
+	store.CreateDebate("w1", "Bundler", "/src/web")
+	store.CreateDebate("a2", "Rate limits", "/src/api")
+	store.CreateDebate("n1", "Unscoped", "")
+	if _, err := store.db.Exec(`INSERT INTO debates (id, name) VALUES ('n2', 'Legacy')`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Debates without a project, e.g. from before projects were recorded,
+	// show up under every project
+	debates, err := store.ListDebatesByProject("/src/api")
+	if err != nil {
+		t.Fatalf("ListDebatesByProject() failed: %v", err)
+	}
+	var ids []string
+	for _, d := range debates {
+		ids = append(ids, d.ID)
+	}
+	slices.Sort(ids)
+	if strings.Join(ids, ",") != "a1,a2,n1,n2" {
+		t.Errorf("Expected debates [a1 a2 n1 n2] for /src/api, got %v", ids)
+	}
+
+	debates, err = store.ListDebatesByProject("/src/other")
+	if err != nil {
+		t.Fatalf("ListDebatesByProject() failed: %v", err)
+	}
+	if len(debates) != 2 {
+		t.Errorf("Expected only the unscoped debates for /src/other, got %d", len(debates))
+	}
+}
+
+func TestStore_UpdateContextFile(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("d1", "Schema", "")
+	for _, content := range []string{"v1", "v2"} {
+		if err := store.UpdateContextFile("d1", "schema.sql", content); err != nil {
+			t.Fatalf("UpdateContextFile() failed: %v", err)
+		}
+	}
+
+	files, err := store.GetContextFiles("d1")
+	if err != nil {
+		t.Fatalf("GetContextFiles() failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Content != "v2" {
+		t.Errorf("Expected one schema.sql with v2, got %+v", files)
+	}
+}
+
+func TestStore_ReactivateDebate(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("d1", "Closed", "")
+	if err := store.ReactivateDebate("d1"); err == nil {
+		t.Error("expected an error reactivating a debate that isn't closed")
+	}
+
+	store.UpdateDebateStatus("d1", "abandoned", "")
+	if err := store.ReactivateDebate("d1"); err != nil {
+		t.Fatalf("ReactivateDebate() failed: %v", err)
+	}
+	d, err := store.GetDebate("d1")
+	if err != nil {
+		t.Fatalf("GetDebate() failed: %v", err)
+	}
+	if d.Status != "active" {
+		t.Errorf("Status = %q, want active", d.Status)
+	}
+
+	if err := store.ReactivateDebate("missing"); err == nil {
+		t.Error("expected an error for an unknown debate")
+	}
+}
+
+func TestStore_DraftUpsertThenFinalize(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+	store.CreateDebate("d1", "Streaming", "")
+
+	id, err := store.SaveDraft(0, "d1", "claude", "Part", 2)
+	if err != nil || id == 0 {
+		t.Fatalf("SaveDraft() insert = %d, %v", id, err)
+	}
+	if again, err := store.SaveDraft(id, "d1", "claude", "Partial answer", 2); err != nil || again != id {
+		t.Fatalf("SaveDraft() update = %d, %v; want the same draft %d", again, err, id)
+	}
+
+	drafts, err := store.GetDrafts()
+	if err != nil {
+		t.Fatalf("GetDrafts() failed: %v", err)
+	}
+	if len(drafts) != 1 || drafts[0].Content != "Partial answer" || drafts[0].Round != 2 {
+		t.Fatalf("expected one updated draft, got %+v", drafts)
+	}
+
+	if err := store.FinalizeDraft(id, "Partial answer, finished.", "model"); err != nil {
+		t.Fatalf("FinalizeDraft() failed: %v", err)
+	}
+	if drafts, _ := store.GetDrafts(); len(drafts) != 0 {
+		t.Errorf("expected no drafts after finalizing, got %+v", drafts)
+	}
+	messages, _ := store.GetMessages("d1")
+	if len(messages) != 1 || messages[0].MsgType != "model" || messages[0].Content != "Partial answer, finished." {
+		t.Errorf("expected one final message, got %+v", messages)
+	}
+
+	// A finalized message is no longer a draft
+	if err := store.FinalizeDraft(id, "again", "model"); err == nil {
+		t.Error("expected an error finalizing a message that isn't a draft")
+	}
+}
+
+func TestStore_GetStaleDrafts(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+	store.CreateDebate("d1", "Streaming", "")
+
+	old, _ := store.SaveDraft(0, "d1", "claude", "Left behind", 1)
+	live, _ := store.SaveDraft(0, "d1", "gpt", "Still going", 1)
+	if _, err := store.db.Exec(`UPDATE messages SET created_at = datetime('now', '-1 hour')`); err != nil {
+		t.Fatal(err)
+	}
+	// Checkpointing again marks the draft live despite its age
+	if _, err := store.SaveDraft(live, "d1", "gpt", "Still going strong", 1); err != nil {
+		t.Fatalf("SaveDraft() failed: %v", err)
+	}
+
+	drafts, err := store.GetStaleDrafts(time.Minute)
+	if err != nil {
+		t.Fatalf("GetStaleDrafts() failed: %v", err)
+	}
+	if len(drafts) != 1 || drafts[0].ID != old {
+		t.Errorf("expected only draft %d stale, got %+v", old, drafts)
+	}
+}
+
+func TestStore_CountMessages(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("d1", "Busy", "")
+	store.CreateDebate("d2", "Quiet", "")
+	store.AddMessage("d1", "user", "Kafka or NATS?", "user")
+	store.AddMessage("d1", "claude", "NATS.", "model")
+	store.AddMessage("d1", "system", "All models have responded.", "system")
+
+	for id, want := range map[string]int{"d1": 3, "d2": 0, "missing": 0} {
+		n, err := store.CountMessages(id)
+		if err != nil {
+			t.Fatalf("CountMessages(%q) failed: %v", id, err)
+		}
+		if n != want {
+			t.Errorf("CountMessages(%q) = %d, want %d", id, n, want)
+		}
+	}
+}
+
+func TestStore_Tags(t *testing.T) {
+	store, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("d1", "Auth redesign", "")
+	store.CreateDebate("d2", "Cache layer", "")
+
+	for _, tag := range []string{"backend", "auth", "backend"} {
+		if err := store.AddTag("d1", tag); err != nil {
+			t.Fatalf("AddTag(%q) failed: %v", tag, err)
+		}
+	}
+	store.AddTag("d2", "backend")
+
+	tags, err := store.GetTags("d1")
+	if err != nil {
+		t.Fatalf("GetTags() failed: %v", err)
+	}
+	if strings.Join(tags, ",") != "auth,backend" {
+		t.Errorf("Expected tags [auth backend], got %v", tags)
+	}
+
+	debates, err := store.ListDebatesByTag("backend")
+	if err != nil {
+		t.Fatalf("ListDebatesByTag() failed: %v", err)
+	}
+	if len(debates) != 2 {
+		t.Errorf("Expected 2 debates tagged backend, got %d", len(debates))
+	}
+
+	if err := store.RemoveTag("d1", "auth"); err != nil {
+		t.Fatalf("RemoveTag() failed: %v", err)
+	}
+	debates, err = store.ListDebatesByTag("auth")
+	if err != nil {
+		t.Fatalf("ListDebatesByTag() failed: %v", err)
+	}
+	if len(debates) != 0 {
+		t.Errorf("Expected no debates tagged auth after removal, got %d", len(debates))
+	}
+	tags, _ = store.GetTags("d1")
+	if len(tags) != 1 || tags[0] != "backend" {
+		t.Errorf("Expected tags [backend] after removal, got %v", tags)
+	}
+}
+
+func TestStore_GetMessagesTail(t *testing.T) {
+	os.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.CreateDebate("long", "Long Debate", ""); err != nil {
+		t.Fatalf("CreateDebate() failed: %v", err)
+	}
+	// Another debate's messages must not leak into the tail
+	if err := store.CreateDebate("other", "Other", ""); err != nil {
+		t.Fatalf("CreateDebate() failed: %v", err)
+	}
+	for i := 0; i < 200; i++ {
+		if _, err := store.AddMessage("long", "claude", fmt.Sprintf("message %d", i), "model"); err != nil {
+			t.Fatalf("AddMessage() failed: %v", err)
+		}
+		if i%50 == 0 {
+			store.AddMessage("other", "gemini", "unrelated", "model")
+		}
+	}
+
+	tail, err := store.GetMessagesTail("long", 30)
+	if err != nil {
+		t.Fatalf("GetMessagesTail() failed: %v", err)
+	}
+	if len(tail) != 30 {
+		t.Fatalf("Expected 30 messages, got %d", len(tail))
+	}
+	for i, m := range tail {
+		if want := fmt.Sprintf("message %d", 170+i); m.Content != want {
+			t.Fatalf("tail[%d] = %q, want %q", i, m.Content, want)
+		}
+	}
+
+	// Page backwards from the oldest message in the tail
+	older, err := store.GetMessagesBefore("long", tail[0].ID, 30)
+	if err != nil {
+		t.Fatalf("GetMessagesBefore() failed: %v", err)
+	}
+	if len(older) != 30 || older[0].Content != "message 140" || older[29].Content != "message 169" {
+		t.Errorf("Expected messages 140-169, got %d messages starting %q", len(older), older[0].Content)
+	}
+
+	rest, err := store.GetMessagesBefore("long", older[0].ID, 0)
+	if err != nil {
+		t.Fatalf("GetMessagesBefore() without limit failed: %v", err)
+	}
+	if len(rest) != 140 {
+		t.Errorf("Expected the remaining 140 messages, got %d", len(rest))
+	}
+
+	all, err := store.GetMessagesTail("long", 500)
+	if err != nil {
+		t.Fatalf("GetMessagesTail() failed: %v", err)
+	}
+	if len(all) != 200 {
+		t.Errorf("Expected all 200 messages when n exceeds the count, got %d", len(all))
+	}
+}
+
+func TestStore_MigrationsAreRecorded(t *testing.T) {
+	dataHome := t.TempDir()
+	os.Setenv("XDG_DATA_HOME", dataHome)
+
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	if err := store.CreateDebate("d", "Debate", ""); err != nil {
+		t.Fatalf("CreateDebate() failed: %v", err)
+	}
+	store.Close()
+
+	// Reopening applies nothing new and keeps the data
+	store, err = Open()
+	if err != nil {
+		t.Fatalf("Open() again failed: %v", err)
+	}
+	version, err := store.SchemaVersion()
+	if err != nil || version != len(migrations) {
+		t.Errorf("Expected schema version %d, got %d (%v)", len(migrations), version, err)
+	}
+	if _, err := store.GetDebate("d"); err != nil {
+		t.Errorf("GetDebate() after reopen failed: %v", err)
+	}
+	store.Close()
+
+	// A database from a newer build is refused rather than mangled
+	raw, err := sql.Open("sqlite3", filepath.Join(dataHome, "roundtable", "debates.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = raw.Exec(`UPDATE schema_version SET version = ?`, len(migrations)+1)
+	raw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store, err := Open(); err == nil {
+		store.Close()
+		t.Error("Expected Open() to fail on a newer schema version")
+	}
 }
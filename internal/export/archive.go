@@ -0,0 +1,145 @@
+// internal/export/archive.go
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"roundtable/internal/db"
+)
+
+// ArchiveManifest is written to manifest.json at the root of an archive
+type ArchiveManifest struct {
+	ExportedAt time.Time      `json:"exported_at"`
+	Debates    []ArchiveEntry `json:"debates"`
+}
+
+// ArchiveEntry describes one debate in an archive
+type ArchiveEntry struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Messages  int       `json:"messages"`
+	File      string    `json:"file"`
+}
+
+// ArchiveAll writes every debate in the store to w as a zip of Markdown
+// transcripts plus manifest.json. Debates are read and written one at a
+// time, so memory use doesn't grow with the size of the history.
+func ArchiveAll(store *db.Store, w io.Writer) error {
+	debates, err := store.ListDebates()
+	if err != nil {
+		return fmt.Errorf("list debates: %w", err)
+	}
+
+	zw := zip.NewWriter(w)
+	manifest := ArchiveManifest{ExportedAt: time.Now(), Debates: []ArchiveEntry{}}
+
+	for _, d := range debates {
+		debate, err := loadDebateExport(store, d)
+		if err != nil {
+			return fmt.Errorf("debate %s: %w", d.ID, err)
+		}
+
+		name := archiveFilename(d)
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, ExportDebate(debate)); err != nil {
+			return err
+		}
+
+		manifest.Debates = append(manifest.Debates, ArchiveEntry{
+			ID:        d.ID,
+			Name:      d.Name,
+			Status:    d.Status,
+			CreatedAt: d.CreatedAt,
+			UpdatedAt: d.UpdatedAt,
+			Messages:  len(debate.Messages),
+			File:      name,
+		})
+	}
+
+	f, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// WriteArchive archives every debate in the store to a zip file at path
+func WriteArchive(store *db.Store, path string) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create directory: %w", err)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := ArchiveAll(store, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadDebateExport reads one debate's messages and context file paths
+func loadDebateExport(store *db.Store, d db.Debate) (*DebateExport, error) {
+	messages, err := store.GetMessages(d.ID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := store.GetContextFiles(d.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	debate := &DebateExport{
+		ID:          d.ID,
+		Name:        d.Name,
+		ProjectPath: d.ProjectPath,
+		CreatedAt:   d.CreatedAt,
+	}
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		debate.Messages = append(debate.Messages, DebateMessage{
+			Source:    msg.Source,
+			Content:   msg.Content,
+			Timestamp: msg.CreatedAt,
+		})
+		if msg.Source != "user" && msg.Source != "system" && !seen[msg.Source] {
+			debate.Participants = append(debate.Participants, msg.Source)
+			seen[msg.Source] = true
+		}
+	}
+	for _, f := range files {
+		debate.ContextFiles = append(debate.ContextFiles, f.Path)
+	}
+	return debate, nil
+}
+
+// archiveFilename names a debate's transcript in an archive. The ID prefix
+// keeps debates with the same name and date apart.
+func archiveFilename(d db.Debate) string {
+	id := d.ID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("debates/%s-%s-%s.md", d.CreatedAt.Format("2006-01-02"), sanitizeFilename(d.Name), id)
+}
@@ -0,0 +1,72 @@
+// internal/export/archive_test.go
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"roundtable/internal/db"
+)
+
+func TestArchiveAll(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	store.CreateDebate("debate-one", "Cache Design", "")
+	store.AddMessage("debate-one", "user", "LRU or LFU?", "user")
+	store.AddMessage("debate-one", "claude", "LRU.", "model")
+	store.CreateDebate("debate-two", "Cache Design", "")
+	store.AddMessage("debate-two", "user", "Redis?", "user")
+
+	var buf bytes.Buffer
+	if err := ArchiveAll(store, &buf); err != nil {
+		t.Fatalf("ArchiveAll() failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("archive is not a valid zip: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	if len(files) != 3 {
+		t.Fatalf("expected 2 transcripts and a manifest, got %d entries", len(files))
+	}
+
+	var manifest ArchiveManifest
+	if err := json.Unmarshal([]byte(files["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("bad manifest: %v", err)
+	}
+	if len(manifest.Debates) != 2 {
+		t.Fatalf("manifest lists %d debates, want 2", len(manifest.Debates))
+	}
+	for _, entry := range manifest.Debates {
+		transcript, ok := files[entry.File]
+		if !ok {
+			t.Errorf("manifest names %q, which isn't in the archive", entry.File)
+			continue
+		}
+		if !strings.Contains(transcript, "`"+entry.ID+"`") {
+			t.Errorf("%s doesn't contain debate %s", entry.File, entry.ID)
+		}
+	}
+	if !strings.Contains(files[manifest.Debates[0].File]+files[manifest.Debates[1].File], "> LRU.") {
+		t.Error("expected message content in the transcripts")
+	}
+}
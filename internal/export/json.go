@@ -0,0 +1,32 @@
+// internal/export/json.go
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"roundtable/internal/db"
+)
+
+// WriteDebateJSON writes a debate as indented JSON to
+// baseDir/debates/YYYY-MM-DD-name.json, the format /import reads back
+func WriteDebateJSON(record *db.ExportedDebate, baseDir string) (string, error) {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	debatesDir := filepath.Join(baseDir, "debates")
+	if err := os.MkdirAll(debatesDir, 0755); err != nil {
+		return "", fmt.Errorf("create debates directory: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s-%s.json", record.CreatedAt.Format("2006-01-02"), sanitizeFilename(record.Name))
+	path := filepath.Join(debatesDir, filename)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("write file: %w", err)
+	}
+	return path, nil
+}
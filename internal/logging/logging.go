@@ -0,0 +1,117 @@
+// internal/logging/logging.go
+
+// Package logging sends log output to a size-capped file, since anything
+// written to the terminal would corrupt the TUI
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileName is the log file kept in the data directory
+const FileName = "roundtable.log"
+
+// DefaultMaxSize is the size at which the log is rotated to FileName.1
+const DefaultMaxSize = 5 << 20
+
+// ParseLevel reads a level name: debug, info, warn or error. Empty means info.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", name)
+	}
+}
+
+// Setup makes the default slog logger, and the standard log package, write
+// to path at level and above. Call the returned function to close the file.
+func Setup(path string, level slog.Level, maxSize int64) (func() error, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	w, err := openRotating(path, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})))
+	return w.Close, nil
+}
+
+// Discard drops all log output, from slog and the standard log package
+func Discard() {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+}
+
+// rotatingFile appends to a file, moving it to path.1 once it reaches maxSize
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+}
+
+func openRotating(path string, maxSize int64) (*rotatingFile, error) {
+	w := &rotatingFile{path: path, maxSize: maxSize}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingFile) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+func (w *rotatingFile) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate replaces path.1 with the current file and starts a new one
+func (w *rotatingFile) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *rotatingFile) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
@@ -0,0 +1,85 @@
+// internal/logging/logging_test.go
+package logging
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	path := filepath.Join(t.TempDir(), "logs", FileName)
+
+	closeLog, err := Setup(path, slog.LevelWarn, DefaultMaxSize)
+	if err != nil {
+		t.Fatalf("Setup() failed: %v", err)
+	}
+	slog.Debug("debug detail")
+	slog.Info("routine event")
+	slog.Warn("model failed", "model", "gemini")
+	slog.Error("store unavailable")
+	log.Print("from the log package")
+	if err := closeLog(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("log file not written: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{"model failed", "model=gemini", "store unavailable"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log missing %q:\n%s", want, got)
+		}
+	}
+	for _, dropped := range []string{"debug detail", "routine event", "from the log package"} {
+		if strings.Contains(got, dropped) {
+			t.Errorf("log has %q below the warn level:\n%s", dropped, got)
+		}
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	w, err := openRotating(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	current, _ := os.ReadFile(path)
+	previous, _ := os.ReadFile(path + ".1")
+	if string(current) != "third\n" || string(previous) != "second\n" {
+		t.Errorf("after rotating: current %q, previous %q", current, previous)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"DEBUG", slog.LevelDebug, false},
+		{"warn", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.name)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) = %v, %v", tt.name, got, err)
+		}
+	}
+}
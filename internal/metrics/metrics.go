@@ -0,0 +1,187 @@
+// internal/metrics/metrics.go
+
+// Package metrics counts debates, rounds and model responses and serves them
+// in the Prometheus text format
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Model response outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeTimeout = "timeout"
+	OutcomeError   = "error"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the response latency histogram
+var latencyBuckets = []float64{0.5, 1, 2, 5, 10, 30, 60, 120}
+
+// Default collects the metrics of the running process
+var Default = New()
+
+// Metrics holds the counters and histograms. The zero value isn't usable;
+// call New. Methods are safe on a nil *Metrics, which records nothing.
+type Metrics struct {
+	mu               sync.Mutex
+	debatesStarted   int64
+	roundsDispatched int64
+	consensus        map[string]int64            // "reached" or "blocked"
+	responses        map[string]map[string]int64 // Model ID -> outcome -> count
+	latency          map[string]*histogram       // Model ID -> response time
+}
+
+type histogram struct {
+	counts []int64 // Per bucket in latencyBuckets, not cumulative
+	count  int64
+	sum    float64
+}
+
+func New() *Metrics {
+	return &Metrics{
+		consensus: make(map[string]int64),
+		responses: make(map[string]map[string]int64),
+		latency:   make(map[string]*histogram),
+	}
+}
+
+// DebateStarted counts a new debate
+func (m *Metrics) DebateStarted() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.debatesStarted++
+}
+
+// RoundDispatched counts a round sent to the models
+func (m *Metrics) RoundDispatched() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roundsDispatched++
+}
+
+// ConsensusChecked counts a round's consensus check as reached or blocked
+func (m *Metrics) ConsensusChecked(reached bool) {
+	if m == nil {
+		return
+	}
+	result := "blocked"
+	if reached {
+		result = "reached"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.consensus[result]++
+}
+
+// ModelResponded counts a model's response by outcome and records how long
+// it took
+func (m *Metrics) ModelResponded(model, outcome string, latency time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.responses[model] == nil {
+		m.responses[model] = make(map[string]int64)
+	}
+	m.responses[model][outcome]++
+
+	h := m.latency[model]
+	if h == nil {
+		h = &histogram{counts: make([]int64, len(latencyBuckets))}
+		m.latency[model] = h
+	}
+	seconds := latency.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ModelCount returns how many of model's responses had outcome
+func (m *Metrics) ModelCount(model, outcome string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.responses[model][outcome]
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	counter := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	}
+
+	counter("roundtable_debates_started_total", "Debates created.")
+	fmt.Fprintf(&b, "roundtable_debates_started_total %d\n", m.debatesStarted)
+
+	counter("roundtable_rounds_dispatched_total", "Rounds sent to the models.")
+	fmt.Fprintf(&b, "roundtable_rounds_dispatched_total %d\n", m.roundsDispatched)
+
+	counter("roundtable_consensus_checks_total", "Consensus checks after a round, by result.")
+	for _, result := range []string{"reached", "blocked"} {
+		fmt.Fprintf(&b, "roundtable_consensus_checks_total{result=%q} %d\n", result, m.consensus[result])
+	}
+
+	counter("roundtable_model_responses_total", "Model responses, by model and outcome.")
+	for _, model := range sortedKeys(m.responses) {
+		for _, outcome := range []string{OutcomeSuccess, OutcomeTimeout, OutcomeError} {
+			fmt.Fprintf(&b, "roundtable_model_responses_total{model=%q,outcome=%q} %d\n", model, outcome, m.responses[model][outcome])
+		}
+	}
+
+	const latency = "roundtable_model_response_seconds"
+	fmt.Fprintf(&b, "# HELP %s Time from sending a prompt to a model's final response.\n# TYPE %s histogram\n", latency, latency)
+	for _, model := range sortedKeys(m.latency) {
+		h := m.latency[model]
+		var cumulative int64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "%s_bucket{model=%q,le=\"%g\"} %d\n", latency, model, bound, cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{model=%q,le=\"+Inf\"} %d\n", latency, model, h.count)
+		fmt.Fprintf(&b, "%s_sum{model=%q} %g\n", latency, model, h.sum)
+		fmt.Fprintf(&b, "%s_count{model=%q} %d\n", latency, model, h.count)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Handler serves the metrics, for mounting at /metrics
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+// internal/metrics/metrics_test.go
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteTo(t *testing.T) {
+	m := New()
+	m.DebateStarted()
+	m.RoundDispatched()
+	m.RoundDispatched()
+	m.ConsensusChecked(false)
+	m.ModelResponded("gemini", OutcomeTimeout, 90*time.Second)
+	m.ModelResponded("claude", OutcomeSuccess, 1500*time.Millisecond)
+	m.ModelResponded("claude", OutcomeSuccess, 3*time.Second)
+
+	var b strings.Builder
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"roundtable_debates_started_total 1\n",
+		"roundtable_rounds_dispatched_total 2\n",
+		`roundtable_consensus_checks_total{result="blocked"} 1`,
+		`roundtable_consensus_checks_total{result="reached"} 0`,
+		`roundtable_model_responses_total{model="claude",outcome="success"} 2`,
+		`roundtable_model_responses_total{model="gemini",outcome="timeout"} 1`,
+		`roundtable_model_response_seconds_bucket{model="claude",le="1"} 0`,
+		`roundtable_model_response_seconds_bucket{model="claude",le="2"} 1`,
+		`roundtable_model_response_seconds_bucket{model="claude",le="5"} 2`,
+		`roundtable_model_response_seconds_bucket{model="gemini",le="60"} 0`,
+		`roundtable_model_response_seconds_bucket{model="gemini",le="+Inf"} 1`,
+		`roundtable_model_response_seconds_sum{model="claude"} 4.5`,
+		"# TYPE roundtable_model_response_seconds histogram",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestNilMetricsRecordsNothing(t *testing.T) {
+	var m *Metrics
+	m.DebateStarted()
+	m.RoundDispatched()
+	m.ConsensusChecked(true)
+	m.ModelResponded("claude", OutcomeError, time.Second)
+}
+
+func TestServer(t *testing.T) {
+	m := New()
+	m.DebateStarted()
+
+	s := NewServer(m, 0)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer s.Stop(context.Background())
+
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "roundtable_debates_started_total 1") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if _, err := http.Get("http://" + s.Addr() + "/metrics"); err == nil {
+		t.Error("expected the server to be stopped")
+	}
+}
@@ -0,0 +1,63 @@
+// internal/metrics/server.go
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Server serves /metrics over HTTP on the loopback interface
+type Server struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// NewServer creates a server for m on port; 0 picks a free port
+func NewServer(m *Metrics, port int) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	return &Server{
+		srv: &http.Server{
+			Addr:              net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Start listens and serves in the background until Stop
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server stopped", "err", err)
+		}
+	}()
+	slog.Info("metrics server listening", "addr", ln.Addr().String())
+	return nil
+}
+
+// Addr returns the address the server listens on, once started
+func (s *Server) Addr() string {
+	if s.ln == nil {
+		return ""
+	}
+	return s.ln.Addr().String()
+}
+
+// Stop shuts the server down, waiting for open requests until ctx ends
+func (s *Server) Stop(ctx context.Context) error {
+	if s.ln == nil {
+		return nil
+	}
+	return s.srv.Shutdown(ctx)
+}
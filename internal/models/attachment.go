@@ -0,0 +1,100 @@
+// internal/models/attachment.go
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxAttachmentSize caps the size of an attached image
+const MaxAttachmentSize = 5 << 20
+
+// Attachment is a non-text part of a user message, such as an image
+type Attachment struct {
+	Name      string // Base name of the file, for display
+	MediaType string // e.g. image/png
+	Data      []byte
+}
+
+// LoadImage reads path as an attachment. The file must be a PNG, JPEG, GIF
+// or WebP image no larger than MaxAttachmentSize.
+func LoadImage(path string) (Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	if info.IsDir() {
+		return Attachment{}, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > MaxAttachmentSize {
+		return Attachment{}, fmt.Errorf("%s is %d KB; attachments are limited to %d KB",
+			filepath.Base(path), info.Size()/1024, MaxAttachmentSize/1024)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	mediaType := http.DetectContentType(data)
+	switch mediaType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+	default:
+		return Attachment{}, fmt.Errorf("%s is not an image (detected %s)", filepath.Base(path), mediaType)
+	}
+	return Attachment{Name: filepath.Base(path), MediaType: mediaType, Data: data}, nil
+}
+
+// DataURL returns the attachment as a base64 data: URL
+func (a Attachment) DataURL() string {
+	return "data:" + a.MediaType + ";base64," + base64.StdEncoding.EncodeToString(a.Data)
+}
+
+// promptAttachments returns the attachments on the user message that ends
+// history, which belong with the prompt being sent
+func promptAttachments(history []Message) []Attachment {
+	if len(history) == 0 {
+		return nil
+	}
+	last := history[len(history)-1]
+	if last.Source != "user" {
+		return nil
+	}
+	return last.Attachments
+}
+
+// contentPart is one element of an OpenAI-style content array
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+// chatContent returns text as a plain string, or as a content array with
+// the attachments as image parts when there are any
+func chatContent(text string, attachments []Attachment) any {
+	if len(attachments) == 0 {
+		return text
+	}
+	parts := []contentPart{{Type: "text", Text: text}}
+	for _, a := range attachments {
+		parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: a.DataURL()}})
+	}
+	return parts
+}
+
+// AttachmentNames lists attachments by name, for messages to the user
+func AttachmentNames(attachments []Attachment) string {
+	names := make([]string, len(attachments))
+	for i, a := range attachments {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
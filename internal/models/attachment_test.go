@@ -0,0 +1,121 @@
+// internal/models/attachment_test.go
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// pngHeader is enough of a PNG for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestLoadImage(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"png", write("shot.png", pngHeader), ""},
+		{"text", write("notes.png", []byte("just some text")), "not an image"},
+		{"too large", write("huge.png", append(pngHeader, make([]byte, MaxAttachmentSize)...)), "limited to"},
+		{"directory", dir, "is a directory"},
+		{"missing", filepath.Join(dir, "missing.png"), "no such file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := LoadImage(tt.path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadImage() error = %v", err)
+				}
+				if a.MediaType != "image/png" || a.Name != "shot.png" {
+					t.Errorf("LoadImage() = %s %s, want shot.png image/png", a.Name, a.MediaType)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadImage() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGPTSend_Attachment(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"a cat\"}}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	gpt := NewGPT("key", "gpt-4o")
+	gpt.SetBaseURL(server.URL)
+	history := []Message{{
+		Source:      "user",
+		Content:     "What is in this picture?",
+		Attachments: []Attachment{{Name: "shot.png", MediaType: "image/png", Data: pngHeader}},
+	}}
+	collect(t, gpt.Send(context.Background(), history, "What is in this picture?"))
+
+	var req struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("request body isn't JSON: %v\n%s", err, body)
+	}
+	last := req.Messages[len(req.Messages)-1]
+	var parts []contentPart
+	if err := json.Unmarshal(last.Content, &parts); err != nil {
+		t.Fatalf("prompt content isn't a content array: %s", last.Content)
+	}
+	if len(parts) != 2 || parts[0].Text != "What is in this picture?" || parts[1].ImageURL == nil {
+		t.Fatalf("prompt parts = %+v, want text then image", parts)
+	}
+	want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngHeader)
+	if parts[1].ImageURL.URL != want {
+		t.Errorf("image url = %q, want %q", parts[1].ImageURL.URL, want)
+	}
+
+	// Earlier messages stay plain strings
+	if !bytes.HasPrefix(req.Messages[1].Content, []byte(`"`)) {
+		t.Errorf("history content = %s, want a string", req.Messages[1].Content)
+	}
+}
+
+func TestGPTSend_NoAttachment(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	gpt := NewGPT("key", "gpt-4o")
+	gpt.SetBaseURL(server.URL)
+	collect(t, gpt.Send(context.Background(), nil, "hi"))
+	if !bytes.Contains(body, []byte(`{"role":"user","content":"hi"}`)) {
+		t.Errorf("request body = %s, want the prompt as a plain string", body)
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 )
 
 type ClaudeModel struct {
@@ -17,6 +19,7 @@ type ClaudeModel struct {
 	modelName string
 	sessionID string
 	workDir   string
+	stream    bool
 
 	cmd    *exec.Cmd
 	cancel context.CancelFunc
@@ -41,6 +44,17 @@ func (m *ClaudeModel) SetWorkDir(dir string) {
 	m.workDir = dir
 }
 
+// SetStream makes Send use stream-json output, so text arrives as it's
+// generated instead of all at once when Claude finishes
+func (m *ClaudeModel) SetStream(stream bool) {
+	m.stream = stream
+}
+
+// HealthCheck verifies the claude CLI is installed and runs
+func (m *ClaudeModel) HealthCheck(ctx context.Context) error {
+	return checkCLI(ctx, m.cliPath)
+}
+
 func (m *ClaudeModel) SetSessionID(id string) {
 	m.sessionID = id
 }
@@ -49,6 +63,30 @@ func (m *ClaudeModel) GetSessionID() string {
 	return m.sessionID
 }
 
+// BuildPrompt assembles the full prompt sent to Claude: the preamble
+// explaining the debate format, the conversation so far, and the current prompt
+func BuildPrompt(preamble string, history []Message, prompt string) string {
+	var fullPrompt strings.Builder
+
+	fullPrompt.WriteString(preamble)
+	fullPrompt.WriteString("\n\n")
+
+	// Add conversation history if present
+	if len(history) > 0 {
+		fullPrompt.WriteString("=== CONVERSATION SO FAR ===\n")
+		for _, msg := range history {
+			fullPrompt.WriteString(fmt.Sprintf("[%s]: %s\n\n", msg.Source, msg.Content))
+		}
+		fullPrompt.WriteString("=== END CONVERSATION ===\n\n")
+	}
+
+	// Add the current prompt
+	fullPrompt.WriteString("Current prompt:\n")
+	fullPrompt.WriteString(prompt)
+
+	return fullPrompt.String()
+}
+
 func (m *ClaudeModel) Send(ctx context.Context, history []Message, prompt string) <-chan Chunk {
 	ch := make(chan Chunk, 100)
 
@@ -59,49 +97,32 @@ func (m *ClaudeModel) Send(ctx context.Context, history []Message, prompt string
 
 		// Build command
 		cmdCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
 		m.mu.Lock()
 		m.cancel = cancel
 		m.mu.Unlock()
 
-		// Build the full prompt with debate context
-		var fullPrompt strings.Builder
-
-		// Add system context explaining the debate format
-		fullPrompt.WriteString("You are participating in a multi-model debate called Roundtable. ")
-		fullPrompt.WriteString("Other AI models (GPT, Gemini, Grok) respond alongside you. ")
-		fullPrompt.WriteString("Be direct and substantive. ")
-		fullPrompt.WriteString("If you agree with another model, say AGREE: [reason]. ")
-		fullPrompt.WriteString("If you disagree, say OBJECT: [reason]. ")
-		fullPrompt.WriteString("If you have something to add, say ADD: [point].\n\n")
-
-		// Add conversation history if present
-		if len(history) > 0 {
-			fullPrompt.WriteString("=== CONVERSATION SO FAR ===\n")
-			for _, msg := range history {
-				fullPrompt.WriteString(fmt.Sprintf("[%s]: %s\n\n", msg.Source, msg.Content))
-			}
-			fullPrompt.WriteString("=== END CONVERSATION ===\n\n")
-		}
-
-		// Add the current prompt
-		fullPrompt.WriteString("Current prompt:\n")
-		fullPrompt.WriteString(prompt)
-
 		// Use --print for non-interactive mode with JSON output
 		// This gives a clean single JSON object with the result
 		// NOTE: We do NOT use --continue because we build our own conversation
 		// history from all models. Using --continue would cause Claude to ignore
 		// our injected context and only see its own session history.
-		args := []string{
-			"--print",
-			"--output-format", "json",
-			"-p", fullPrompt.String(),
+		args := []string{"--print", "--output-format", "json"}
+		if m.stream {
+			// Partial messages carry the text delta by delta
+			args = []string{"--print", "--output-format", "stream-json", "--verbose", "--include-partial-messages"}
 		}
+		args = append(args, "-p", BuildPrompt(m.Preamble(), history, prompt))
 
 		cmd := exec.CommandContext(cmdCtx, m.cliPath, args...)
 		if m.workDir != "" {
 			cmd.Dir = m.workDir
 		}
+		// Don't let grandchildren holding the pipes open block Wait forever
+		cmd.WaitDelay = time.Second
+
+		var stderrBuf strings.Builder
+		cmd.Stderr = &stderrBuf
 
 		m.mu.Lock()
 		m.cmd = cmd
@@ -113,57 +134,69 @@ func (m *ClaudeModel) Send(ctx context.Context, history []Message, prompt string
 			return
 		}
 
-		stderr, err := cmd.StderrPipe()
-		if err != nil {
-			ch <- Chunk{Error: fmt.Errorf("stderr pipe: %w", err)}
-			return
-		}
-
 		if err := cmd.Start(); err != nil {
 			ch <- Chunk{Error: fmt.Errorf("start: %w", err)}
 			return
 		}
 
-		// Capture stderr in background
-		var stderrBuf strings.Builder
+		// Read stdout in the background so a process that stalls mid-line
+		// can't block us past cancellation
+		lines := make(chan string)
 		go func() {
-			stderrScanner := bufio.NewScanner(stderr)
-			for stderrScanner.Scan() {
-				stderrBuf.WriteString(stderrScanner.Text())
-				stderrBuf.WriteString("\n")
+			defer close(lines)
+			scanner := bufio.NewScanner(stdout)
+			buf := make([]byte, 0, 1024*1024)
+			scanner.Buffer(buf, 10*1024*1024)
+			for scanner.Scan() {
+				select {
+				case lines <- scanner.Text():
+				case <-cmdCtx.Done():
+					return
+				}
 			}
 		}()
 
-		scanner := bufio.NewScanner(stdout)
-		buf := make([]byte, 0, 1024*1024)
-		scanner.Buffer(buf, 10*1024*1024)
-
-		var fullText strings.Builder
+		var out claudeOutput
 		var gotResponse bool
 
-		for scanner.Scan() {
+	read:
+		for {
 			select {
 			case <-cmdCtx.Done():
+				cmd.Process.Kill()
+				go cmd.Wait()
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					ch <- Chunk{Error: fmt.Errorf("claude timed out"), IsTimeout: true}
+					return
+				}
 				ch <- Chunk{Done: true}
 				return
-			default:
-			}
 
-			line := scanner.Text()
-			chunk := m.parseLine(line, &fullText)
-			if chunk != nil {
-				if chunk.Text != "" {
-					gotResponse = true
+			case line, ok := <-lines:
+				if !ok {
+					break read
+				}
+				chunk := m.parseLine(line, &out)
+				if chunk != nil {
+					// An error event is already a report; don't add the exit status
+					if chunk.Text != "" || chunk.Error != nil {
+						gotResponse = true
+					}
+					ch <- *chunk
 				}
-				ch <- *chunk
 			}
 		}
 
-		cmd.Wait()
+		waitErr := cmd.Wait()
+		stderrText := strings.TrimSpace(stderrBuf.String())
 
-		// If we got no response and there's stderr output, report it as error
-		if !gotResponse && stderrBuf.Len() > 0 {
-			ch <- Chunk{Error: fmt.Errorf("claude stderr: %s", stderrBuf.String())}
+		// If we got no response, report why: exit status first, then stderr
+		if !gotResponse && waitErr != nil {
+			ch <- Chunk{Error: exitError(waitErr, stderrText)}
+			return
+		}
+		if !gotResponse && stderrText != "" {
+			ch <- Chunk{Error: fmt.Errorf("claude stderr: %s", stderrText)}
 			return
 		}
 
@@ -173,13 +206,35 @@ func (m *ClaudeModel) Send(ctx context.Context, history []Message, prompt string
 	return ch
 }
 
-func (m *ClaudeModel) parseLine(line string, fullText *strings.Builder) *Chunk {
+// exitError describes a failed claude run, e.g. "claude exited with code 1: <stderr>"
+func exitError(waitErr error, stderr string) error {
+	msg := "claude: " + waitErr.Error()
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) && exitErr.ExitCode() >= 0 {
+		msg = fmt.Sprintf("claude exited with code %d", exitErr.ExitCode())
+	}
+	if stderr != "" {
+		msg += ": " + stderr
+	}
+	return errors.New(msg)
+}
+
+// claudeOutput is what one claude run has produced so far
+type claudeOutput struct {
+	text     strings.Builder
+	streamed bool // Text arrived as deltas, so whole messages would repeat it
+}
+
+func (m *ClaudeModel) parseLine(line string, out *claudeOutput) *Chunk {
 	var event map[string]any
 	if err := json.Unmarshal([]byte(line), &event); err != nil {
 		// Not valid JSON - might be plain text output, ignore
 		return nil
 	}
+	return m.parseEvent(event, out)
+}
 
+func (m *ClaudeModel) parseEvent(event map[string]any, out *claudeOutput) *Chunk {
 	eventType, _ := event["type"].(string)
 
 	switch eventType {
@@ -197,9 +252,15 @@ func (m *ClaudeModel) parseLine(line string, fullText *strings.Builder) *Chunk {
 			m.sessionID = sid
 		}
 
+		// In stream-json mode the text has already been sent and the
+		// result repeats it
+		if out.text.Len() > 0 {
+			return &Chunk{Done: true, Usage: parseClaudeUsage(event)}
+		}
+
 		if result, ok := event["result"].(string); ok && result != "" {
-			fullText.WriteString(result)
-			return &Chunk{Text: result, Done: true}
+			out.text.WriteString(result)
+			return &Chunk{Text: result, Done: true, Usage: parseClaudeUsage(event)}
 		}
 
 		// Check for errors in result
@@ -211,28 +272,42 @@ func (m *ClaudeModel) parseLine(line string, fullText *strings.Builder) *Chunk {
 			return &Chunk{Error: fmt.Errorf("%s", errMsg)}
 		}
 
-		return &Chunk{Done: true}
+		return &Chunk{Done: true, Usage: parseClaudeUsage(event)}
 
 	case "assistant":
-		// Handle stream-json format (for verbose mode)
+		// A complete message in stream-json mode
+		if out.streamed {
+			return nil
+		}
 		msgData, _ := event["message"].(map[string]any)
 		content, _ := msgData["content"].([]any)
 
+		var text strings.Builder
 		for _, block := range content {
 			b, _ := block.(map[string]any)
 			if blockType, _ := b["type"].(string); blockType == "text" {
-				if text, ok := b["text"].(string); ok {
-					fullText.WriteString(text)
-					return &Chunk{Text: text}
+				if t, ok := b["text"].(string); ok {
+					text.WriteString(t)
 				}
 			}
 		}
+		if text.Len() > 0 {
+			out.text.WriteString(text.String())
+			return &Chunk{Text: text.String()}
+		}
+
+	case "stream_event":
+		// Partial message events wrap the API's streaming events
+		if inner, ok := event["event"].(map[string]any); ok {
+			return m.parseEvent(inner, out)
+		}
 
 	case "content_block_delta":
 		// Handle streaming chunks in verbose mode
 		if delta, ok := event["delta"].(map[string]any); ok {
 			if text, ok := delta["text"].(string); ok {
-				fullText.WriteString(text)
+				out.text.WriteString(text)
+				out.streamed = true
 				return &Chunk{Text: text}
 			}
 		}
@@ -252,6 +327,27 @@ func (m *ClaudeModel) parseLine(line string, fullText *strings.Builder) *Chunk {
 	return nil
 }
 
+// parseClaudeUsage extracts token counts and cost from a result event.
+// Returns nil if the event carries no usage information.
+func parseClaudeUsage(event map[string]any) *Usage {
+	usageData, hasUsage := event["usage"].(map[string]any)
+	cost, hasCost := event["total_cost_usd"].(float64)
+	if !hasUsage && !hasCost {
+		return nil
+	}
+
+	usage := &Usage{Cost: cost}
+	for _, key := range []string{"input_tokens", "cache_creation_input_tokens", "cache_read_input_tokens"} {
+		if n, ok := usageData[key].(float64); ok {
+			usage.PromptTokens += int(n)
+		}
+	}
+	if n, ok := usageData["output_tokens"].(float64); ok {
+		usage.CompletionTokens = int(n)
+	}
+	return usage
+}
+
 func (m *ClaudeModel) Stop() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
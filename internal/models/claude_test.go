@@ -2,8 +2,13 @@
 package models
 
 import (
+	"context"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestClaudeInfo(t *testing.T) {
@@ -28,3 +33,233 @@ func TestClaudeCliExists(t *testing.T) {
 	}
 	// CLI exists, test passes
 }
+
+func TestClaudeParseLine_Usage(t *testing.T) {
+	claude := NewClaude("claude", "opus")
+	var out claudeOutput
+
+	line := `{"type":"result","subtype":"success","result":"done","total_cost_usd":0.0125,` +
+		`"usage":{"input_tokens":100,"cache_read_input_tokens":20,"output_tokens":42}}`
+	chunk := claude.parseLine(line, &out)
+	if chunk == nil || !chunk.Done {
+		t.Fatalf("expected done chunk, got %+v", chunk)
+	}
+	if chunk.Usage == nil {
+		t.Fatal("expected usage on result chunk")
+	}
+	if chunk.Usage.PromptTokens != 120 {
+		t.Errorf("PromptTokens = %d, want 120", chunk.Usage.PromptTokens)
+	}
+	if chunk.Usage.CompletionTokens != 42 {
+		t.Errorf("CompletionTokens = %d, want 42", chunk.Usage.CompletionTokens)
+	}
+	if chunk.Usage.Cost != 0.0125 {
+		t.Errorf("Cost = %v, want 0.0125", chunk.Usage.Cost)
+	}
+}
+
+func TestClaudeHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		cliPath func(t *testing.T) string
+		wantErr bool
+	}{
+		{
+			name:    "working CLI",
+			cliPath: func(t *testing.T) string { return writeScript(t, "echo '1.0.0 (Claude Code)'\n") },
+		},
+		{
+			name:    "failing CLI",
+			cliPath: func(t *testing.T) string { return writeScript(t, "echo 'broken install' >&2\nexit 1\n") },
+			wantErr: true,
+		},
+		{
+			name:    "missing CLI",
+			cliPath: func(t *testing.T) string { return filepath.Join(t.TempDir(), "no-such-claude") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claude := NewClaude(tt.cliPath(t), "opus")
+			err := claude.HealthCheck(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HealthCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	history := []Message{
+		{Source: "user", Content: "Should we use Postgres?"},
+		{Source: "gpt", Content: "AGREE: Postgres fits."},
+	}
+	got := BuildPrompt(DefaultPreamble, history, "Any objections?")
+
+	parts := []string{
+		"You are participating in a multi-model debate called Roundtable.",
+		"=== CONVERSATION SO FAR ===",
+		"[user]: Should we use Postgres?",
+		"[gpt]: AGREE: Postgres fits.",
+		"=== END CONVERSATION ===",
+		"Current prompt:\nAny objections?",
+	}
+	last := -1
+	for _, part := range parts {
+		idx := strings.Index(got, part)
+		if idx < 0 {
+			t.Fatalf("prompt missing %q:\n%s", part, got)
+		}
+		if idx <= last {
+			t.Errorf("%q appears out of order in:\n%s", part, got)
+		}
+		last = idx
+	}
+}
+
+func TestBuildPrompt_NoHistory(t *testing.T) {
+	got := BuildPrompt(DefaultPreamble, nil, "Hello")
+	if strings.Contains(got, "CONVERSATION SO FAR") {
+		t.Error("expected no history block without history")
+	}
+	if !strings.HasSuffix(got, "Current prompt:\nHello") {
+		t.Errorf("expected prompt at the end, got:\n%s", got)
+	}
+}
+
+func TestClaudeSend_StallMidStreamTimesOut(t *testing.T) {
+	// Emits half a JSON line, then hangs without closing stdout
+	script := writeScript(t, `printf '{"type":"result","res'
+sleep 10
+`)
+	claude := NewClaude(script, "opus")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	chunks := collect(t, claude.Send(ctx, nil, "hi"))
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send took %v to give up after a 200ms timeout", elapsed)
+	}
+	if len(chunks) != 1 || chunks[0].Error == nil || !chunks[0].IsTimeout {
+		t.Fatalf("expected a single timeout chunk, got %+v", chunks)
+	}
+}
+
+func TestClaudeSend_ExitCodeInError(t *testing.T) {
+	script := writeScript(t, `echo "not json"
+echo "Invalid API key" >&2
+exit 1
+`)
+	claude := NewClaude(script, "opus")
+
+	chunks := collect(t, claude.Send(context.Background(), nil, "hi"))
+	if len(chunks) != 1 || chunks[0].Error == nil {
+		t.Fatalf("expected a single error chunk, got %+v", chunks)
+	}
+	want := "claude exited with code 1: Invalid API key"
+	if got := chunks[0].Error.Error(); got != want {
+		t.Errorf("error = %q, want %q", got, want)
+	}
+	if chunks[0].IsTimeout {
+		t.Error("a failed exit shouldn't be reported as a timeout")
+	}
+}
+
+func TestClaudeParseLine_StreamJSON(t *testing.T) {
+	const result = "Hello there. AGREE: ship it"
+
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{
+			name: "json",
+			lines: []string{
+				`{"type":"result","subtype":"success","result":"Hello there. AGREE: ship it","usage":{"output_tokens":7}}`,
+			},
+		},
+		{
+			name: "stream-json with partial messages",
+			lines: []string{
+				`{"type":"system","subtype":"init","session_id":"s1"}`,
+				`{"type":"stream_event","event":{"type":"message_start","message":{}}}`,
+				`{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}}`,
+				`{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" there."}}}`,
+				`{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" AGREE: ship it"}}}`,
+				`{"type":"assistant","message":{"content":[{"type":"text","text":"Hello there. AGREE: ship it"}]}}`,
+				`{"type":"result","subtype":"success","result":"Hello there. AGREE: ship it","usage":{"output_tokens":7}}`,
+			},
+		},
+		{
+			name: "stream-json whole messages",
+			lines: []string{
+				`{"type":"system","subtype":"init","session_id":"s1"}`,
+				`{"type":"assistant","message":{"content":[{"type":"text","text":"Hello there."},{"type":"text","text":" AGREE: ship it"}]}}`,
+				`{"type":"result","subtype":"success","result":"Hello there. AGREE: ship it","usage":{"output_tokens":7}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claude := NewClaude("claude", "opus")
+			var out claudeOutput
+			var text strings.Builder
+			var last *Chunk
+			for _, line := range tt.lines {
+				if chunk := claude.parseLine(line, &out); chunk != nil {
+					text.WriteString(chunk.Text)
+					last = chunk
+				}
+			}
+			if got := text.String(); got != result {
+				t.Errorf("assembled text = %q, want %q", got, result)
+			}
+			if last == nil || !last.Done || last.Usage == nil || last.Usage.CompletionTokens != 7 {
+				t.Errorf("expected a final done chunk with usage, got %+v", last)
+			}
+		})
+	}
+}
+
+func TestClaudeSend_Stream(t *testing.T) {
+	// Answers in stream-json when asked to, otherwise as a single result
+	script := writeScript(t, `case "$*" in
+*stream-json*)
+	echo '{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"one "}}}'
+	echo '{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"two"}}}'
+	echo '{"type":"result","result":"one two"}'
+	;;
+*)
+	echo '{"type":"result","result":"one two"}'
+	;;
+esac
+`)
+
+	for _, stream := range []bool{false, true} {
+		claude := NewClaude(script, "opus")
+		claude.SetStream(stream)
+
+		var texts []string
+		for _, chunk := range collect(t, claude.Send(context.Background(), nil, "hi")) {
+			if chunk.Error != nil {
+				t.Fatalf("stream=%v: unexpected error %v", stream, chunk.Error)
+			}
+			if chunk.Text != "" {
+				texts = append(texts, chunk.Text)
+			}
+		}
+
+		want := []string{"one two"}
+		if stream {
+			want = []string{"one ", "two"}
+		}
+		if !slices.Equal(texts, want) {
+			t.Errorf("stream=%v: chunks = %q, want %q", stream, texts, want)
+		}
+	}
+}
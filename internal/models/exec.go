@@ -0,0 +1,229 @@
+// internal/models/exec.go
+package models
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecModel runs an arbitrary external command as a Roundtable participant.
+//
+// Protocol:
+//
+//	stdin:  a single JSON object (ExecRequest), then EOF
+//	stdout: newline-delimited JSON objects (ExecChunk), one per streamed piece
+//
+// Each stdout line is {"text": "...", "done": false, "error": ""}. The done line
+// may also carry {"usage": {"prompt_tokens": N, "completion_tokens": N, "cost": X}}.
+// The process signals completion with {"done": true} or by exiting 0. A non-empty "error"
+// field, or a non-zero exit before "done", is reported as a model error.
+// Lines that aren't valid JSON are ignored so scripts can log freely to stdout.
+type ExecModel struct {
+	BaseModel
+	command string
+	args    []string
+	workDir string
+
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	mu     sync.Mutex
+}
+
+// ExecRequest is written to the subprocess's stdin
+type ExecRequest struct {
+	Model   string           `json:"model"`
+	System  string           `json:"system"` // Debate framing; use as the system prompt
+	Prompt  string           `json:"prompt"`
+	History []ExecHistoryMsg `json:"history"`
+}
+
+// ExecHistoryMsg is a single prior debate message in an ExecRequest
+type ExecHistoryMsg struct {
+	Source  string `json:"source"`
+	Content string `json:"content"`
+	Type    string `json:"type,omitempty"`
+}
+
+// ExecChunk is a single line of subprocess stdout
+type ExecChunk struct {
+	Text  string `json:"text"`
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+	Usage *Usage `json:"usage,omitempty"` // Optional, on the done line
+}
+
+// NewExec creates a model backed by an external command
+func NewExec(id, name, command string, args []string) *ExecModel {
+	if name == "" {
+		name = id
+	}
+	return &ExecModel{
+		BaseModel: NewBaseModel(ModelInfo{
+			ID:      id,
+			Name:    name,
+			CanExec: false,
+			CanRead: true,
+		}),
+		command: command,
+		args:    args,
+	}
+}
+
+func (m *ExecModel) SetWorkDir(dir string) {
+	m.workDir = dir
+}
+
+// HealthCheck verifies the command exists. It isn't run, since the protocol
+// has no side-effect-free invocation.
+func (m *ExecModel) HealthCheck(ctx context.Context) error {
+	if _, err := exec.LookPath(m.command); err != nil {
+		return fmt.Errorf("%s not found", m.command)
+	}
+	return nil
+}
+
+func (m *ExecModel) Send(ctx context.Context, history []Message, prompt string) <-chan Chunk {
+	ch := make(chan Chunk, 100)
+
+	go func() {
+		defer close(ch)
+		m.SetStatus(StatusResponding)
+		defer m.SetStatus(StatusIdle)
+
+		cmdCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		m.mu.Lock()
+		m.cancel = cancel
+		m.mu.Unlock()
+
+		req := ExecRequest{
+			Model:   m.Info().ID,
+			System:  m.Preamble(),
+			Prompt:  prompt,
+			History: make([]ExecHistoryMsg, 0, len(history)),
+		}
+		for _, msg := range history {
+			req.History = append(req.History, ExecHistoryMsg{
+				Source:  msg.Source,
+				Content: msg.Content,
+				Type:    msg.Type,
+			})
+		}
+
+		reqBytes, err := json.Marshal(req)
+		if err != nil {
+			ch <- Chunk{Error: fmt.Errorf("marshal: %w", err)}
+			return
+		}
+
+		cmd := exec.CommandContext(cmdCtx, m.command, m.args...)
+		if m.workDir != "" {
+			cmd.Dir = m.workDir
+		}
+		cmd.Stdin = strings.NewReader(string(reqBytes) + "\n")
+
+		var stderrBuf strings.Builder
+		cmd.Stderr = &stderrBuf
+		// Grandchildren (e.g. a script's own subprocesses) can hold the output
+		// pipes open after the command is killed; don't wait on them forever
+		cmd.WaitDelay = time.Second
+
+		m.mu.Lock()
+		m.cmd = cmd
+		m.mu.Unlock()
+
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			ch <- Chunk{Error: fmt.Errorf("stdout pipe: %w", err)}
+			return
+		}
+
+		if err := cmd.Start(); err != nil {
+			ch <- Chunk{Error: fmt.Errorf("start: %w", err)}
+			return
+		}
+
+		// Unblock the scanner promptly on cancellation
+		go func() {
+			<-cmdCtx.Done()
+			stdout.Close()
+		}()
+
+		scanner := bufio.NewScanner(stdout)
+		buf := make([]byte, 0, 1024*1024)
+		scanner.Buffer(buf, 10*1024*1024)
+
+		var gotDone bool
+		for scanner.Scan() {
+			var out ExecChunk
+			if err := json.Unmarshal(scanner.Bytes(), &out); err != nil {
+				continue
+			}
+
+			if out.Error != "" {
+				cancel()
+				cmd.Wait()
+				ch <- Chunk{Error: fmt.Errorf("%s", out.Error)}
+				return
+			}
+
+			if out.Text != "" || out.Done {
+				ch <- Chunk{Text: out.Text, Done: out.Done, Usage: out.Usage}
+			}
+			if out.Done {
+				gotDone = true
+				break
+			}
+		}
+
+		// Stop the process if it's still running after signalling done
+		if gotDone {
+			cancel()
+			cmd.Wait()
+			return
+		}
+
+		waitErr := cmd.Wait()
+
+		if cmdCtx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				ch <- Chunk{Error: fmt.Errorf("%s timed out", m.command), IsTimeout: true}
+				return
+			}
+			ch <- Chunk{Done: true}
+			return
+		}
+
+		if waitErr != nil {
+			errMsg := strings.TrimSpace(stderrBuf.String())
+			if errMsg == "" {
+				errMsg = waitErr.Error()
+			}
+			ch <- Chunk{Error: fmt.Errorf("%s: %s", m.command, errMsg)}
+			return
+		}
+
+		ch <- Chunk{Done: true}
+	}()
+
+	return ch
+}
+
+func (m *ExecModel) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+	}
+	if m.cmd != nil && m.cmd.Process != nil {
+		m.cmd.Process.Kill()
+	}
+}
@@ -0,0 +1,158 @@
+// internal/models/exec_test.go
+package models
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript creates an executable shell script implementing the exec protocol
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "model.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
+
+func collect(t *testing.T, ch <-chan Chunk) []Chunk {
+	t.Helper()
+	var chunks []Chunk
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case c, ok := <-ch:
+			if !ok {
+				return chunks
+			}
+			chunks = append(chunks, c)
+		case <-timeout:
+			t.Fatal("timed out waiting for exec model")
+			return chunks
+		}
+	}
+}
+
+func TestExecModel_StreamsChunks(t *testing.T) {
+	script := writeScript(t, `
+read -r req
+echo "not json, ignored"
+echo '{"text":"Hello "}'
+echo '{"text":"world"}'
+echo '{"done":true}'
+`)
+	m := NewExec("local", "Local", script, nil)
+
+	chunks := collect(t, m.Send(context.Background(), nil, "hi"))
+
+	var text strings.Builder
+	var done bool
+	for _, c := range chunks {
+		if c.Error != nil {
+			t.Fatalf("unexpected error: %v", c.Error)
+		}
+		text.WriteString(c.Text)
+		done = done || c.Done
+	}
+	if text.String() != "Hello world" {
+		t.Errorf("text = %q, want %q", text.String(), "Hello world")
+	}
+	if !done {
+		t.Error("expected a Done chunk")
+	}
+}
+
+func TestExecModel_ReceivesRequestOnStdin(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "req.json")
+	script := writeScript(t, `
+read -r req
+printf '%s' "$req" > "`+out+`"
+echo '{"text":"ok","done":true}'
+`)
+	m := NewExec("local", "", script, nil)
+
+	history := []Message{{Source: "user", Content: "earlier question", Type: "user"}}
+	collect(t, m.Send(context.Background(), history, "current prompt"))
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("script did not record request: %v", err)
+	}
+	req := string(data)
+	for _, want := range []string{`"model":"local"`, `"prompt":"current prompt"`, `"content":"earlier question"`} {
+		if !strings.Contains(req, want) {
+			t.Errorf("request %s missing %s", req, want)
+		}
+	}
+}
+
+func TestExecModel_ErrorLine(t *testing.T) {
+	script := writeScript(t, `
+read -r req
+echo '{"error":"model exploded"}'
+`)
+	m := NewExec("local", "Local", script, nil)
+
+	chunks := collect(t, m.Send(context.Background(), nil, "hi"))
+	if len(chunks) == 0 || chunks[len(chunks)-1].Error == nil {
+		t.Fatalf("expected error chunk, got %+v", chunks)
+	}
+	if !strings.Contains(chunks[len(chunks)-1].Error.Error(), "model exploded") {
+		t.Errorf("error = %v, want it to contain %q", chunks[len(chunks)-1].Error, "model exploded")
+	}
+}
+
+func TestExecModel_NonZeroExit(t *testing.T) {
+	script := writeScript(t, `
+read -r req
+echo "backend unavailable" >&2
+exit 3
+`)
+	m := NewExec("local", "Local", script, nil)
+
+	chunks := collect(t, m.Send(context.Background(), nil, "hi"))
+	if len(chunks) != 1 || chunks[0].Error == nil {
+		t.Fatalf("expected a single error chunk, got %+v", chunks)
+	}
+	if !strings.Contains(chunks[0].Error.Error(), "backend unavailable") {
+		t.Errorf("error = %v, want stderr in message", chunks[0].Error)
+	}
+}
+
+func TestExecModel_Cancellation(t *testing.T) {
+	script := writeScript(t, `
+read -r req
+echo '{"text":"starting"}'
+sleep 10
+echo '{"done":true}'
+`)
+	m := NewExec("local", "Local", script, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	chunks := collect(t, m.Send(ctx, nil, "hi"))
+	if time.Since(start) > 3*time.Second {
+		t.Fatal("exec model did not stop on context cancellation")
+	}
+
+	last := chunks[len(chunks)-1]
+	if last.Error == nil || !last.IsTimeout {
+		t.Errorf("expected timeout chunk, got %+v", last)
+	}
+}
+
+func TestExecModel_MissingCommand(t *testing.T) {
+	m := NewExec("local", "Local", filepath.Join(t.TempDir(), "does-not-exist"), nil)
+
+	chunks := collect(t, m.Send(context.Background(), nil, "hi"))
+	if len(chunks) != 1 || chunks[0].Error == nil {
+		t.Fatalf("expected start error, got %+v", chunks)
+	}
+}
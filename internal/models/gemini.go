@@ -22,7 +22,7 @@ type GeminiModel struct {
 }
 
 func NewGemini(cliPath string) *GeminiModel {
-	return &GeminiModel{
+	m := &GeminiModel{
 		BaseModel: NewBaseModel(ModelInfo{
 			ID:      "gemini",
 			Name:    "Gemini",
@@ -32,12 +32,19 @@ func NewGemini(cliPath string) *GeminiModel {
 		}),
 		cliPath: cliPath,
 	}
+	m.defaultPreamble = geminiPreamble
+	return m
 }
 
 func (m *GeminiModel) SetWorkDir(dir string) {
 	m.workDir = dir
 }
 
+// HealthCheck verifies the gemini CLI is installed and runs
+func (m *GeminiModel) HealthCheck(ctx context.Context) error {
+	return checkCLI(ctx, m.cliPath)
+}
+
 func (m *GeminiModel) Send(ctx context.Context, history []Message, prompt string) <-chan Chunk {
 	ch := make(chan Chunk, 100)
 
@@ -48,7 +55,8 @@ func (m *GeminiModel) Send(ctx context.Context, history []Message, prompt string
 
 		// Build context from history
 		var contextPrompt strings.Builder
-		contextPrompt.WriteString("You are participating in a multi-model debate. Previous messages:\n\n")
+		contextPrompt.WriteString(m.Preamble())
+		contextPrompt.WriteString("\n\nPrevious messages:\n\n")
 		for _, msg := range history {
 			contextPrompt.WriteString(fmt.Sprintf("[%s]: %s\n\n", msg.Source, msg.Content))
 		}
@@ -148,10 +156,11 @@ func (m *GeminiModel) parseLine(line string, fullText *strings.Builder) *Chunk {
 
 	case "result":
 		// Check for success/error status
+		usage := parseGeminiUsage(event)
 		if status, ok := event["status"].(string); ok && status == "success" {
-			return &Chunk{Done: true}
+			return &Chunk{Done: true, Usage: usage}
 		}
-		return &Chunk{Done: true}
+		return &Chunk{Done: true, Usage: usage}
 
 	case "done":
 		return &Chunk{Done: true}
@@ -171,6 +180,23 @@ func (m *GeminiModel) parseLine(line string, fullText *strings.Builder) *Chunk {
 	return nil
 }
 
+// parseGeminiUsage extracts token counts from a result event's stats block.
+// Returns nil if the event carries no stats.
+func parseGeminiUsage(event map[string]any) *Usage {
+	stats, ok := event["stats"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	usage := &Usage{}
+	if n, ok := stats["input_tokens"].(float64); ok {
+		usage.PromptTokens = int(n)
+	}
+	if n, ok := stats["output_tokens"].(float64); ok {
+		usage.CompletionTokens = int(n)
+	}
+	return usage
+}
+
 func (m *GeminiModel) Stop() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
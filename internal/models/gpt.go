@@ -2,6 +2,7 @@
 package models
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,55 +12,78 @@ import (
 	"sync"
 )
 
+// gptBaseURL is the default GPT API endpoint
+const gptBaseURL = "https://api.openai.com/v1"
+
 type GPTModel struct {
 	BaseModel
-	apiKey      string
-	modelName   string
-	client      *RetryableClient
-	cancel      context.CancelFunc
-	mu          sync.Mutex
+	apiKey    string
+	modelName string
+	baseURL   string
+	client    *RetryableClient
+	cancel    context.CancelFunc
+	mu        sync.Mutex
 }
 
 func NewGPT(apiKey, modelName string) *GPTModel {
-	return &GPTModel{
+	m := &GPTModel{
 		BaseModel: NewBaseModel(ModelInfo{
-			ID:      "gpt",
-			Name:    "GPT",
-			Color:   "#00FF00", // Green
-			CanExec: false,
-			CanRead: true,
+			ID:           "gpt",
+			Name:         "GPT",
+			Color:        "#00FF00", // Green
+			CanExec:      false,
+			CanRead:      true,
+			CanSeeImages: true,
 		}),
 		apiKey:    apiKey,
 		modelName: modelName,
+		baseURL:   gptBaseURL,
 		client:    NewRetryableClient(DefaultRetryConfig()),
 	}
+	m.defaultPreamble = gptPreamble
+	return m
 }
 
 // NewGPTWithRetry creates a GPT model with custom retry settings
 func NewGPTWithRetry(apiKey, modelName string, retryConfig RetryConfig) *GPTModel {
-	return &GPTModel{
+	m := &GPTModel{
 		BaseModel: NewBaseModel(ModelInfo{
-			ID:      "gpt",
-			Name:    "GPT",
-			Color:   "#00FF00",
-			CanExec: false,
-			CanRead: true,
+			ID:           "gpt",
+			Name:         "GPT",
+			Color:        "#00FF00",
+			CanExec:      false,
+			CanRead:      true,
+			CanSeeImages: true,
 		}),
 		apiKey:    apiKey,
 		modelName: modelName,
+		baseURL:   gptBaseURL,
 		client:    NewRetryableClient(retryConfig),
 	}
+	m.defaultPreamble = gptPreamble
+	return m
+}
+
+// SetBaseURL overrides the API endpoint (e.g. for a proxy or test server)
+func (m *GPTModel) SetBaseURL(url string) {
+	m.baseURL = strings.TrimRight(url, "/")
+}
+
+// HealthCheck verifies the API is reachable and the key is accepted
+func (m *GPTModel) HealthCheck(ctx context.Context) error {
+	return m.client.checkModelsEndpoint(ctx, m.baseURL, m.apiKey)
 }
 
 type gptMessage struct {
 	Role    string `json:"role"`
-	Content string `json:"content"`
+	Content any    `json:"content"` // A string, or a content array when images are attached
 }
 
 type gptRequest struct {
-	Model    string       `json:"model"`
-	Messages []gptMessage `json:"messages"`
-	Stream   bool         `json:"stream"`
+	Model         string         `json:"model"`
+	Messages      []gptMessage   `json:"messages"`
+	Stream        bool           `json:"stream"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
 }
 
 func (m *GPTModel) Send(ctx context.Context, history []Message, prompt string) <-chan Chunk {
@@ -79,7 +103,7 @@ func (m *GPTModel) Send(ctx context.Context, history []Message, prompt string) <
 		messages := []gptMessage{
 			{
 				Role:    "system",
-				Content: "You are participating in a multi-model debate. Other AI models may respond before or after you. Be direct and substantive. If you agree, say AGREE: [model]. If you disagree, explain why. If you have something to add, say ADD: [point].",
+				Content: m.Preamble(),
 			},
 		}
 
@@ -94,12 +118,14 @@ func (m *GPTModel) Send(ctx context.Context, history []Message, prompt string) <
 		}
 
 		// Add current prompt
-		messages = append(messages, gptMessage{Role: "user", Content: prompt})
+		messages = append(messages, gptMessage{Role: "user", Content: chatContent(prompt, promptAttachments(history))})
 
 		reqBody := gptRequest{
 			Model:    m.modelName,
 			Messages: messages,
 			Stream:   true,
+			// Ask for a final usage chunk so we can report token counts
+			StreamOptions: &streamOptions{IncludeUsage: true},
 		}
 
 		bodyBytes, err := json.Marshal(reqBody)
@@ -108,7 +134,7 @@ func (m *GPTModel) Send(ctx context.Context, history []Message, prompt string) <
 			return
 		}
 
-		req, err := NewRequestWithBody(cmdCtx, "POST", "https://api.openai.com/v1/chat/completions", bodyBytes)
+		req, err := NewRequestWithBody(cmdCtx, "POST", m.baseURL+"/chat/completions", bodyBytes)
 		if err != nil {
 			ch <- Chunk{Error: fmt.Errorf("request: %w", err)}
 			return
@@ -128,7 +154,7 @@ func (m *GPTModel) Send(ctx context.Context, history []Message, prompt string) <
 			// Check for rate limit
 			if errors.Is(err, ErrRateLimit) {
 				m.SetStatus(StatusError)
-				ch <- Chunk{Error: fmt.Errorf("rate limit exceeded - try again later")}
+				ch <- Chunk{Error: fmt.Errorf("rate limit exceeded - try again later: %w", err)}
 				return
 			}
 			m.SetStatus(StatusError)
@@ -145,10 +171,9 @@ func (m *GPTModel) Send(ctx context.Context, history []Message, prompt string) <
 		}
 
 		// Parse SSE stream
-		var fullText strings.Builder
-		decoder := json.NewDecoder(resp.Body)
-
-		for {
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxSSELine)
+		for scanner.Scan() {
 			select {
 			case <-cmdCtx.Done():
 				ch <- Chunk{Done: true}
@@ -156,54 +181,49 @@ func (m *GPTModel) Send(ctx context.Context, history []Message, prompt string) <
 			default:
 			}
 
-			// Read line-by-line for SSE
-			buf := make([]byte, 4096)
-			n, err := resp.Body.Read(buf)
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				ch <- Chunk{Error: err}
-				return
-			}
+			line := scanner.Text()
+			line = strings.TrimPrefix(line, "data: ")
+			line = strings.TrimSpace(line)
 
-			lines := strings.Split(string(buf[:n]), "\n")
-			for _, line := range lines {
-				line = strings.TrimPrefix(line, "data: ")
-				line = strings.TrimSpace(line)
+			if line == "" || line == "[DONE]" {
+				continue
+			}
 
-				if line == "" || line == "[DONE]" {
-					continue
-				}
+			var sseData struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+					FinishReason string `json:"finish_reason"`
+				} `json:"choices"`
+				Usage *openAIUsage `json:"usage"`
+			}
 
-				var sseData struct {
-					Choices []struct {
-						Delta struct {
-							Content string `json:"content"`
-						} `json:"delta"`
-						FinishReason string `json:"finish_reason"`
-					} `json:"choices"`
-				}
+			if err := json.Unmarshal([]byte(line), &sseData); err != nil {
+				continue
+			}
 
-				if err := json.Unmarshal([]byte(line), &sseData); err != nil {
-					continue
+			for _, choice := range sseData.Choices {
+				if choice.Delta.Content != "" {
+					ch <- Chunk{Text: choice.Delta.Content}
 				}
+			}
 
-				for _, choice := range sseData.Choices {
-					if choice.Delta.Content != "" {
-						fullText.WriteString(choice.Delta.Content)
-						ch <- Chunk{Text: choice.Delta.Content}
-					}
-					if choice.FinishReason == "stop" {
-						ch <- Chunk{Text: fullText.String(), Done: true}
-						return
-					}
-				}
+			// Usage comes with the last chunk, after finish_reason; some
+			// servers put content on the same chunk, sent above
+			if sseData.Usage != nil {
+				ch <- Chunk{Done: true, Usage: sseData.Usage.toUsage()}
+				return
 			}
 		}
+		if err := scanner.Err(); err != nil && cmdCtx.Err() == nil {
+			ch <- Chunk{Error: err}
+			return
+		}
 
-		_ = decoder // silence unused warning
-		ch <- Chunk{Text: fullText.String(), Done: true}
+		// The server ignored stream_options and sent no usage; the text
+		// has all been sent already
+		ch <- Chunk{Done: true}
 	}()
 
 	return ch
@@ -0,0 +1,137 @@
+// internal/models/gpt_test.go
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGPTHealthCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		wantErr error
+	}{
+		{name: "valid key", apiKey: "good-key"},
+		{name: "invalid key", apiKey: "bad-key", wantErr: ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gpt := NewGPT(tt.apiKey, "gpt-4o")
+			gpt.SetBaseURL(server.URL)
+			err := gpt.HealthCheck(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("HealthCheck() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGPTHealthCheck_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	gpt := NewGPT("key", "gpt-4o")
+	gpt.SetBaseURL(url)
+	if err := gpt.HealthCheck(context.Background()); err == nil {
+		t.Error("expected error for unreachable server")
+	}
+}
+
+func TestOpenAIStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    []string
+		wantText  string
+		wantUsage bool
+	}{
+		{
+			name: "usage after finish_reason",
+			events: []string{
+				`{"choices":[{"delta":{"content":"Hello"}}]}`,
+				`{"choices":[{"delta":{"content":" world"},"finish_reason":"stop"}]}`,
+				`{"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2}}`,
+			},
+			wantText:  "Hello world",
+			wantUsage: true,
+		},
+		{
+			name: "usage on a content chunk",
+			events: []string{
+				`{"choices":[{"delta":{"content":"Hello"}}]}`,
+				`{"choices":[{"delta":{"content":" world"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2}}`,
+			},
+			wantText:  "Hello world",
+			wantUsage: true,
+		},
+		{
+			name: "stream_options ignored",
+			events: []string{
+				`{"choices":[{"delta":{"content":"Hello"}}]}`,
+				`{"choices":[{"delta":{"content":" world"},"finish_reason":"stop"}]}`,
+			},
+			wantText: "Hello world",
+		},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, event := range tt.events {
+				w.Write([]byte("data: " + event + "\n\n"))
+			}
+			w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		gpt := NewGPT("key", "gpt-4o")
+		gpt.SetBaseURL(server.URL)
+		grok := NewGrok("key", "grok-3")
+		grok.SetBaseURL(server.URL)
+
+		for _, m := range []Model{gpt, grok} {
+			t.Run(tt.name+"/"+m.Info().ID, func(t *testing.T) {
+				var text strings.Builder
+				var done, usage bool
+				for chunk := range m.Send(context.Background(), nil, "hi") {
+					if chunk.Error != nil {
+						t.Fatalf("stream error: %v", chunk.Error)
+					}
+					if done {
+						t.Fatalf("chunk after Done: %+v", chunk)
+					}
+					text.WriteString(chunk.Text)
+					done, usage = chunk.Done, chunk.Usage != nil
+				}
+				if !done {
+					t.Fatal("stream ended without Done")
+				}
+				if text.String() != tt.wantText {
+					t.Errorf("text = %q, want %q", text.String(), tt.wantText)
+				}
+				if usage != tt.wantUsage {
+					t.Errorf("usage reported = %v, want %v", usage, tt.wantUsage)
+				}
+			})
+		}
+	}
+}
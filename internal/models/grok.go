@@ -2,6 +2,7 @@
 package models
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,55 +12,78 @@ import (
 	"sync"
 )
 
+// grokBaseURL is the default Grok API endpoint
+const grokBaseURL = "https://api.x.ai/v1"
+
 type GrokModel struct {
 	BaseModel
 	apiKey    string
 	modelName string
+	baseURL   string
 	client    *RetryableClient
 	cancel    context.CancelFunc
 	mu        sync.Mutex
 }
 
 func NewGrok(apiKey, modelName string) *GrokModel {
-	return &GrokModel{
+	m := &GrokModel{
 		BaseModel: NewBaseModel(ModelInfo{
-			ID:      "grok",
-			Name:    "Grok",
-			Color:   "#FFA500", // Orange
-			CanExec: false,
-			CanRead: true,
+			ID:           "grok",
+			Name:         "Grok",
+			Color:        "#FFA500", // Orange
+			CanExec:      false,
+			CanRead:      true,
+			CanSeeImages: true,
 		}),
 		apiKey:    apiKey,
 		modelName: modelName,
+		baseURL:   grokBaseURL,
 		client:    NewRetryableClient(DefaultRetryConfig()),
 	}
+	m.defaultPreamble = grokPreamble
+	return m
 }
 
 // NewGrokWithRetry creates a Grok model with custom retry settings
 func NewGrokWithRetry(apiKey, modelName string, retryConfig RetryConfig) *GrokModel {
-	return &GrokModel{
+	m := &GrokModel{
 		BaseModel: NewBaseModel(ModelInfo{
-			ID:      "grok",
-			Name:    "Grok",
-			Color:   "#FFA500",
-			CanExec: false,
-			CanRead: true,
+			ID:           "grok",
+			Name:         "Grok",
+			Color:        "#FFA500",
+			CanExec:      false,
+			CanRead:      true,
+			CanSeeImages: true,
 		}),
 		apiKey:    apiKey,
 		modelName: modelName,
+		baseURL:   grokBaseURL,
 		client:    NewRetryableClient(retryConfig),
 	}
+	m.defaultPreamble = grokPreamble
+	return m
+}
+
+// SetBaseURL overrides the API endpoint (e.g. for a proxy or test server)
+func (m *GrokModel) SetBaseURL(url string) {
+	m.baseURL = strings.TrimRight(url, "/")
+}
+
+// HealthCheck verifies the API is reachable and the key is accepted
+func (m *GrokModel) HealthCheck(ctx context.Context) error {
+	return m.client.checkModelsEndpoint(ctx, m.baseURL, m.apiKey)
 }
 
 type grokMessage struct {
 	Role    string `json:"role"`
-	Content string `json:"content"`
+	Content any    `json:"content"` // A string, or a content array when images are attached
 }
 
 type grokRequest struct {
-	Model    string        `json:"model"`
-	Messages []grokMessage `json:"messages"`
-	Stream   bool          `json:"stream"`
+	Model         string         `json:"model"`
+	Messages      []grokMessage  `json:"messages"`
+	Stream        bool           `json:"stream"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
 }
 
 func (m *GrokModel) Send(ctx context.Context, history []Message, prompt string) <-chan Chunk {
@@ -79,7 +103,7 @@ func (m *GrokModel) Send(ctx context.Context, history []Message, prompt string)
 		messages := []grokMessage{
 			{
 				Role:    "system",
-				Content: "You are participating in a multi-model debate with other AI models. Be direct and opinionated. If you agree, say AGREE: [model]. If you disagree, explain why. If you have something to add, say ADD: [point]. Don't be sycophantic.",
+				Content: m.Preamble(),
 			},
 		}
 
@@ -94,12 +118,14 @@ func (m *GrokModel) Send(ctx context.Context, history []Message, prompt string)
 		}
 
 		// Add current prompt
-		messages = append(messages, grokMessage{Role: "user", Content: prompt})
+		messages = append(messages, grokMessage{Role: "user", Content: chatContent(prompt, promptAttachments(history))})
 
 		reqBody := grokRequest{
 			Model:    m.modelName,
 			Messages: messages,
 			Stream:   true,
+			// Ask for a final usage chunk so we can report token counts
+			StreamOptions: &streamOptions{IncludeUsage: true},
 		}
 
 		bodyBytes, err := json.Marshal(reqBody)
@@ -108,7 +134,7 @@ func (m *GrokModel) Send(ctx context.Context, history []Message, prompt string)
 			return
 		}
 
-		req, err := NewRequestWithBody(cmdCtx, "POST", "https://api.x.ai/v1/chat/completions", bodyBytes)
+		req, err := NewRequestWithBody(cmdCtx, "POST", m.baseURL+"/chat/completions", bodyBytes)
 		if err != nil {
 			ch <- Chunk{Error: fmt.Errorf("request: %w", err)}
 			return
@@ -128,7 +154,7 @@ func (m *GrokModel) Send(ctx context.Context, history []Message, prompt string)
 			// Check for rate limit
 			if errors.Is(err, ErrRateLimit) {
 				m.SetStatus(StatusError)
-				ch <- Chunk{Error: fmt.Errorf("rate limit exceeded - try again later")}
+				ch <- Chunk{Error: fmt.Errorf("rate limit exceeded - try again later: %w", err)}
 				return
 			}
 			m.SetStatus(StatusError)
@@ -145,9 +171,9 @@ func (m *GrokModel) Send(ctx context.Context, history []Message, prompt string)
 		}
 
 		// Parse SSE stream (same format as OpenAI)
-		var fullText strings.Builder
-
-		for {
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxSSELine)
+		for scanner.Scan() {
 			select {
 			case <-cmdCtx.Done():
 				ch <- Chunk{Done: true}
@@ -155,52 +181,49 @@ func (m *GrokModel) Send(ctx context.Context, history []Message, prompt string)
 			default:
 			}
 
-			buf := make([]byte, 4096)
-			n, err := resp.Body.Read(buf)
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				ch <- Chunk{Error: err}
-				return
-			}
+			line := scanner.Text()
+			line = strings.TrimPrefix(line, "data: ")
+			line = strings.TrimSpace(line)
 
-			lines := strings.Split(string(buf[:n]), "\n")
-			for _, line := range lines {
-				line = strings.TrimPrefix(line, "data: ")
-				line = strings.TrimSpace(line)
+			if line == "" || line == "[DONE]" {
+				continue
+			}
 
-				if line == "" || line == "[DONE]" {
-					continue
-				}
+			var sseData struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+					FinishReason string `json:"finish_reason"`
+				} `json:"choices"`
+				Usage *openAIUsage `json:"usage"`
+			}
 
-				var sseData struct {
-					Choices []struct {
-						Delta struct {
-							Content string `json:"content"`
-						} `json:"delta"`
-						FinishReason string `json:"finish_reason"`
-					} `json:"choices"`
-				}
+			if err := json.Unmarshal([]byte(line), &sseData); err != nil {
+				continue
+			}
 
-				if err := json.Unmarshal([]byte(line), &sseData); err != nil {
-					continue
+			for _, choice := range sseData.Choices {
+				if choice.Delta.Content != "" {
+					ch <- Chunk{Text: choice.Delta.Content}
 				}
+			}
 
-				for _, choice := range sseData.Choices {
-					if choice.Delta.Content != "" {
-						fullText.WriteString(choice.Delta.Content)
-						ch <- Chunk{Text: choice.Delta.Content}
-					}
-					if choice.FinishReason == "stop" {
-						ch <- Chunk{Text: fullText.String(), Done: true}
-						return
-					}
-				}
+			// Usage comes with the last chunk, after finish_reason; some
+			// servers put content on the same chunk, sent above
+			if sseData.Usage != nil {
+				ch <- Chunk{Done: true, Usage: sseData.Usage.toUsage()}
+				return
 			}
 		}
+		if err := scanner.Err(); err != nil && cmdCtx.Err() == nil {
+			ch <- Chunk{Error: err}
+			return
+		}
 
-		ch <- Chunk{Text: fullText.String(), Done: true}
+		// The server ignored stream_options and sent no usage; the text
+		// has all been sent already
+		ch <- Chunk{Done: true}
 	}()
 
 	return ch
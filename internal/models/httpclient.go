@@ -9,17 +9,52 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 // Common HTTP errors that should trigger retry
 var (
-	ErrRateLimit  = errors.New("rate limit exceeded (429)")
-	ErrServerBusy = errors.New("server busy (503)")
-	ErrBadGateway = errors.New("bad gateway (502)")
+	ErrRateLimit      = errors.New("rate limit exceeded (429)")
+	ErrServerBusy     = errors.New("server busy (503)")
+	ErrBadGateway     = errors.New("bad gateway (502)")
 	ErrGatewayTimeout = errors.New("gateway timeout (504)")
+	ErrUnauthorized   = errors.New("API key rejected (401/403)")
 )
 
+// RateLimitError is returned for HTTP 429. RetryAfter is how long the
+// server asked us to wait, or zero if it didn't say.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v, retry after %v", ErrRateLimit, e.RetryAfter)
+	}
+	return ErrRateLimit.Error()
+}
+
+// Is makes errors.Is(err, ErrRateLimit) match
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimit
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an
+// HTTP date. Returns zero if it's missing or malformed.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
 // RetryConfig holds retry configuration
 type RetryConfig struct {
 	MaxAttempts int
@@ -91,6 +126,15 @@ func (c *RetryableClient) DoWithRetry(ctx context.Context, req *http.Request) (*
 			return nil, err
 		}
 
+		// A rate limit with Retry-After is left to the caller, which knows
+		// how much time it has to wait
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); wait > 0 {
+				resp.Body.Close()
+				return nil, &RateLimitError{RetryAfter: wait}
+			}
+		}
+
 		// Check for retryable status codes
 		if shouldRetryStatus(resp.StatusCode) {
 			resp.Body.Close()
@@ -156,7 +200,7 @@ func shouldRetryStatus(code int) bool {
 func statusError(code int) error {
 	switch code {
 	case 429:
-		return ErrRateLimit
+		return &RateLimitError{}
 	case 502:
 		return ErrBadGateway
 	case 503:
@@ -168,6 +212,31 @@ func statusError(code int) error {
 	}
 }
 
+// checkModelsEndpoint verifies an OpenAI-compatible API is reachable and accepts
+// the key by listing models, which costs no tokens. No retries - this is a probe.
+func (c *RetryableClient) checkModelsEndpoint(ctx context.Context, baseURL, apiKey string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	default:
+		return statusError(resp.StatusCode)
+	}
+}
+
 // NewRequestWithBody creates a new HTTP request with the given body bytes
 // The body is stored so it can be re-read on retry
 func NewRequestWithBody(ctx context.Context, method, url string, body []byte) (*http.Request, error) {
@@ -183,3 +252,24 @@ func NewRequestWithBody(ctx context.Context, method, url string, body []byte) (*
 	req.ContentLength = int64(len(body))
 	return req, nil
 }
+
+// streamOptions requests extra data in OpenAI-compatible streaming responses
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// maxSSELine caps one line of an OpenAI-compatible event stream
+const maxSSELine = 1024 * 1024
+
+// openAIUsage is the usage block of an OpenAI-compatible response
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+func (u *openAIUsage) toUsage() *Usage {
+	return &Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+	}
+}
@@ -0,0 +1,40 @@
+// internal/models/httpclient_test.go
+package models
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"2", 2 * time.Second},
+		{"-5", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitError_IsErrRateLimit(t *testing.T) {
+	var err error = &RateLimitError{RetryAfter: 2 * time.Second}
+	if !errors.Is(err, ErrRateLimit) {
+		t.Error("RateLimitError should match ErrRateLimit")
+	}
+	if got, want := err.Error(), "rate limit exceeded (429), retry after 2s"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -3,6 +3,10 @@ package models
 
 import (
 	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
 )
 
 // Model is the interface all model backends must implement
@@ -21,12 +25,20 @@ type Model interface {
 
 	// SetStatus updates the model status
 	SetStatus(status ModelStatus)
+
+	// HealthCheck cheaply verifies the backend is usable (CLI installed, API key valid)
+	HealthCheck(ctx context.Context) error
 }
 
 // BaseModel provides common functionality for all models
 type BaseModel struct {
-	info   ModelInfo
-	status ModelStatus
+	info     ModelInfo
+	statusMu sync.RWMutex // The orchestrator and Send's goroutine both set status
+	status   ModelStatus
+	preamble string
+	persona  string
+
+	defaultPreamble string // Used when no preamble is set; DefaultPreamble if empty
 }
 
 func NewBaseModel(info ModelInfo) BaseModel {
@@ -41,9 +53,67 @@ func (m *BaseModel) Info() ModelInfo {
 }
 
 func (m *BaseModel) Status() ModelStatus {
+	m.statusMu.RLock()
+	defer m.statusMu.RUnlock()
 	return m.status
 }
 
 func (m *BaseModel) SetStatus(status ModelStatus) {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
 	m.status = status
 }
+
+// SetColor overrides the model's display color
+func (m *BaseModel) SetColor(color string) {
+	m.info.Color = color
+}
+
+// SetPreamble replaces the debate framing sent ahead of every prompt
+func (m *BaseModel) SetPreamble(preamble string) {
+	m.preamble = preamble
+}
+
+// SetPersona sets the role the model argues from, e.g. "security reviewer"
+func (m *BaseModel) SetPersona(persona string) {
+	m.persona = strings.TrimSpace(persona)
+}
+
+// Persona returns the role the model argues from, or "" if it has none
+func (m *BaseModel) Persona() string {
+	return m.persona
+}
+
+// Preamble returns the debate framing sent ahead of every prompt, led by
+// the model's persona if it has one
+func (m *BaseModel) Preamble() string {
+	preamble := m.preamble
+	if preamble == "" {
+		preamble = m.defaultPreamble
+	}
+	if preamble == "" {
+		preamble = DefaultPreamble
+	}
+	if m.persona == "" {
+		return preamble
+	}
+	return fmt.Sprintf("Your role in this debate: %s. Argue from that perspective.\n\n%s", m.persona, preamble)
+}
+
+// checkCLI verifies a CLI is on PATH and that "--version" runs
+func checkCLI(ctx context.Context, cliPath string) error {
+	path, err := exec.LookPath(cliPath)
+	if err != nil {
+		return fmt.Errorf("%s not found", cliPath)
+	}
+
+	out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("%s --version failed: %s", cliPath, msg)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// internal/models/preamble.go
+package models
+
+import (
+	"strings"
+	"text/template"
+)
+
+// DefaultPreamble frames the debate for Claude and exec models unless the
+// config sets defaults.preamble. GPT, Grok and Gemini keep their own framing.
+const DefaultPreamble = "You are participating in a multi-model debate called Roundtable. " +
+	"Other AI models (GPT, Gemini, Grok) respond alongside you. " +
+	"Be direct and substantive. " +
+	"If you agree with another model, say AGREE: [reason]. " +
+	"If you disagree, say OBJECT: [reason]. " +
+	"If you have something to add, say ADD: [point]."
+
+const (
+	gptPreamble = "You are participating in a multi-model debate. " +
+		"Other AI models may respond before or after you. " +
+		"Be direct and substantive. " +
+		"If you agree, say AGREE: [model]. " +
+		"If you disagree, explain why. " +
+		"If you have something to add, say ADD: [point]."
+	grokPreamble = "You are participating in a multi-model debate with other AI models. " +
+		"Be direct and opinionated. " +
+		"If you agree, say AGREE: [model]. " +
+		"If you disagree, explain why. " +
+		"If you have something to add, say ADD: [point]. " +
+		"Don't be sycophantic."
+	geminiPreamble = "You are participating in a multi-model debate."
+)
+
+// PreambleData is what a preamble template can refer to
+type PreambleData struct {
+	ModelName   string // e.g. "Claude"
+	OtherModels string // The other participants' names, comma-separated
+}
+
+// RenderPreamble executes a preamble template such as
+// "You are {{.ModelName}}, debating {{.OtherModels}}." An empty template
+// renders DefaultPreamble.
+func RenderPreamble(tmpl string, data PreambleData) (string, error) {
+	if tmpl == "" {
+		return DefaultPreamble, nil
+	}
+	t, err := template.New("preamble").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := t.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,120 @@
+// internal/models/preamble_test.go
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"roundtable/internal/config"
+)
+
+func TestRenderPreamble(t *testing.T) {
+	data := PreambleData{ModelName: "Claude", OtherModels: "Gemini, GPT"}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty is the default", tmpl: "", want: DefaultPreamble},
+		{
+			name: "placeholders",
+			tmpl: "You are {{.ModelName}} on a red team against {{.OtherModels}}. Attack every proposal.\n",
+			want: "You are Claude on a red team against Gemini, GPT. Attack every proposal.",
+		},
+		{name: "unknown field", tmpl: "{{.Persona}}", wantErr: true},
+		{name: "bad syntax", tmpl: "{{.ModelName", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderPreamble(tt.tmpl, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderPreamble() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderPreamble() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRegistry_Preamble(t *testing.T) {
+	cfg := config.Default()
+	cfg.Defaults.Preamble = "{{.ModelName}} vs {{.OtherModels}}"
+	r := NewRegistry(cfg)
+
+	claude := r.Get("claude").(*ClaudeModel)
+	if got := claude.Preamble(); got != "Claude vs Gemini" {
+		t.Errorf("claude preamble = %q, want %q", got, "Claude vs Gemini")
+	}
+	prompt := BuildPrompt(claude.Preamble(), nil, "hi")
+	if !strings.HasPrefix(prompt, "Claude vs Gemini\n\n") {
+		t.Errorf("prompt doesn't start with the preamble:\n%s", prompt)
+	}
+
+	// Only enabled models are named as the others
+	cfg.Models.Gemini.Enabled = false
+	cfg.Models.GPT.Enabled = true
+	cfg.Models.GPT.APIKey = "sk-test"
+	r = NewRegistry(cfg)
+	if got := r.Get("claude").(*ClaudeModel).Preamble(); got != "Claude vs GPT" {
+		t.Errorf("claude preamble = %q, want %q", got, "Claude vs GPT")
+	}
+}
+
+func TestNewRegistry_DefaultPreambles(t *testing.T) {
+	cfg := config.Default()
+	cfg.Models.GPT.Enabled = true
+	cfg.Models.GPT.APIKey = "sk-test"
+	cfg.Models.Grok.Enabled = true
+	cfg.Models.Grok.APIKey = "xai-test"
+	r := NewRegistry(cfg)
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"claude", DefaultPreamble},
+		{"gemini", geminiPreamble},
+		{"gpt", gptPreamble},
+		{"grok", grokPreamble},
+	}
+	for _, tt := range tests {
+		got := r.Get(tt.id).(interface{ Preamble() string }).Preamble()
+		if got != tt.want {
+			t.Errorf("%s preamble = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+
+	// GPT and Grok name the model they agree with, and Grok stays blunt
+	for _, p := range []string{gptPreamble, grokPreamble} {
+		if !strings.Contains(p, "AGREE: [model]") {
+			t.Errorf("preamble doesn't ask for the agreed model: %q", p)
+		}
+	}
+	if !strings.Contains(grokPreamble, "Don't be sycophantic.") {
+		t.Errorf("grok preamble lost its framing: %q", grokPreamble)
+	}
+}
+
+func TestNewRegistry_Persona(t *testing.T) {
+	cfg := config.Default()
+	cfg.Models.Claude.Persona = "systems architect"
+	r := NewRegistry(cfg)
+
+	claude := r.Get("claude").(*ClaudeModel)
+	prompt := BuildPrompt(claude.Preamble(), nil, "hi")
+	if !strings.HasPrefix(prompt, "Your role in this debate: systems architect.") {
+		t.Errorf("prompt doesn't lead with the persona:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, DefaultPreamble) {
+		t.Errorf("persona replaced the preamble instead of leading it:\n%s", prompt)
+	}
+
+	gemini := r.Get("gemini").(*GeminiModel)
+	if got := BuildPrompt(gemini.Preamble(), nil, "hi"); strings.Contains(got, "Your role") || strings.Contains(got, "systems architect") {
+		t.Errorf("model without a persona got one:\n%s", got)
+	}
+}
@@ -2,6 +2,11 @@
 package models
 
 import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
 	"roundtable/internal/config"
 )
 
@@ -9,6 +14,16 @@ import (
 type Registry struct {
 	models map[string]Model
 	order  []string // Preserve order for consistent display
+
+	mu     sync.Mutex
+	health map[string]error // Last health check result per model
+}
+
+// RefreshResult describes what changed when the registry re-checked its models
+type RefreshResult struct {
+	Health    map[string]error // Latest result per model ID, nil when healthy
+	Recovered []string         // Failed the previous check, pass now
+	Lost      []string         // Passed the previous check (or had none), fail now
 }
 
 // NewRegistry creates a registry from config
@@ -21,6 +36,7 @@ func NewRegistry(cfg *config.Config) *Registry {
 	// Add Claude if enabled
 	if cfg.Models.Claude.Enabled {
 		claude := NewClaude(cfg.Models.Claude.CLIPath, cfg.Models.Claude.DefaultModel)
+		claude.SetStream(cfg.Models.Claude.Stream)
 		r.models["claude"] = claude
 		r.order = append(r.order, "claude")
 	}
@@ -46,9 +62,110 @@ func NewRegistry(cfg *config.Config) *Registry {
 		r.order = append(r.order, "grok")
 	}
 
+	// Add external command models
+	for _, ec := range cfg.Models.Exec {
+		if !ec.Enabled || ec.ID == "" || ec.Command == "" {
+			continue
+		}
+		if _, exists := r.models[ec.ID]; exists {
+			continue
+		}
+		r.models[ec.ID] = NewExec(ec.ID, ec.Name, ec.Command, ec.Args)
+		r.order = append(r.order, ec.ID)
+	}
+
+	r.applyOrder(cfg.UI.ModelOrder)
+	r.applyPreamble(cfg.Defaults.Preamble)
+
+	for id, persona := range cfg.ModelPersonas() {
+		if m, ok := r.models[id].(interface{ SetPersona(string) }); ok {
+			m.SetPersona(persona)
+		}
+	}
+
+	// Apply configured display colors
+	for id, color := range cfg.ModelColors() {
+		if m, ok := r.models[id].(interface{ SetColor(string) }); ok {
+			m.SetColor(color)
+		}
+	}
+
 	return r
 }
 
+// applyOrder puts the listed models first, in that order. Unlisted models
+// follow in registry order; unknown IDs are ignored.
+func (r *Registry) applyOrder(ids []string) {
+	order := make([]string, 0, len(r.order))
+	for _, id := range ids {
+		if _, ok := r.models[id]; ok && !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
+	for _, id := range r.order {
+		if !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
+	r.order = order
+}
+
+// MoveModel moves a model delta places in the order (negative is earlier),
+// stopping at either end. Returns false if id is unknown or can't move that
+// way, e.g. the first model up.
+func (r *Registry) MoveModel(id string, delta int) bool {
+	from := slices.Index(r.order, id)
+	if from < 0 {
+		return false
+	}
+	to := max(0, min(len(r.order)-1, from+delta))
+	if to == from {
+		return false
+	}
+
+	// Copy, since callers may still be iterating the old slice from Enabled
+	order := slices.Delete(slices.Clone(r.order), from, from+1)
+	r.order = slices.Insert(order, to, id)
+	return true
+}
+
+// applyPreamble renders the preamble template for each model. Models keep
+// the default if it doesn't render; Validate reports the error.
+func (r *Registry) applyPreamble(tmpl string) {
+	if tmpl == "" {
+		return
+	}
+	enabled := r.Enabled()
+	for _, id := range enabled {
+		m, ok := r.models[id].(interface{ SetPreamble(string) })
+		if !ok {
+			continue
+		}
+		var others []string
+		for _, other := range enabled {
+			if other != id {
+				others = append(others, r.models[other].Info().Name)
+			}
+		}
+		preamble, err := RenderPreamble(tmpl, PreambleData{
+			ModelName:   r.models[id].Info().Name,
+			OtherModels: strings.Join(others, ", "),
+		})
+		if err == nil {
+			m.SetPreamble(preamble)
+		}
+	}
+}
+
+// SetWorkDir sets the directory that CLI and exec models run in
+func (r *Registry) SetWorkDir(dir string) {
+	for _, m := range r.models {
+		if m, ok := m.(interface{ SetWorkDir(string) }); ok {
+			m.SetWorkDir(dir)
+		}
+	}
+}
+
 // Get returns a model by ID
 func (r *Registry) Get(id string) Model {
 	return r.models[id]
@@ -74,3 +191,54 @@ func (r *Registry) Enabled() []string {
 func (r *Registry) Count() int {
 	return len(r.order)
 }
+
+// HealthCheckAll checks every model concurrently and returns each model's
+// result keyed by ID (nil for healthy models)
+func (r *Registry) HealthCheckAll(ctx context.Context) map[string]error {
+	results := make(map[string]error, len(r.order))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, id := range r.order {
+		m, ok := r.models[id]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(id string, m Model) {
+			defer wg.Done()
+			err := m.HealthCheck(ctx)
+			mu.Lock()
+			results[id] = err
+			mu.Unlock()
+		}(id, m)
+	}
+
+	wg.Wait()
+
+	r.mu.Lock()
+	r.health = results
+	r.mu.Unlock()
+	return results
+}
+
+// Refresh re-runs every model's health check and reports which models became
+// reachable or unreachable since the last check
+func (r *Registry) Refresh(ctx context.Context) RefreshResult {
+	r.mu.Lock()
+	previous := r.health
+	r.mu.Unlock()
+
+	result := RefreshResult{Health: r.HealthCheckAll(ctx)}
+	for _, id := range r.order {
+		wasHealthy := previous[id] == nil
+		healthy := result.Health[id] == nil
+		switch {
+		case !wasHealthy && healthy:
+			result.Recovered = append(result.Recovered, id)
+		case wasHealthy && !healthy:
+			result.Lost = append(result.Lost, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+// internal/models/registry_test.go
+package models
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"roundtable/internal/config"
+)
+
+func TestNewRegistry_ConfiguredColors(t *testing.T) {
+	cfg := config.Default()
+	cfg.Models.Claude.Color = "#123456"
+	cfg.Models.Exec = []config.ExecModelConfig{
+		{ID: "local", Enabled: true, Command: "true", Color: "#ABCDEF"},
+		{ID: "plain", Enabled: true, Command: "true"},
+	}
+	r := NewRegistry(cfg)
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"claude", "#123456"},
+		{"gemini", "#FF00FF"}, // Unconfigured built-in keeps its default
+		{"local", "#ABCDEF"},
+		{"plain", ""},
+	}
+	for _, tt := range tests {
+		m := r.Get(tt.id)
+		if m == nil {
+			t.Fatalf("model %q not registered", tt.id)
+		}
+		if got := m.Info().Color; got != tt.want {
+			t.Errorf("%s color = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestRegistry_MoveModel(t *testing.T) {
+	cfg := &config.Config{}
+	for _, id := range []string{"a", "b", "c"} {
+		cfg.Models.Exec = append(cfg.Models.Exec, config.ExecModelConfig{ID: id, Enabled: true, Command: "true"})
+	}
+
+	tests := []struct {
+		name  string
+		id    string
+		delta int
+		want  []string
+		moved bool
+	}{
+		{"down one", "a", 1, []string{"b", "a", "c"}, true},
+		{"up one", "c", -1, []string{"a", "c", "b"}, true},
+		{"first can't move up", "a", -1, []string{"a", "b", "c"}, false},
+		{"last can't move down", "c", 1, []string{"a", "b", "c"}, false},
+		{"clamped to the end", "a", 5, []string{"b", "c", "a"}, true},
+		{"unknown model", "zed", 1, []string{"a", "b", "c"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry(cfg)
+			before := r.Enabled()
+			if got := r.MoveModel(tt.id, tt.delta); got != tt.moved {
+				t.Errorf("MoveModel(%q, %d) = %v, want %v", tt.id, tt.delta, got, tt.moved)
+			}
+			if got := r.Enabled(); !slices.Equal(got, tt.want) {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(before, []string{"a", "b", "c"}) {
+				t.Errorf("MoveModel changed a slice already returned by Enabled: %v", before)
+			}
+		})
+	}
+}
+
+func TestNewRegistry_ModelOrder(t *testing.T) {
+	cfg := &config.Config{}
+	for _, id := range []string{"a", "b", "c"} {
+		cfg.Models.Exec = append(cfg.Models.Exec, config.ExecModelConfig{ID: id, Enabled: true, Command: "true"})
+	}
+	cfg.UI.ModelOrder = []string{"c", "gone", "a"}
+
+	if got := NewRegistry(cfg).Enabled(); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("Enabled() = %v, want [c a b]", got)
+	}
+}
+
+func TestRegistry_RefreshFindsRecoveredModel(t *testing.T) {
+	command := filepath.Join(t.TempDir(), "local-model")
+	cfg := &config.Config{}
+	cfg.Models.Exec = []config.ExecModelConfig{
+		{ID: "local", Enabled: true, Command: command},
+	}
+	r := NewRegistry(cfg)
+
+	result := r.Refresh(context.Background())
+	if result.Health["local"] == nil {
+		t.Fatal("expected a missing command to fail its health check")
+	}
+	if !slices.Equal(result.Lost, []string{"local"}) || len(result.Recovered) != 0 {
+		t.Fatalf("first refresh: lost %v, recovered %v", result.Lost, result.Recovered)
+	}
+
+	// The backend gets installed after startup
+	if err := os.WriteFile(command, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	result = r.Refresh(context.Background())
+	if err := result.Health["local"]; err != nil {
+		t.Fatalf("expected local to be reachable, got %v", err)
+	}
+	if !slices.Equal(result.Recovered, []string{"local"}) || len(result.Lost) != 0 {
+		t.Errorf("second refresh: recovered %v, lost %v", result.Recovered, result.Lost)
+	}
+
+	// Nothing changed since the last check
+	result = r.Refresh(context.Background())
+	if len(result.Recovered) != 0 || len(result.Lost) != 0 {
+		t.Errorf("third refresh: recovered %v, lost %v", result.Recovered, result.Lost)
+	}
+}
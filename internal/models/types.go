@@ -8,16 +8,38 @@ type Chunk struct {
 	Text      string
 	Done      bool
 	Error     error
-	IsTimeout bool // Distinguishes timeout from other errors
+	IsTimeout bool   // Distinguishes timeout from other errors
+	Usage     *Usage // Token/cost usage, set on the final chunk when the backend reports it
+}
+
+// Usage reports token consumption and cost for a single model response
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"` // USD; zero if the backend doesn't report cost
+}
+
+// Add accumulates another usage report into u
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.Cost += other.Cost
+}
+
+// TotalTokens returns prompt plus completion tokens
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
 }
 
 // Message represents a message in the debate
 type Message struct {
-	Source    string    // claude, gpt, gemini, grok, user, system
+	Source    string // claude, gpt, gemini, grok, user, system
 	Content   string
-	Type      string    // model, user, system, tool, meta
+	Type      string // model, user, system, tool, meta
 	Timestamp time.Time
-	ToolName  string    // for tool messages
+	ToolName  string // for tool messages
+
+	Attachments []Attachment // Images sent with a user message, for models that can see them
 }
 
 // ModelStatus represents the current state of a model
@@ -50,9 +72,10 @@ func (s ModelStatus) String() string {
 
 // ModelInfo contains display information for a model
 type ModelInfo struct {
-	ID       string // claude, gpt, gemini, grok
-	Name     string // Display name
-	Color    string // Hex color for UI
-	CanExec  bool   // Can execute tools
-	CanRead  bool   // Can read files
+	ID           string // claude, gpt, gemini, grok
+	Name         string // Display name
+	Color        string // Hex color for UI
+	CanExec      bool   // Can execute tools
+	CanRead      bool   // Can read files
+	CanSeeImages bool   // Accepts image attachments
 }
@@ -4,9 +4,13 @@ package orchestrator
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"time"
 
+	"roundtable/internal/config"
+	"roundtable/internal/consensus"
+	"roundtable/internal/metrics"
 	"roundtable/internal/models"
 )
 
@@ -23,15 +27,26 @@ type Response struct {
 	Content   string
 	Error     error
 	Done      bool
-	IsTimeout bool // True if the error was due to timeout
+	Started   bool          // First response for a model: it has begun working on the prompt
+	IsTimeout bool          // True if the error was due to timeout
+	Usage     *models.Usage // Token/cost usage, if the model reported it (Done only)
+	Stopped   bool          // The round ended on consensus before this model finished (Done only)
 }
 
 // Orchestrator manages multi-model debate
 type Orchestrator struct {
-	registry      *models.Registry
-	timeout       time.Duration
-	retryAttempts int
-	retryDelay    time.Duration
+	registry       *models.Registry
+	timeoutMu      sync.Mutex // Guards timeout, which can change while a round runs
+	timeout        time.Duration
+	retryAttempts  int
+	retryDelay     time.Duration
+	maxConcurrency int // Max models in flight at once; <= 0 means unlimited
+
+	// Ends a round early once the finished models agree; nil means never
+	stopParser          *consensus.Parser
+	stopMinParticipants int
+
+	metrics *metrics.Metrics // Records each model's outcome and latency
 }
 
 func New(registry *models.Registry, timeout time.Duration) *Orchestrator {
@@ -40,6 +55,7 @@ func New(registry *models.Registry, timeout time.Duration) *Orchestrator {
 		timeout:       timeout,
 		retryAttempts: 3,
 		retryDelay:    time.Second,
+		metrics:       metrics.Default,
 	}
 }
 
@@ -50,26 +66,126 @@ func NewWithRetry(registry *models.Registry, timeout time.Duration, retryAttempt
 		timeout:       timeout,
 		retryAttempts: retryAttempts,
 		retryDelay:    retryDelay,
+		metrics:       metrics.Default,
+	}
+}
+
+// NewFromConfig creates an orchestrator with the timeout, retry, concurrency
+// and early-stop settings from cfg
+func NewFromConfig(cfg *config.Config, registry *models.Registry) *Orchestrator {
+	timeout := time.Duration(cfg.Defaults.ModelTimeout) * time.Second
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+	retryAttempts := cfg.Defaults.RetryAttempts
+	if retryAttempts == 0 {
+		retryAttempts = 3
 	}
+	retryDelay := time.Duration(cfg.Defaults.RetryDelay) * time.Millisecond
+	if retryDelay == 0 {
+		retryDelay = time.Second
+	}
+	orch := NewWithRetry(registry, timeout, retryAttempts, retryDelay)
+	orch.SetMaxConcurrency(cfg.Defaults.MaxConcurrency)
+	if cfg.Defaults.StopOnConsensus {
+		orch.SetStopOnConsensus(consensus.NewParser(cfg.Consensus.ParserOptions()), cfg.Defaults.MinConsensusParticipants)
+	}
+	return orch
+}
+
+// SetMaxConcurrency caps how many models are queried at once.
+// Zero or negative means unlimited.
+func (o *Orchestrator) SetMaxConcurrency(n int) {
+	o.maxConcurrency = n
+}
+
+// SetStopOnConsensus ends parallel rounds early: once most of the round's
+// models have finished and agree with no objections, the rest are stopped.
+// A nil parser turns this off.
+func (o *Orchestrator) SetStopOnConsensus(parser *consensus.Parser, minParticipants int) {
+	o.stopParser = parser
+	o.stopMinParticipants = minParticipants
+}
+
+// SetTimeout changes how long each model gets to respond. Requests already
+// sent keep the timeout they started with.
+func (o *Orchestrator) SetTimeout(d time.Duration) {
+	o.timeoutMu.Lock()
+	defer o.timeoutMu.Unlock()
+	o.timeout = d
+}
+
+// Timeout returns how long each model gets to respond
+func (o *Orchestrator) Timeout() time.Duration {
+	o.timeoutMu.Lock()
+	defer o.timeoutMu.Unlock()
+	return o.timeout
+}
+
+// SetMetrics sets where model outcomes are recorded; nil records nothing
+func (o *Orchestrator) SetMetrics(m *metrics.Metrics) {
+	o.metrics = m
+}
+
+// PromptFunc builds the prompt one model is sent, e.g. leaving out file
+// contents for models that can't read files
+type PromptFunc func(info models.ModelInfo) string
+
+// samePrompt is a PromptFunc sending every model prompt
+func samePrompt(prompt string) PromptFunc {
+	return func(models.ModelInfo) string { return prompt }
 }
 
 // ParallelSeed sends the initial prompt to all models in parallel
 // Graceful degradation: continues with remaining models if one fails
 func (o *Orchestrator) ParallelSeed(ctx context.Context, history []models.Message, prompt string) <-chan Response {
-	responses := make(chan Response, o.registry.Count()*10)
+	return o.ParallelSeedEach(ctx, history, samePrompt(prompt))
+}
+
+// ParallelSeedEach is ParallelSeed with each model's prompt built by promptFor
+func (o *Orchestrator) ParallelSeedEach(ctx context.Context, history []models.Message, promptFor PromptFunc) <-chan Response {
+	return o.sendAll(ctx, o.registry.Enabled(), o.registry.Get, history, promptFor)
+}
+
+// sendAll sends a prompt to the given models in parallel, running at most
+// maxConcurrency at a time. Responses stream as each model produces them.
+func (o *Orchestrator) sendAll(ctx context.Context, ids []string, get func(string) models.Model, history []models.Message, promptFor PromptFunc) <-chan Response {
+	responses := make(chan Response, len(ids)*10)
+
+	var stop context.CancelFunc
+	if o.stopParser != nil {
+		ctx, stop = context.WithCancel(ctx)
+	}
+
+	var sem chan struct{}
+	if o.maxConcurrency > 0 {
+		sem = make(chan struct{}, o.maxConcurrency)
+	}
 
 	var wg sync.WaitGroup
+	var sent []string
 
-	for _, modelID := range o.registry.Enabled() {
-		model := o.registry.Get(modelID)
+	for _, modelID := range ids {
+		model := get(modelID)
 		if model == nil {
 			continue
 		}
+		sent = append(sent, modelID)
 
 		wg.Add(1)
 		go func(m models.Model, id string) {
 			defer wg.Done()
-			o.sendWithTimeout(ctx, m, id, history, prompt, responses)
+			if sem != nil {
+				m.SetStatus(models.StatusWaiting)
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					m.SetStatus(models.StatusIdle)
+					return
+				}
+			}
+			o.sendWithTimeout(ctx, m, id, history, promptFor(m.Info()), responses)
 		}(model, modelID)
 	}
 
@@ -79,24 +195,109 @@ func (o *Orchestrator) ParallelSeed(ctx context.Context, history []models.Messag
 		close(responses)
 	}()
 
+	if stop != nil {
+		return o.untilConsensus(responses, sent, stop, get)
+	}
 	return responses
 }
 
+// untilConsensus forwards a round's responses, checking for consensus as
+// each model finishes. Once it's reached, the models yet to finish are
+// stopped, reported as Stopped, and the channel closes.
+func (o *Orchestrator) untilConsensus(in <-chan Response, ids []string, stop context.CancelFunc, get func(string) models.Model) <-chan Response {
+	out := make(chan Response, cap(in))
+
+	// Most of the round has to have answered, not just the fastest two
+	minParticipants := max(o.stopMinParticipants, len(ids)/2+1)
+
+	go func() {
+		defer close(out)
+		defer stop()
+
+		content := make(map[string]*strings.Builder)
+		running := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			running[id] = true
+		}
+		positions := make(map[string]consensus.ParsedPosition)
+
+		for resp := range in {
+			out <- resp
+			if resp.Content != "" {
+				if content[resp.ModelID] == nil {
+					content[resp.ModelID] = &strings.Builder{}
+				}
+				content[resp.ModelID].WriteString(resp.Content)
+			}
+			if !resp.Done {
+				continue
+			}
+
+			delete(running, resp.ModelID)
+			if b := content[resp.ModelID]; b != nil && resp.Error == nil {
+				positions[resp.ModelID] = o.stopParser.ParseResponse(b.String())
+			}
+			if len(running) == 0 || !consensus.AnalyzeConsensusWithMinimum(positions, minParticipants).HasConsensus {
+				continue
+			}
+
+			stop()
+			for _, id := range ids {
+				if !running[id] {
+					continue
+				}
+				if m := get(id); m != nil {
+					m.Stop()
+				}
+				out <- Response{ModelID: id, Done: true, Stopped: true}
+			}
+			// Let the stopped models' goroutines finish
+			go func() {
+				for range in {
+				}
+			}()
+			return
+		}
+	}()
+
+	return out
+}
+
 // sendWithTimeout sends a prompt to a model with timeout handling
 func (o *Orchestrator) sendWithTimeout(ctx context.Context, m models.Model, id string, history []models.Message, prompt string, responses chan<- Response) {
-	timeoutCtx, cancel := context.WithTimeout(ctx, o.timeout)
+	timeoutCtx, cancel := context.WithTimeout(ctx, o.Timeout())
 	defer cancel()
 
+	// Cancelled rounds leave outcome empty and aren't recorded
+	start := time.Now()
+	var outcome string
+	defer func() {
+		if outcome != "" {
+			o.metrics.ModelResponded(id, outcome, time.Since(start))
+		}
+	}()
+
+	// Let the UI show the model as working before its first chunk arrives
+	m.SetStatus(models.StatusResponding)
+	responses <- Response{ModelID: id, Started: true}
+
 	chunks := m.Send(timeoutCtx, history, prompt)
 
 	// Channel to detect if we got any response
 	gotResponse := false
+	attempt := 1
 
 	for {
 		select {
 		case <-timeoutCtx.Done():
+			// The round was cancelled; whoever cancelled it reports that
+			if errors.Is(ctx.Err(), context.Canceled) {
+				m.SetStatus(models.StatusIdle)
+				return
+			}
 			// Timeout occurred
 			m.SetStatus(models.StatusTimeout)
+			outcome = metrics.OutcomeTimeout
 			responses <- Response{
 				ModelID:   id,
 				Error:     ErrTimeout,
@@ -108,7 +309,9 @@ func (o *Orchestrator) sendWithTimeout(ctx context.Context, m models.Model, id s
 		case chunk, ok := <-chunks:
 			if !ok {
 				// Channel closed without Done - treat as complete if we got content
+				m.SetStatus(models.StatusIdle)
 				if gotResponse {
+					outcome = metrics.OutcomeSuccess
 					responses <- Response{
 						ModelID: id,
 						Done:    true,
@@ -117,10 +320,37 @@ func (o *Orchestrator) sendWithTimeout(ctx context.Context, m models.Model, id s
 				return
 			}
 
+			// Rate limited before any output: wait as long as the server
+			// asked, then try again
+			if wait, limited := o.rateLimitWait(chunk.Error); limited && !gotResponse && attempt < o.retryAttempts {
+				select {
+				case <-timeoutCtx.Done():
+					if errors.Is(ctx.Err(), context.Canceled) {
+						m.SetStatus(models.StatusIdle)
+						return
+					}
+					m.SetStatus(models.StatusTimeout)
+					outcome = metrics.OutcomeTimeout
+					responses <- Response{
+						ModelID:   id,
+						Error:     ErrTimeout,
+						IsTimeout: true,
+						Done:      true,
+					}
+					return
+				case <-time.After(wait):
+				}
+				attempt++
+				m.SetStatus(models.StatusResponding)
+				chunks = m.Send(timeoutCtx, history, prompt)
+				continue
+			}
+
 			if chunk.Error != nil {
 				// Check if it's a timeout from the model itself
 				if chunk.IsTimeout || errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
 					m.SetStatus(models.StatusTimeout)
+					outcome = metrics.OutcomeTimeout
 					responses <- Response{
 						ModelID:   id,
 						Error:     ErrTimeout,
@@ -129,6 +359,7 @@ func (o *Orchestrator) sendWithTimeout(ctx context.Context, m models.Model, id s
 					}
 				} else {
 					m.SetStatus(models.StatusError)
+					outcome = metrics.OutcomeError
 					responses <- Response{
 						ModelID: id,
 						Error:   chunk.Error,
@@ -148,9 +379,11 @@ func (o *Orchestrator) sendWithTimeout(ctx context.Context, m models.Model, id s
 
 			if chunk.Done {
 				m.SetStatus(models.StatusIdle)
+				outcome = metrics.OutcomeSuccess
 				responses <- Response{
 					ModelID: id,
 					Done:    true,
+					Usage:   chunk.Usage,
 				}
 				return
 			}
@@ -158,6 +391,19 @@ func (o *Orchestrator) sendWithTimeout(ctx context.Context, m models.Model, id s
 	}
 }
 
+// rateLimitWait reports whether err is a rate limit and how long to wait
+// before retrying: the server's Retry-After, or retryDelay if it gave none
+func (o *Orchestrator) rateLimitWait(err error) (time.Duration, bool) {
+	var rl *models.RateLimitError
+	if !errors.As(err, &rl) {
+		return 0, false
+	}
+	if rl.RetryAfter > 0 {
+		return rl.RetryAfter, true
+	}
+	return o.retryDelay, true
+}
+
 // SendToModel sends a prompt to a specific model with timeout handling
 func (o *Orchestrator) SendToModel(ctx context.Context, modelID string, history []models.Message, prompt string) <-chan Response {
 	responses := make(chan Response, 10)
@@ -188,6 +434,19 @@ Be explicit about your position.`
 	return o.ParallelSeed(ctx, history, prompt)
 }
 
+// ChallengePrompt asks one model to play devil's advocate and argue against
+// the agreement emerging in the discussion
+func (o *Orchestrator) ChallengePrompt(ctx context.Context, modelID string, history []models.Message) <-chan Response {
+	return o.SendToModel(ctx, modelID, history, challengePrompt)
+}
+
+// challengePrompt frames a forced-dissent turn for ChallengePrompt
+const challengePrompt = `You are now the devil's advocate. The discussion is converging on an agreement; your job is to stress-test it before anyone acts on it.
+
+Find the strongest objection to the current consensus: a flaw, risk, hidden cost, or unexamined alternative that the others have missed or dismissed too quickly. Argue it as persuasively as you honestly can, even if you agreed earlier.
+
+Start your reply with "OBJECT:" followed by the objection in one line, then explain it and what would have to change to resolve it.`
+
 // StopAll stops all models
 func (o *Orchestrator) StopAll() {
 	for _, modelID := range o.registry.Enabled() {
@@ -4,11 +4,16 @@ package orchestrator
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"roundtable/internal/consensus"
+	"roundtable/internal/metrics"
 	"roundtable/internal/models"
 )
 
@@ -16,6 +21,7 @@ import (
 type MockModel struct {
 	id           string
 	name         string
+	canRead      bool
 	status       models.ModelStatus
 	sendFunc     func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk
 	stopCalled   atomic.Bool
@@ -34,8 +40,9 @@ func NewMockModel(id, name string) *MockModel {
 
 func (m *MockModel) Info() models.ModelInfo {
 	return models.ModelInfo{
-		ID:   m.id,
-		Name: m.name,
+		ID:      m.id,
+		Name:    m.name,
+		CanRead: m.canRead,
 	}
 }
 
@@ -70,6 +77,10 @@ func (m *MockModel) SetStatus(status models.ModelStatus) {
 	m.statusCalled = append(m.statusCalled, status)
 }
 
+func (m *MockModel) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
 func (m *MockModel) WasStopCalled() bool {
 	return m.stopCalled.Load()
 }
@@ -134,29 +145,12 @@ func newTestOrchestrator(timeout time.Duration) (*TestOrchestrator, *MockRegistr
 
 // ParallelSeed overrides to use mock registry
 func (to *TestOrchestrator) ParallelSeed(ctx context.Context, history []models.Message, prompt string) <-chan Response {
-	responses := make(chan Response, to.mockRegistry.Count()*10)
-
-	var wg sync.WaitGroup
-
-	for _, modelID := range to.mockRegistry.Enabled() {
-		model := to.mockRegistry.Get(modelID)
-		if model == nil {
-			continue
-		}
-
-		wg.Add(1)
-		go func(m models.Model, id string) {
-			defer wg.Done()
-			to.sendWithTimeout(ctx, m, id, history, prompt, responses)
-		}(model, modelID)
-	}
-
-	go func() {
-		wg.Wait()
-		close(responses)
-	}()
+	return to.ParallelSeedEach(ctx, history, samePrompt(prompt))
+}
 
-	return responses
+// ParallelSeedEach overrides to use mock registry
+func (to *TestOrchestrator) ParallelSeedEach(ctx context.Context, history []models.Message, promptFor PromptFunc) <-chan Response {
+	return to.sendAll(ctx, to.mockRegistry.Enabled(), to.mockRegistry.Get, history, promptFor)
 }
 
 // SendToModel overrides to use mock registry
@@ -189,6 +183,11 @@ Be explicit about your position.`
 	return to.ParallelSeed(ctx, history, prompt)
 }
 
+// ChallengePrompt overrides to use mock registry
+func (to *TestOrchestrator) ChallengePrompt(ctx context.Context, modelID string, history []models.Message) <-chan Response {
+	return to.SendToModel(ctx, modelID, history, challengePrompt)
+}
+
 // StopAll overrides to use mock registry
 func (to *TestOrchestrator) StopAll() {
 	for _, modelID := range to.mockRegistry.Enabled() {
@@ -243,6 +242,47 @@ func TestNewWithRetry(t *testing.T) {
 	}
 }
 
+func TestSetTimeout_AppliesToNextRound(t *testing.T) {
+	orch, mockReg := newTestOrchestrator(time.Minute)
+
+	slow := NewMockModel("slow", "Slow Model")
+	slow.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+		ch := make(chan models.Chunk)
+		go func() {
+			defer close(ch)
+			select {
+			case <-ctx.Done():
+			case <-time.After(300 * time.Millisecond):
+				ch <- models.Chunk{Text: "late answer"}
+				ch <- models.Chunk{Done: true}
+			}
+		}()
+		return ch
+	}
+	mockReg.Add("slow", slow)
+
+	timedOut := func() bool {
+		for r := range orch.ParallelSeed(context.Background(), nil, "Test prompt") {
+			if r.IsTimeout {
+				return true
+			}
+		}
+		return false
+	}
+
+	if timedOut() {
+		t.Fatal("the slow model shouldn't time out under a one minute timeout")
+	}
+
+	orch.SetTimeout(50 * time.Millisecond)
+	if got := orch.Timeout(); got != 50*time.Millisecond {
+		t.Errorf("Timeout() = %v, want 50ms", got)
+	}
+	if !timedOut() {
+		t.Error("expected the next round to time out under the shorter timeout")
+	}
+}
+
 // --- ParallelSeed Tests ---
 
 func TestParallelSeed_SendsToAllEnabledModels(t *testing.T) {
@@ -299,6 +339,42 @@ func TestParallelSeed_SendsToAllEnabledModels(t *testing.T) {
 	}
 }
 
+func TestParallelSeedEach_BuildsPromptPerModel(t *testing.T) {
+	orch, mockReg := newTestOrchestrator(5 * time.Second)
+
+	received := make(map[string]string)
+	var mu sync.Mutex
+	for _, id := range []string{"reader", "nonreader"} {
+		m := NewMockModel(id, id)
+		m.canRead = id == "reader"
+		m.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+			mu.Lock()
+			received[id] = prompt
+			mu.Unlock()
+			ch := make(chan models.Chunk, 1)
+			ch <- models.Chunk{Done: true}
+			close(ch)
+			return ch
+		}
+		mockReg.Add(id, m)
+	}
+
+	promptFor := func(info models.ModelInfo) string {
+		if info.CanRead {
+			return "full file contents"
+		}
+		return "file summary"
+	}
+	for range orch.ParallelSeedEach(context.Background(), nil, promptFor) {
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if received["reader"] != "full file contents" || received["nonreader"] != "file summary" {
+		t.Errorf("prompts = %q, want the reader to get full contents and the other a summary", received)
+	}
+}
+
 func TestParallelSeed_HandlesTimeoutGracefully(t *testing.T) {
 	orch, mockReg := newTestOrchestrator(100 * time.Millisecond)
 
@@ -665,6 +741,138 @@ func TestSendToModel_HandlesTimeout(t *testing.T) {
 	}
 }
 
+func TestSendToModel_WaitsForRetryAfterOnRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, time.Now())
+		first := len(requests) == 1
+		mu.Unlock()
+
+		if first {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hello\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond) // Keep the chunks in separate reads, like a real stream
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":1,\"completion_tokens\":1}}\n\n")
+	}))
+	defer server.Close()
+
+	orch, mockReg := newTestOrchestrator(10 * time.Second)
+	orch.retryDelay = 10 * time.Millisecond // Must not be used when Retry-After is given
+	gpt := models.NewGPTWithRetry("key", "gpt-4o", models.RetryConfig{MaxAttempts: 1})
+	gpt.SetBaseURL(server.URL)
+	mockReg.Add("gpt", gpt)
+
+	var content string
+	for r := range orch.SendToModel(context.Background(), "gpt", nil, "Test prompt") {
+		if r.Error != nil {
+			t.Fatalf("unexpected error: %v", r.Error)
+		}
+		content += r.Content
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if wait := requests[1].Sub(requests[0]); wait < 1900*time.Millisecond || wait > 4*time.Second {
+		t.Errorf("retried after %v, want about 2s", wait)
+	}
+	if !containsString(content, "hello") {
+		t.Errorf("expected the retried response, got %q", content)
+	}
+}
+
+func TestSendToModel_CancelDuringRetryAfterIsNotATimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	orch, mockReg := newTestOrchestrator(10 * time.Second)
+	recorded := metrics.New()
+	orch.SetMetrics(recorded)
+	gpt := models.NewGPTWithRetry("key", "gpt-4o", models.RetryConfig{MaxAttempts: 1})
+	gpt.SetBaseURL(server.URL)
+	mockReg.Add("gpt", gpt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	responses := orch.SendToModel(ctx, "gpt", nil, "Test prompt")
+	go func() {
+		time.Sleep(200 * time.Millisecond) // Well inside the 30s wait
+		cancel()
+	}()
+
+	start := time.Now()
+	for r := range responses {
+		if r.IsTimeout || r.Error != nil {
+			t.Errorf("cancel during the wait reported %+v", r)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancel did not end the wait, took %v", elapsed)
+	}
+	if got := recorded.ModelCount("gpt", metrics.OutcomeTimeout); got != 0 {
+		t.Errorf("timeouts recorded = %d, want 0", got)
+	}
+	if status := gpt.Status(); status != models.StatusIdle {
+		t.Errorf("status = %v, want idle", status)
+	}
+}
+
+// --- ChallengePrompt Tests ---
+
+func TestChallengePrompt_TargetsOneModelWithDissentFraming(t *testing.T) {
+	orch, mockReg := newTestOrchestrator(5 * time.Second)
+
+	var mu sync.Mutex
+	prompts := make(map[string]string)
+	for _, id := range []string{"claude", "gpt", "gemini"} {
+		model := NewMockModel(id, id)
+		model.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+			mu.Lock()
+			prompts[id] = prompt
+			mu.Unlock()
+			ch := make(chan models.Chunk, 2)
+			ch <- models.Chunk{Text: "OBJECT: no rollback plan"}
+			ch <- models.Chunk{Done: true}
+			close(ch)
+			return ch
+		}
+		mockReg.Add(id, model)
+	}
+
+	history := []models.Message{{Source: "user", Content: "Monolith or services?"}}
+	var responders []string
+	for r := range orch.ChallengePrompt(context.Background(), "gpt", history) {
+		if r.Content != "" {
+			responders = append(responders, r.ModelID)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(prompts) != 1 || prompts["gpt"] == "" {
+		t.Fatalf("expected only gpt to be prompted, got prompts for %v", prompts)
+	}
+	for _, phrase := range []string{"devil's advocate", "strongest objection", "OBJECT:"} {
+		if !containsString(prompts["gpt"], phrase) {
+			t.Errorf("challenge prompt missing %q:\n%s", phrase, prompts["gpt"])
+		}
+	}
+	if len(responders) == 0 || responders[0] != "gpt" {
+		t.Errorf("responses came from %v, want gpt", responders)
+	}
+}
+
 // --- ConsensusPrompt Tests ---
 
 func TestConsensusPrompt_UsesCorrectPrompt(t *testing.T) {
@@ -888,6 +1096,43 @@ func TestParallelSeed_SetsCorrectErrorType(t *testing.T) {
 	}
 }
 
+func TestParallelSeed_RecordsMetrics(t *testing.T) {
+	orch, mockReg := newTestOrchestrator(5 * time.Second)
+	recorded := metrics.New()
+	orch.SetMetrics(recorded)
+
+	failing := NewMockModel("failing", "Failing Model")
+	failing.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+		ch := make(chan models.Chunk, 1)
+		ch <- models.Chunk{Error: errors.New("backend crashed")}
+		close(ch)
+		return ch
+	}
+	working := NewMockModel("working", "Working Model")
+	working.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+		ch := make(chan models.Chunk, 2)
+		ch <- models.Chunk{Text: "Fine"}
+		ch <- models.Chunk{Done: true}
+		close(ch)
+		return ch
+	}
+	mockReg.Add("failing", failing)
+	mockReg.Add("working", working)
+
+	for range orch.ParallelSeed(context.Background(), nil, "Test prompt") {
+	}
+
+	if got := recorded.ModelCount("failing", metrics.OutcomeError); got != 1 {
+		t.Errorf("failing errors = %d, want 1", got)
+	}
+	if got := recorded.ModelCount("working", metrics.OutcomeSuccess); got != 1 {
+		t.Errorf("working successes = %d, want 1", got)
+	}
+	if got := recorded.ModelCount("failing", metrics.OutcomeSuccess); got != 0 {
+		t.Errorf("failing successes = %d, want 0", got)
+	}
+}
+
 // --- Status Update Tests ---
 
 func TestParallelSeed_SetsIdleStatusOnSuccess(t *testing.T) {
@@ -968,6 +1213,152 @@ func TestParallelSeed_ConcurrentResponseHandling(t *testing.T) {
 	}
 }
 
+func TestParallelSeed_StartSignalPrecedesContent(t *testing.T) {
+	orch, mockReg := newTestOrchestrator(5 * time.Second)
+
+	m := NewMockModel("claude", "Claude")
+	mockReg.Add("claude", m)
+
+	var got []Response
+	for r := range orch.ParallelSeed(context.Background(), nil, "Test prompt") {
+		got = append(got, r)
+	}
+
+	if len(got) < 2 {
+		t.Fatalf("Expected start signal and content, got %+v", got)
+	}
+	if !got[0].Started || got[0].ModelID != "claude" {
+		t.Errorf("Expected first response to be a start signal for claude, got %+v", got[0])
+	}
+	if got[0].Content != "" || got[0].Done {
+		t.Errorf("Start signal should carry no content or done flag, got %+v", got[0])
+	}
+	for _, r := range got[1:] {
+		if r.Started {
+			t.Errorf("Expected a single start signal, got another: %+v", r)
+		}
+	}
+
+	history := m.GetStatusHistory()
+	if len(history) == 0 || history[0] != models.StatusResponding {
+		t.Errorf("Expected model to be marked responding first, got %v", history)
+	}
+}
+
+func TestParallelSeed_RespectsMaxConcurrency(t *testing.T) {
+	orch, mockReg := newTestOrchestrator(5 * time.Second)
+	orch.SetMaxConcurrency(2)
+
+	var inFlight, maxInFlight atomic.Int32
+	for i := 0; i < 5; i++ {
+		id := "model" + string(rune('A'+i))
+		m := NewMockModel(id, id)
+		m.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+			ch := make(chan models.Chunk, 2)
+			go func() {
+				defer close(ch)
+				n := inFlight.Add(1)
+				for {
+					prev := maxInFlight.Load()
+					if n <= prev || maxInFlight.CompareAndSwap(prev, n) {
+						break
+					}
+				}
+				time.Sleep(30 * time.Millisecond)
+				inFlight.Add(-1)
+				ch <- models.Chunk{Text: "done"}
+				ch <- models.Chunk{Done: true}
+			}()
+			return ch
+		}
+		mockReg.Add(id, m)
+	}
+
+	var doneCount int
+	for r := range orch.ParallelSeed(context.Background(), nil, "Test prompt") {
+		if r.Done {
+			doneCount++
+		}
+	}
+
+	if doneCount != 5 {
+		t.Errorf("Expected 5 done signals, got %d", doneCount)
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("Expected at most 2 models in flight, saw %d", got)
+	}
+	if got := maxInFlight.Load(); got < 2 {
+		t.Errorf("Expected models to run concurrently up to the cap, max in flight was %d", got)
+	}
+}
+
+func TestParallelSeed_StopsStragglersOnConsensus(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string // What the fast models say
+		wantStop bool
+	}{
+		{"agreement stops the slow model", "AGREE: claude, ship it", true},
+		{"objection waits for the slow model", "OBJECT: not yet", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch, mockReg := newTestOrchestrator(5 * time.Second)
+			orch.SetStopOnConsensus(consensus.NewParser(consensus.ParserOptions{}), 2)
+
+			for _, id := range []string{"claude", "gemini", "gpt"} {
+				m := NewMockModel(id, id)
+				m.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+					ch := make(chan models.Chunk, 2)
+					ch <- models.Chunk{Text: tt.reply}
+					ch <- models.Chunk{Done: true}
+					close(ch)
+					return ch
+				}
+				mockReg.Add(id, m)
+			}
+			slow := NewMockModel("grok", "Grok")
+			slow.sendFunc = func(ctx context.Context, history []models.Message, prompt string) <-chan models.Chunk {
+				ch := make(chan models.Chunk, 2)
+				go func() {
+					defer close(ch)
+					ch <- models.Chunk{Text: "Still thinking"}
+					select {
+					case <-ctx.Done():
+					case <-time.After(300 * time.Millisecond):
+						ch <- models.Chunk{Text: ", AGREE: claude"}
+						ch <- models.Chunk{Done: true}
+					}
+				}()
+				return ch
+			}
+			mockReg.Add("grok", slow)
+
+			var slowDone Response
+			for r := range orch.ParallelSeed(context.Background(), nil, "Test prompt") {
+				if r.ModelID == "grok" && r.Done {
+					slowDone = r
+				}
+			}
+
+			if slow.WasStopCalled() != tt.wantStop {
+				t.Errorf("slow model stopped = %v, want %v", slow.WasStopCalled(), tt.wantStop)
+			}
+			if slowDone.Stopped != tt.wantStop || slowDone.Error != nil {
+				t.Errorf("slow model's done response = %+v, want Stopped %v", slowDone, tt.wantStop)
+			}
+			if tt.wantStop {
+				// Give the cancelled send a moment to wind down
+				time.Sleep(50 * time.Millisecond)
+				if got := slow.Status(); got != models.StatusIdle {
+					t.Errorf("stopped model status = %v, want idle", got)
+				}
+			}
+		})
+	}
+}
+
 // Helper function
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsStringHelper(s, substr))
@@ -0,0 +1,84 @@
+// internal/transcript/transcript.go
+
+// Package transcript appends finalized debate messages and consensus events
+// to a JSON Lines file, so other tools can follow a debate as it happens
+package transcript
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TypeConsensus is the type of events recording a reached consensus
+const TypeConsensus = "consensus"
+
+// Event is one line of the transcript
+type Event struct {
+	DebateID  string    `json:"debate_id"`
+	Source    string    `json:"source"`
+	Content   string    `json:"content"`
+	Type      string    `json:"type"` // The stored message type, or TypeConsensus
+	Timestamp time.Time `json:"ts"`
+}
+
+// Writer appends events to a transcript file. Methods are safe on a nil
+// *Writer, which writes nothing.
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Open opens path for appending, creating it and its directory if needed
+func Open(path string) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{file: f}, nil
+}
+
+// Write appends e as one line. Lines aren't buffered, so a reader tailing
+// the file sees each event as soon as it's written.
+func (w *Writer) Write(e Event) error {
+	if w == nil {
+		return nil
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.file.Write(append(line, '\n'))
+	return err
+}
+
+// Message records a finalized debate message
+func (w *Writer) Message(debateID, source, content, msgType string) error {
+	return w.Write(Event{DebateID: debateID, Source: source, Content: content, Type: msgType})
+}
+
+// Consensus records that a debate reached consensus, described by summary
+func (w *Writer) Consensus(debateID, summary string) error {
+	return w.Write(Event{DebateID: debateID, Source: "system", Content: summary, Type: TypeConsensus})
+}
+
+// Close closes the file
+func (w *Writer) Close() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
@@ -0,0 +1,66 @@
+// internal/transcript/transcript_test.go
+package transcript
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriter_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "transcript.jsonl")
+	w, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	if err := w.Message("d1", "claude", "AGREE: ship it\nwith tests", "model"); err != nil {
+		t.Fatalf("Message() failed: %v", err)
+	}
+
+	// Each line is readable before the writer is closed
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e Event
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(string(data), "\n")), &e); err != nil {
+		t.Fatalf("line isn't valid JSON: %v\n%s", err, data)
+	}
+	if e.DebateID != "d1" || e.Source != "claude" || e.Content != "AGREE: ship it\nwith tests" || e.Type != "model" || e.Timestamp.IsZero() {
+		t.Errorf("event = %+v", e)
+	}
+
+	if err := w.Consensus("d1", "Agreement target: claude"); err != nil {
+		t.Fatalf("Consensus() failed: %v", err)
+	}
+	w.Close()
+
+	// Reopening appends rather than truncating
+	w, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	w.Message("d2", "user", "next", "user")
+	w.Close()
+
+	data, _ = os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), data)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil || e.Type != TypeConsensus {
+		t.Errorf("line 2 = %s, want a consensus event", lines[1])
+	}
+}
+
+func TestWriter_NilWritesNothing(t *testing.T) {
+	var w *Writer
+	if err := w.Message("d1", "claude", "hi", "model"); err != nil {
+		t.Errorf("Message() on nil writer = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Close() on nil writer = %v", err)
+	}
+}
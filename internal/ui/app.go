@@ -3,11 +3,18 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -21,8 +28,10 @@ import (
 	ctxloader "roundtable/internal/context"
 	"roundtable/internal/db"
 	"roundtable/internal/export"
+	"roundtable/internal/metrics"
 	"roundtable/internal/models"
 	"roundtable/internal/orchestrator"
+	"roundtable/internal/transcript"
 )
 
 // Package-level program reference for async message sending
@@ -75,14 +84,59 @@ func stripEscapeSequences(s string) string {
 
 // Message types for async model responses
 type modelResponseMsg struct {
+	seq       int // Round that produced this response; stale rounds are ignored
 	modelID   string
 	content   string
 	done      bool
+	started   bool // Model has begun working; no content yet
 	err       error
-	isTimeout bool // True if error was due to timeout
+	isTimeout bool          // True if error was due to timeout
+	usage     *models.Usage // Token/cost usage reported with done
+	stopped   bool          // Cut short because the others reached consensus
 }
 
-type allModelsDoneMsg struct{}
+type allModelsDoneMsg struct {
+	seq int
+}
+
+// tickMsg advances the streaming animation and elapsed timers
+type tickMsg time.Time
+
+// tickInterval is how often the models pane refreshes while a round is running
+const tickInterval = 400 * time.Millisecond
+
+// healthCheckMsg carries startup health check results keyed by model ID
+type healthCheckMsg struct {
+	results map[string]error
+}
+
+// modelsRefreshedMsg carries the result of /models refresh
+type modelsRefreshedMsg struct {
+	registry *models.Registry
+	result   models.RefreshResult
+}
+
+// healthCheckTimeout bounds the startup health check
+const healthCheckTimeout = 15 * time.Second
+
+const (
+	// inputCharLimit caps typed input
+	inputCharLimit = 8192
+	// pasteCharLimit caps input once text has been pasted into it
+	pasteCharLimit = 65536
+	// largePromptChars is the size above which Enter must be pressed twice
+	// to send, so a big paste isn't sent by accident
+	largePromptChars = inputCharLimit
+)
+
+// roundKind says what a dispatched round is for
+type roundKind int
+
+const (
+	roundDebate  roundKind = iota // All models answer; consensus and auto-discussion follow
+	roundDirect                   // One model answers an @mention; nothing follows
+	roundSummary                  // The moderator sums up the last round; the debate continues after
+)
 
 // Focus states
 type FocusPane int
@@ -99,6 +153,16 @@ type Model struct {
 	// Dimensions
 	width, height int
 	ready         bool
+	panes         paneWidths // Pane content widths, computed by updateLayout
+
+	// Side panes hidden to give the chat more room
+	hideContext, hideModels bool
+
+	// When set, the chat shows only this model's messages (plus user/system)
+	onlySource string
+
+	// Selected row of the models pane, reordered with shift+up/down
+	modelCursor int
 
 	// Config and dependencies
 	config   *config.Config
@@ -120,7 +184,9 @@ type Model struct {
 
 	// Orchestrator
 	orchestrator *orchestrator.Orchestrator
-	cancelDebate context.CancelFunc
+	cancelDebate context.CancelFunc // Cancels the in-flight round; nil when idle
+	roundSeq     int                // Incremented per dispatched round
+	round        roundKind          // What the round in flight is for
 
 	// Streaming state - tracks partial messages being built
 	// map[modelID]messageIndex - which message in debate.Messages is being streamed to
@@ -129,41 +195,122 @@ type Model struct {
 	// View mode state (normal, history browser, etc.)
 	viewMode     ViewMode
 	historyState *HistoryState
+
+	// Model health from the startup check (nil error = healthy)
+	health   map[string]error
+	healthCh <-chan map[string]error
+
+	// Config reload requested while models were responding
+	pendingReload bool
+
+	// True while the tick loop is scheduled
+	ticking bool
+
+	// When streaming responses were last checkpointed to the store
+	lastCheckpoint time.Time
+
+	// Problems found loading or validating the config, shown on startup
+	configErrors []error
+
+	// Debate in the most recently closed tab, for /reopen
+	lastClosedID string
+
+	// Images added with /attach, sent with the next prompt
+	attachments []models.Attachment
+
+	// Per-model response times and failures this session
+	modelStats map[string]*ModelStats
+
+	// Live JSON Lines copy of finalized messages; nil when not configured
+	transcript *transcript.Writer
+
+	// Model picked with /executor; empty means defaults.executor or the
+	// first model that can execute tools
+	executor string
+
+	// Prompt of the last round sent to all models, for /retry
+	lastRoundPrompt string
+
+	// Round started by accepting the offer to retry a failed round; it
+	// isn't offered again if that round fails too
+	autoRetrySeq int
+
+	// Pending yes/no question, e.g. before closing a tab
+	confirm *confirmation
+
+	// Inputs sent this session, recalled with up/down
+	inputHistory inputHistory
+
+	// Size of a large input waiting for a second Enter before it's sent
+	confirmSendChars int
+
+	// Ephemeral session: the store is in memory and nothing is saved to disk
+	noPersist bool
+
+	// Directory new debates belong to and models run in; startup only
+	// reopens this project's debates
+	project string
 }
 
-func New() Model {
-	// Load config
+// Options are command-line settings that override the config file
+type Options struct {
+	NoPersist bool   // Use an in-memory store so nothing is written to disk
+	DataDir   string // Directory holding debates.db; empty means the default
+	Project   string // Project directory; empty means the working directory
+
+	// Where finalized messages are also written as JSON lines; nil for none
+	Transcript *transcript.Writer
+}
+
+func New(opts Options) Model {
+	// Load config; problems are shown on a splash screen before the main UI
+	var configErrors []error
 	cfg, err := config.Load()
 	if err != nil {
-		cfg = &config.Config{}
+		configErrors = []error{fmt.Errorf("failed to load config: %w", err)}
+		cfg = config.Default()
+	} else {
+		configErrors = config.Validate(cfg)
 	}
 
-	// Open database
-	store, _ := db.Open()
+	theme, err := configTheme(cfg)
+	if err != nil {
+		configErrors = append(configErrors, err)
+	}
+	ApplyTheme(theme)
+
+	// Open database; an ephemeral session gets a store that lives in memory
+	noPersist := opts.NoPersist || cfg.Defaults.NoPersist
+	var store *db.Store
+	switch {
+	case noPersist:
+		store, err = db.OpenInMemory()
+	case opts.DataDir != "":
+		store, err = db.OpenDir(opts.DataDir)
+	default:
+		store, err = db.Open()
+	}
+	if err != nil {
+		slog.Error("opening the debate database failed; debates won't be saved", "err", err)
+	}
 
 	// Create model registry
+	project := projectDir(opts.Project)
+
 	registry := models.NewRegistry(cfg)
+	registry.SetWorkDir(project)
+	setModelColors(cfg, registry)
 
-	// Create orchestrator with timeout and retry settings from config
-	timeout := time.Duration(cfg.Defaults.ModelTimeout) * time.Second
-	if timeout == 0 {
-		timeout = 60 * time.Second
-	}
-	retryAttempts := cfg.Defaults.RetryAttempts
-	if retryAttempts == 0 {
-		retryAttempts = 3
-	}
-	retryDelay := time.Duration(cfg.Defaults.RetryDelay) * time.Millisecond
-	if retryDelay == 0 {
-		retryDelay = time.Second
-	}
-	orch := orchestrator.NewWithRetry(registry, timeout, retryAttempts, retryDelay)
+	orch := orchestrator.NewFromConfig(cfg, registry)
+
+	// Check models in the background so startup isn't blocked
+	healthCh := startHealthCheck(registry)
 
 	// Text input
 	ta := textarea.New()
 	ta.Placeholder = "Type here... (Enter to send)"
 	ta.Focus()
-	ta.CharLimit = 8192
+	ta.CharLimit = inputCharLimit
 	ta.SetHeight(3)
 	ta.ShowLineNumbers = false
 	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
@@ -172,22 +319,51 @@ func New() Model {
 
 	// Load existing debates from database or create initial debate
 	var debates []*Debate
+	var recovered string
 	if store != nil {
-		debates = loadDebatesFromStore(store)
+		recovered = recoverDrafts(store, staleDraftIntervals*autosaveInterval(cfg))
+		debates = loadDebatesFromStore(store, project)
 	}
 
 	// If no debates loaded, create a new one
 	if len(debates) == 0 {
 		debateID := uuid.New().String()[:8]
 		firstDebate := NewDebate(debateID, "New Debate")
+		firstDebate.ProjectPath = project
 		debates = []*Debate{firstDebate}
 
 		// Persist the new debate to database
 		if store != nil {
-			store.CreateDebate(debateID, "New Debate", "")
+			store.CreateDebate(debateID, "New Debate", project)
+		}
+		pinPreamble(firstDebate, cfg.PreambleFilePath(), store)
+		metrics.Default.DebateStarted()
+	}
+
+	historyState := NewHistoryState()
+	historyState.SetProject(project)
+
+	// Reopen the tab that was active when Roundtable last exited
+	activeTab := 0
+	if store != nil {
+		if id, err := store.GetState(db.StateActiveDebate); err == nil {
+			activeTab = debateIndex(debates, id)
 		}
 	}
 
+	if recovered != "" {
+		debates[activeTab].AddMessage("system", recovered)
+	}
+
+	for _, debate := range debates {
+		debate.Consensus = debateConsensus(debate, cfg)
+	}
+
+	viewMode := ViewNormal
+	if len(configErrors) > 0 {
+		viewMode = ViewConfigErrors
+	}
+
 	return Model{
 		config:        cfg,
 		store:         store,
@@ -195,17 +371,93 @@ func New() Model {
 		orchestrator:  orch,
 		input:         ta,
 		debates:       debates,
-		activeTab:     0,
+		activeTab:     activeTab,
 		focus:         FocusInput,
 		streamingMsgs: make(map[string]int),
-		viewMode:      ViewNormal,
-		historyState:  NewHistoryState(),
+		viewMode:      viewMode,
+		configErrors:  configErrors,
+		historyState:  historyState,
+		healthCh:      healthCh,
+		hideContext:   cfg.UI.HideContext,
+		hideModels:    cfg.UI.HideModels,
+		noPersist:     noPersist,
+		project:       project,
+		transcript:    opts.Transcript,
+	}
+}
+
+// projectDir resolves the --project flag to an absolute path, defaulting
+// to the working directory
+func projectDir(path string) string {
+	if path == "" {
+		path, _ = os.Getwd()
 	}
+	if abs, err := filepath.Abs(expandHome(path)); err == nil {
+		return abs
+	}
+	return path
+}
+
+// loadOlderAtTop loads the previous page of the active debate's messages
+// once the chat is scrolled to the top, keeping the view where it was
+func (m *Model) loadOlderAtTop() {
+	debate := m.activeDebate()
+	if debate == nil || !debate.HasOlder || !m.chatView.AtTop() {
+		return
+	}
+
+	lines := m.chatView.TotalLineCount()
+	offset := m.chatView.YOffset
+	if m.loadOlder(debate, messagePageSize) == 0 {
+		return
+	}
+	m.chatView.SetContent(debate.RenderMessages(m.chatView.Width, m.onlySource))
+	m.chatView.SetYOffset(offset + m.chatView.TotalLineCount() - lines)
+}
+
+// loadAllMessages loads every message of a debate still in the store, so
+// anything sent to models or exported sees the full history
+func (m *Model) loadAllMessages(debate *Debate) {
+	m.loadOlder(debate, 0)
+}
+
+// loadOlder prepends up to n older messages (all if n <= 0), shifting the
+// indexes of messages still streaming into the debate
+func (m *Model) loadOlder(debate *Debate, n int) int {
+	added := debate.LoadOlder(n)
+	if added > 0 && debate == m.activeDebate() {
+		for id, idx := range m.streamingMsgs {
+			m.streamingMsgs[id] = idx + added
+		}
+	}
+	return added
+}
+
+// debateIndex returns the index of the debate with the given ID, or 0 if it isn't loaded
+func debateIndex(debates []*Debate, id string) int {
+	for i, d := range debates {
+		if d.ID == id {
+			return i
+		}
+	}
+	return 0
+}
+
+// startHealthCheck checks all models in the background; the results arrive on
+// the returned channel
+func startHealthCheck(registry *models.Registry) <-chan map[string]error {
+	healthCh := make(chan map[string]error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		defer cancel()
+		healthCh <- registry.HealthCheckAll(ctx)
+	}()
+	return healthCh
 }
 
 // loadDebatesFromStore loads existing debates and their messages from the database
-func loadDebatesFromStore(store *db.Store) []*Debate {
-	dbDebates, err := store.ListDebates()
+func loadDebatesFromStore(store *db.Store, project string) []*Debate {
+	dbDebates, err := store.ListDebatesByProject(project)
 	if err != nil {
 		return nil
 	}
@@ -221,16 +473,13 @@ func loadDebatesFromStore(store *db.Store) []*Debate {
 		debate.ProjectPath = dbDebate.ProjectPath
 		debate.CreatedAt = dbDebate.CreatedAt
 
-		// Load messages for this debate
-		messages, err := store.GetMessages(dbDebate.ID)
-		if err == nil {
-			for _, msg := range messages {
-				debate.Messages = append(debate.Messages, DebateMessage{
-					Source:    msg.Source,
-					Content:   msg.Content,
-					Timestamp: msg.CreatedAt,
-				})
-			}
+		// Load the most recent messages; older ones load on scroll
+		loadMessages(store, debate)
+
+		loadUsage(store, debate)
+
+		if tags, err := store.GetTags(dbDebate.ID); err == nil {
+			debate.Tags = tags
 		}
 
 		// Load context files for this debate
@@ -249,20 +498,212 @@ func loadDebatesFromStore(store *db.Store) []*Debate {
 
 // saveMessage persists a message to the database
 func (m *Model) saveMessage(debateID, source, content, msgType string) {
+	m.writeTranscript(debateID, source, content, msgType)
+	if m.store != nil {
+		round := 0
+		for _, d := range m.debates {
+			if d.ID == debateID {
+				round = d.Round
+				break
+			}
+		}
+		m.store.AddRoundMessage(debateID, source, content, msgType, round)
+	}
+}
+
+// writeTranscript appends a finalized message to the transcript, if any
+func (m *Model) writeTranscript(debateID, source, content, msgType string) {
+	if err := m.transcript.Message(debateID, source, content, msgType); err != nil {
+		slog.Warn("transcript write failed", "err", err)
+	}
+}
+
+// saveStreamed saves a finished streamed message, turning its draft into a
+// regular message if it was checkpointed
+func (m *Model) saveStreamed(debate *Debate, idx int, msgType string) {
+	msg := &debate.Messages[idx]
+	if m.store != nil && msg.draftID != 0 {
+		if err := m.store.FinalizeDraft(msg.draftID, msg.Content, msgType); err == nil {
+			msg.draftID = 0
+			m.writeTranscript(debate.ID, msg.Source, msg.Content, msgType)
+			return
+		}
+	}
+	m.saveMessage(debate.ID, msg.Source, msg.Content, msgType)
+}
+
+// checkpointStreaming saves the responses still streaming as drafts, so a
+// crash doesn't lose them. Drafts are saved even if unchanged, marking them
+// live so another session's recoverDrafts leaves them alone.
+func (m *Model) checkpointStreaming() {
+	m.lastCheckpoint = time.Now()
+	debate := m.activeDebate()
+	if m.store == nil || debate == nil {
+		return
+	}
+	for _, idx := range m.streamingMsgs {
+		if idx >= len(debate.Messages) {
+			continue
+		}
+		msg := &debate.Messages[idx]
+		if msg.draftID == 0 && msg.Content == "" {
+			continue
+		}
+		id, err := m.store.SaveDraft(msg.draftID, debate.ID, msg.Source, msg.Content, debate.Round)
+		if err != nil {
+			slog.Warn("checkpointing a streaming response failed", "model", msg.Source, "err", err)
+			continue
+		}
+		msg.draftID = id
+	}
+}
+
+// autosaveInterval is how often streaming responses are checkpointed
+func (m *Model) autosaveInterval() time.Duration {
+	return autosaveInterval(m.config)
+}
+
+func autosaveInterval(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.Defaults.AutosaveInterval <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(cfg.Defaults.AutosaveInterval) * time.Second
+}
+
+// staleDraftIntervals is how many autosave intervals a draft goes without a
+// checkpoint before it's taken as left by a session that ended
+const staleDraftIntervals = 3
+
+// interruptedNote marks a draft recovered after a crash
+const interruptedNote = "\n\n[Interrupted: Roundtable exited before this response finished]"
+
+// recoverDrafts turns drafts left by a crash into regular messages, marked
+// as interrupted, and describes them for the user. Drafts checkpointed in
+// the last idle may belong to another running session and are left alone.
+// Returns "" if there were none.
+func recoverDrafts(store *db.Store, idle time.Duration) string {
+	drafts, err := store.GetStaleDrafts(idle)
+	if err != nil || len(drafts) == 0 {
+		return ""
+	}
+	var recovered []string
+	for _, d := range drafts {
+		msgType := "model"
+		if d.Source == moderatorSource {
+			msgType = moderatorSource
+		}
+		if err := store.FinalizeDraft(d.ID, d.Content+interruptedNote, msgType); err != nil {
+			continue
+		}
+		name := d.DebateID
+		if debate, err := store.GetDebate(d.DebateID); err == nil {
+			name = debate.Name
+		}
+		recovered = append(recovered, fmt.Sprintf("%s in %q", formatSource(d.Source), name))
+	}
+	if len(recovered) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Recovered %d partial response(s) from a session that ended unexpectedly: %s.", len(recovered), strings.Join(recovered, ", "))
+}
+
+// saveUsage persists a model response's token/cost usage to the database
+func (m *Model) saveUsage(debateID, modelID string, usage models.Usage) {
 	if m.store != nil {
-		m.store.AddMessage(debateID, source, content, msgType)
+		m.store.AddUsage(debateID, modelID, usage.PromptTokens, usage.CompletionTokens, usage.Cost)
+	}
+}
+
+// pinPreamble adds the preamble file at path, if any, to a new debate's
+// context and saves it. It's read afresh for each debate, so edits to the
+// file apply from the next debate on.
+func pinPreamble(debate *Debate, path string, store *db.Store) {
+	if path == "" {
+		return
+	}
+	content, err := ctxloader.LoadContext(path)
+	if err != nil {
+		debate.AddMessage("system", fmt.Sprintf("Failed to load preamble file: %v", err))
+		return
+	}
+	debate.ContextFiles[path] = content
+	if store != nil {
+		store.UpdateContextFile(debate.ID, path, content)
+	}
+}
+
+// preambleFile returns the configured preamble file path; empty means none
+func (m *Model) preambleFile() string {
+	if m.config == nil {
+		return ""
 	}
+	return m.config.PreambleFilePath()
 }
 
-// saveContextFile persists a context file to the database
+// saveContextFile persists a context file to the database, replacing any
+// earlier version
 func (m *Model) saveContextFile(debateID, path, content string) {
 	if m.store != nil {
-		m.store.AddContextFile(debateID, path, content)
+		m.store.UpdateContextFile(debateID, path, content)
+	}
+}
+
+// refreshContext re-reads debate's context files from disk, saving the ones
+// that changed, and describes the result. Files that can't be read keep
+// their last loaded version.
+func (m *Model) refreshContext(debate *Debate) string {
+	paths := make([]string, 0, len(debate.ContextFiles))
+	for path := range debate.ContextFiles {
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return "No context files loaded"
+	}
+	sort.Strings(paths)
+
+	var updated, missing, failed []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			missing = append(missing, path)
+			continue
+		}
+		content, err := ctxloader.LoadContext(path)
+		switch {
+		case err != nil:
+			failed = append(failed, fmt.Sprintf("%s (%v)", path, err))
+		case content != debate.ContextFiles[path]:
+			debate.ContextFiles[path] = content
+			m.saveContextFile(debate.ID, path, content)
+			updated = append(updated, path)
+		}
+	}
+
+	unchanged := len(paths) - len(updated) - len(missing) - len(failed)
+	lines := []string{fmt.Sprintf("Refreshed context: %d updated, %d unchanged.", len(updated), unchanged)}
+	if len(updated) > 0 {
+		lines = append(lines, "Updated: "+strings.Join(updated, ", "))
+	}
+	if len(missing) > 0 {
+		lines = append(lines, "Missing, keeping the last version: "+strings.Join(missing, ", "))
 	}
+	if len(failed) > 0 {
+		lines = append(lines, "Couldn't read, keeping the last version: "+strings.Join(failed, ", "))
+	}
+	return strings.Join(lines, "\n")
 }
 
 func (m Model) Init() tea.Cmd {
-	return textarea.Blink
+	return tea.Batch(textarea.Blink, waitForHealthCheck(m.healthCh))
+}
+
+// waitForHealthCheck delivers the background health check results
+func waitForHealthCheck(ch <-chan map[string]error) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return healthCheckMsg{results: <-ch}
+	}
 }
 
 func (m *Model) activeDebate() *Debate {
@@ -280,24 +721,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateHistoryView(msg)
 	}
 
+	// The config error splash only intercepts keys; other messages
+	// (window size, health checks) are handled as usual
+	if key, ok := msg.(tea.KeyMsg); ok && m.viewMode == ViewConfigErrors {
+		return m.updateConfigErrors(key)
+	}
+
+	// A pending yes/no question takes the next keypress
+	if key, ok := msg.(tea.KeyMsg); ok && m.confirm != nil {
+		return m.updateConfirm(key)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.updateInputLimits(msg)
 		switch msg.String() {
 		case "ctrl+c", "ctrl+q":
-			// Cancel any ongoing model requests before quitting
+			// Cancel any ongoing model requests before quitting, keeping
+			// what they've streamed so far
 			if m.cancelDebate != nil {
 				m.cancelDebate()
 			}
+			m.flushStreaming()
 			return m, tea.Quit
 
 		case "alt+h":
 			// Open history browser
-			m.viewMode = ViewHistory
-			if m.historyState == nil {
-				m.historyState = NewHistoryState()
-			}
-			m.historyState.SetMaxHeight(m.height)
-			m.historyState.LoadDebates(m.store)
+			m.openHistory("")
 			return m, nil
 
 		case "shift+enter", "alt+enter":
@@ -311,6 +761,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if input == "" {
 				return m, nil
 			}
+			// A large prompt needs a second Enter
+			if n := utf8.RuneCountInString(input); n > largePromptChars && m.confirmSendChars != n {
+				m.confirmSendChars = n
+				return m, nil
+			}
+			m.confirmSendChars = 0
+			m.inputHistory.Push(input)
 
 			// Check if this is a slash command
 			if cmd := commands.Parse(input); cmd != nil {
@@ -318,6 +775,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m.handleCommand(cmd)
 			}
 
+			// Read-only debates keep the input until they're reactivated
+			if debate := m.activeDebate(); debate != nil && debate.ReadOnly {
+				debate.AddMessage("system", readOnlyBanner)
+				m.updateChatView()
+				return m, nil
+			}
+
+			// "@model question" asks just that model
+			if modelID, prompt, ok := mentionTarget(input); ok {
+				m.input.Reset()
+				cmd := m.askModel(input, modelID, prompt)
+				return m, cmd
+			}
+
 			// Not a command - send as prompt to models
 			if m.activeDebate() != nil {
 				debate := m.activeDebate()
@@ -335,7 +806,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.streamingMsgs = make(map[string]int)
 				m.updateChatView()
 				// Dispatch to all models in parallel
-				return m, m.dispatchToModels(input)
+				cmd := m.dispatchToModels(input)
+				return m, cmd
 			}
 			return m, nil
 
@@ -343,15 +815,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showHelp = !m.showHelp
 			return m, nil
 
+		case "ctrl+x":
+			// Cancel the current round without quitting
+			if m.roundInFlight() {
+				cmd := m.cancelRound()
+				return m, cmd
+			}
+			return m, nil
+
 		case "esc":
 			if m.showHelp {
 				m.showHelp = false
 				return m, nil
 			}
+			if m.roundInFlight() {
+				cmd := m.cancelRound()
+				return m, cmd
+			}
 			m.focus = FocusInput
 			m.input.Focus()
 			return m, nil
 
+		case "ctrl+b":
+			m.togglePane(FocusContext)
+			return m, nil
+
+		case "ctrl+g":
+			m.togglePane(FocusModels)
+			return m, nil
+
 		case "tab":
 			m.cycleFocus(1)
 			return m, nil
@@ -364,16 +856,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "up", "k":
 			if m.focus == FocusChat {
 				m.chatView.LineUp(1)
+				m.loadOlderAtTop()
 				return m, nil
 			}
+			if m.focus == FocusModels {
+				m.modelCursor = max(0, m.modelCursor-1)
+				return m, nil
+			}
+			// Up on the first line recalls the previous input
+			if msg.Type == tea.KeyUp && m.focus == FocusInput && m.input.Line() == 0 {
+				if prev, ok := m.inputHistory.Prev(m.input.Value()); ok {
+					m.input.CharLimit = pasteCharLimit
+					m.input.SetValue(prev)
+					return m, nil
+				}
+			}
 		case "down", "j":
 			if m.focus == FocusChat {
 				m.chatView.LineDown(1)
 				return m, nil
 			}
+			if m.focus == FocusModels {
+				m.modelCursor = max(0, min(m.registry.Count()-1, m.modelCursor+1))
+				return m, nil
+			}
+			// Down on the last line moves forward through recalled inputs
+			if msg.Type == tea.KeyDown && m.focus == FocusInput && m.input.Line() == strings.Count(m.input.Value(), "\n") {
+				if next, ok := m.inputHistory.Next(); ok {
+					m.input.CharLimit = pasteCharLimit
+					m.input.SetValue(next)
+					return m, nil
+				}
+			}
+		case "shift+up":
+			if m.focus == FocusModels {
+				m.moveSelectedModel(-1)
+				return m, nil
+			}
+		case "shift+down":
+			if m.focus == FocusModels {
+				m.moveSelectedModel(1)
+				return m, nil
+			}
 		case "pgup", "ctrl+u":
 			if m.focus == FocusChat {
 				m.chatView.HalfViewUp()
+				m.loadOlderAtTop()
 				return m, nil
 			}
 		case "pgdown", "ctrl+d":
@@ -384,6 +912,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "home", "g":
 			if m.focus == FocusChat {
 				m.chatView.GotoTop()
+				m.loadOlderAtTop()
 				return m, nil
 			}
 		case "end", "G":
@@ -425,7 +954,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "alt+w":
-			m.closeTab(m.activeTab)
+			m.requestCloseTab(m.activeTab)
+			return m, nil
+
+		case "alt+W":
+			m.reopenLastClosed()
 			return m, nil
 		}
 
@@ -435,12 +968,64 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateLayout()
 		m.ready = true
 
+	case tickMsg:
+		if !m.roundInFlight() {
+			m.ticking = false
+			return m, nil
+		}
+		if debate := m.activeDebate(); debate != nil {
+			debate.TickAnimation()
+		}
+		if time.Since(m.lastCheckpoint) >= m.autosaveInterval() {
+			m.checkpointStreaming()
+		}
+		return m, tick()
+
+	case healthCheckMsg:
+		m.health = msg.results
+		var problems []string
+		for _, model := range m.registry.All() {
+			info := model.Info()
+			if err := m.health[info.ID]; err != nil {
+				problems = append(problems, fmt.Sprintf("  %s: %v", info.Name, err))
+			}
+		}
+		if debate := m.activeDebate(); debate != nil && len(problems) > 0 {
+			debate.AddMessage("system", "Some models failed their health check:\n"+strings.Join(problems, "\n"))
+			m.updateChatView()
+		}
+		return m, nil
+
+	case modelsRefreshedMsg:
+		if msg.registry != m.registry {
+			// The config was reloaded while the check ran
+			return m, nil
+		}
+		m.health = msg.result.Health
+		if debate := m.activeDebate(); debate != nil {
+			debate.AddMessage("system", refreshSummary(m.registry, msg.result))
+			m.updateChatView()
+		}
+		return m, nil
+
 	case modelResponseMsg:
+		if msg.seq != m.roundSeq {
+			// Late response from a cancelled round
+			return m, nil
+		}
 		debate := m.activeDebate()
 		if debate == nil {
 			return m, nil
 		}
 
+		if msg.started {
+			// Start the elapsed timer before any content arrives
+			debate.UpdateModelStatus(msg.modelID, models.StatusResponding)
+			m.updateChatView()
+			return m, nil
+		}
+		start, started := debate.ModelStartTime[msg.modelID]
+
 		if msg.err != nil {
 			// Add error message with proper error styling
 			errContent := msg.err.Error()
@@ -454,6 +1039,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				debate.AddErrorMessage(msg.modelID, errContent, false)
 			}
 
+			slog.Warn("model failed", "model", msg.modelID, "timeout", msg.isTimeout, "err", msg.err)
+
 			// Persist error to database
 			m.saveMessage(debate.ID, msg.modelID, "[ERROR] "+errContent, "system")
 		} else if msg.content != "" {
@@ -462,38 +1049,140 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Append to existing streaming message
 				debate.Messages[idx].Content += msg.content
 			} else {
-				// Start a new message
-				debate.AddMessage(msg.modelID, msg.content)
+				// Start a new message; a summary is the moderator's, not the model's
+				source := msg.modelID
+				if m.round == roundSummary {
+					source = moderatorSource
+				}
+				debate.AddMessage(source, msg.content)
 				m.streamingMsgs[msg.modelID] = len(debate.Messages) - 1
 			}
-			debate.ModelStatus[msg.modelID] = models.StatusResponding
+			debate.UpdateModelStatus(msg.modelID, models.StatusResponding)
 		}
 
 		if msg.done {
 			// Finalize the message - save complete content to database
 			if idx, ok := m.streamingMsgs[msg.modelID]; ok && idx < len(debate.Messages) {
 				finalContent := debate.Messages[idx].Content
-				m.saveMessage(debate.ID, msg.modelID, finalContent, "model")
+				source := debate.Messages[idx].Source
+				if source == moderatorSource {
+					m.saveStreamed(debate, idx, moderatorSource)
+				} else if msg.stopped {
+					debate.Messages[idx].MsgType = db.MsgTypeStopped
+					m.saveStreamed(debate, idx, db.MsgTypeStopped)
+				} else {
+					quality := consensus.AssessQuality(debate.LastUserPrompt(), finalContent)
+					debate.Messages[idx].QualityWarning = quality.Warning()
+					m.saveStreamed(debate, idx, "model")
+					if note := commandsNote(source, shellCommands(source, finalContent)); note != "" {
+						debate.AddMessage("system", note)
+					}
+				}
 				delete(m.streamingMsgs, msg.modelID)
 			}
-			debate.ModelStatus[msg.modelID] = models.StatusIdle
+			debate.UpdateModelStatus(msg.modelID, models.StatusIdle)
+
+			if !msg.stopped {
+				var latency time.Duration
+				if started {
+					latency = time.Since(start)
+				}
+				outcome := metrics.OutcomeSuccess
+				if msg.isTimeout {
+					outcome = metrics.OutcomeTimeout
+				} else if msg.err != nil {
+					outcome = metrics.OutcomeError
+				}
+				m.recordResponse(msg.modelID, outcome, latency)
+			}
+
+			if msg.usage != nil {
+				debate.AddUsage(msg.modelID, *msg.usage)
+				m.saveUsage(debate.ID, msg.modelID, *msg.usage)
+			}
+
+			if msg.stopped {
+				note := fmt.Sprintf("Stopped %s: the other models reached consensus.", formatSource(msg.modelID))
+				debate.AddMessage("system", note)
+				m.saveMessage(debate.ID, "system", note, "system")
+			}
 		}
 
 		m.updateChatView()
 		return m, nil
 
 	case allModelsDoneMsg:
+		if msg.seq != m.roundSeq {
+			return m, nil
+		}
+		// The round is over; release its context
+		if m.cancelDebate != nil {
+			m.cancelDebate()
+			m.cancelDebate = nil
+		}
+		// Save any response whose stream ended without a done signal
+		m.flushStreaming()
+
+		// Apply a config reload deferred while models were responding
+		var reloadCmd tea.Cmd
+		if m.pendingReload {
+			reloadCmd = m.reloadConfig()
+		}
+
+		// A question to one model isn't a debate round
+		if m.round == roundDirect {
+			m.round = roundDebate
+			if debate := m.activeDebate(); debate != nil {
+				debate.AwaitingUser = true
+			}
+			return m, reloadCmd
+		}
+
+		// Nothing to discuss if every model failed
+		if debate := m.activeDebate(); debate != nil && m.round == roundDebate && allFailed(debate) {
+			m.handleFailedRound(debate, msg.seq)
+			return m, reloadCmd
+		}
+
+		// Have the moderator sum up the round before the debate moves on
+		if m.round == roundDebate && summaryDue(m.activeDebate(), m.autoSummarize()) {
+			if cmd := m.dispatchSummary(); cmd != nil {
+				return m, tea.Batch(reloadCmd, cmd)
+			}
+		}
+		m.round = roundDebate
+
 		debate := m.activeDebate()
 		if debate != nil {
 			// Check for consensus among model responses
-			consensusResult := m.checkDebateConsensus(debate)
+			consensusResult := m.updateConsensus(debate)
+			metrics.Default.ConsensusChecked(consensusResult.HasConsensus)
 
+			// Ask for positions once enough rounds pass without consensus
+			lastWasPoll := debate.AutoPolled
+			debate.AutoPolled = false
 			if consensusResult.HasConsensus {
-				// Consensus reached - mark debate as resolved
-				systemMsg := fmt.Sprintf("CONSENSUS REACHED: %d models agree (no objections). Ready for execution.", consensusResult.AgreeCount)
-
+				debate.RoundsWithoutConsensus = 0
+			} else if !lastWasPoll {
+				debate.RoundsWithoutConsensus++
+			}
+			if !consensusResult.HasConsensus && !debate.Paused && autoConsensusDue(m.autoConsensusAfter(), debate.RoundsWithoutConsensus, lastWasPoll) {
+				if cmd := m.autoPollConsensus(debate); cmd != nil {
+					return m, tea.Batch(reloadCmd, cmd)
+				}
+			}
+
+			if consensusResult.HasConsensus {
+				// Consensus reached - mark debate as resolved
+				systemMsg := fmt.Sprintf("CONSENSUS REACHED: %d models agree (no objections). Ready for execution.", consensusResult.AgreeCount)
+
 				// Build consensus description for storage
 				consensusText := fmt.Sprintf("Agreement target: %s", consensusResult.AgreementTarget)
+				if split := consensusResult.DescribeSplit(formatSource); split != "" {
+					// Models agree, but not on the same proposal
+					systemMsg += "\nNote: " + split + "."
+					consensusText = "Split agreement: " + strings.TrimPrefix(split, "agreement is split across proposals: ")
+				}
 				if len(consensusResult.Additions) > 0 {
 					consensusText += fmt.Sprintf(" with %d additions", len(consensusResult.Additions))
 				}
@@ -502,6 +1191,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.store != nil {
 					m.store.UpdateDebateStatus(debate.ID, "resolved", consensusText)
 				}
+				if err := m.transcript.Consensus(debate.ID, consensusText); err != nil {
+					slog.Warn("transcript write failed", "err", err)
+				}
 
 				debate.AwaitingUser = true
 				debate.AddMessage("system", systemMsg)
@@ -519,12 +1211,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if consensusResult.ObjectCount > 0 {
 						roundMsg += fmt.Sprintf(" (%d objection(s) raised)", consensusResult.ObjectCount)
 					}
+					if consensusResult.InsufficientParticipation {
+						roundMsg += fmt.Sprintf(" (%s)", consensusResult.ParticipationMessage())
+					}
 					debate.AddMessage("system", roundMsg)
 					m.saveMessage(debate.ID, "system", roundMsg, "system")
 					m.updateChatView()
 
 					// Dispatch to models for discussion
-					return m, m.dispatchToModels(discussionPrompt)
+					cmd := m.dispatchToModels(discussionPrompt)
+					return m, tea.Batch(reloadCmd, cmd)
 				}
 			} else {
 				// Max rounds reached or paused - await user input
@@ -542,15 +1238,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					systemMsg = "All models have responded. Any objections or additions?"
 					if consensusResult.ObjectCount > 0 {
 						systemMsg = fmt.Sprintf("All models have responded. %d objection(s) raised - consensus not reached.", consensusResult.ObjectCount)
+					} else if consensusResult.InsufficientParticipation {
+						systemMsg = fmt.Sprintf("All models have responded, but consensus can't be declared: %s.", consensusResult.ParticipationMessage())
 					}
 				}
 
+				// Help the user decide whether another round is worthwhile
+				if hint := m.consensusGapHint(debate); hint != "" {
+					systemMsg += "\nHint: " + hint
+				}
+
 				debate.AddMessage("system", systemMsg)
 				m.saveMessage(debate.ID, "system", systemMsg, "system")
 				m.updateChatView()
 			}
 		}
-		return m, nil
+		return m, reloadCmd
 	}
 
 	// Update focused component
@@ -562,13 +1265,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.chatView, cmd = m.chatView.Update(msg)
 		cmds = append(cmds, cmd)
+		if _, ok := msg.(tea.MouseMsg); ok {
+			m.loadOlderAtTop()
+		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m *Model) cycleFocus(dir int) {
-	panes := []FocusPane{FocusInput, FocusChat, FocusContext, FocusModels}
+	panes := []FocusPane{FocusInput, FocusChat}
+	if !m.hideContext {
+		panes = append(panes, FocusContext)
+	}
+	if !m.hideModels {
+		panes = append(panes, FocusModels)
+	}
 	current := 0
 	for i, p := range panes {
 		if p == m.focus {
@@ -585,17 +1297,87 @@ func (m *Model) cycleFocus(dir int) {
 	}
 }
 
+// togglePane shows or hides the context or models pane, giving the chat the
+// freed space, and saves the choice to the config file
+func (m *Model) togglePane(pane FocusPane) {
+	switch pane {
+	case FocusContext:
+		m.hideContext = !m.hideContext
+	case FocusModels:
+		m.hideModels = !m.hideModels
+	default:
+		return
+	}
+
+	// Don't leave focus on a pane that's no longer visible
+	if (m.focus == FocusContext && m.hideContext) || (m.focus == FocusModels && m.hideModels) {
+		m.focus = FocusInput
+		m.input.Focus()
+	}
+
+	if m.ready {
+		m.updateLayout()
+	}
+
+	if m.config == nil || m.noPersist {
+		return
+	}
+	m.config.UI.HideContext = m.hideContext
+	m.config.UI.HideModels = m.hideModels
+	if err := config.SaveUI(m.config); err != nil {
+		if debate := m.activeDebate(); debate != nil {
+			debate.AddMessage("system", fmt.Sprintf("Couldn't save pane layout: %v", err))
+			m.updateChatView()
+		}
+	}
+}
+
+// moveSelectedModel moves the model under the cursor delta places, keeping
+// it selected, and saves the new order to the config file
+func (m *Model) moveSelectedModel(delta int) {
+	ids := m.registry.Enabled()
+	if m.modelCursor >= len(ids) || !m.registry.MoveModel(ids[m.modelCursor], delta) {
+		return
+	}
+	m.modelCursor = slices.Index(m.registry.Enabled(), ids[m.modelCursor])
+
+	if m.config == nil || m.noPersist {
+		return
+	}
+	m.config.UI.ModelOrder = slices.Clone(m.registry.Enabled())
+	if err := config.SaveUI(m.config); err != nil {
+		if debate := m.activeDebate(); debate != nil {
+			debate.AddMessage("system", fmt.Sprintf("Couldn't save model order: %v", err))
+			m.updateChatView()
+		}
+	}
+}
+
+// openHistory shows the history browser, only debates tagged tag if it's set
+func (m *Model) openHistory(tag string) {
+	m.viewMode = ViewHistory
+	if m.historyState == nil {
+		m.historyState = NewHistoryState()
+		m.historyState.SetProject(m.project)
+	}
+	m.historyState.SetMaxHeight(m.height)
+	m.historyState.LoadDebates(m.store, tag)
+}
+
 func (m *Model) createTab() {
 	debateID := uuid.New().String()[:8]
 	debateName := fmt.Sprintf("Debate %d", len(m.debates)+1)
 	debate := NewDebate(debateID, debateName)
+	debate.ProjectPath = m.project
 	m.debates = append(m.debates, debate)
-	m.activeTab = len(m.debates) - 1
 
 	// Persist new debate to database
 	if m.store != nil {
-		m.store.CreateDebate(debateID, debateName, "")
+		m.store.CreateDebate(debateID, debateName, m.project)
 	}
+	pinPreamble(debate, m.preambleFile(), m.store)
+	metrics.Default.DebateStarted()
+	m.setActiveTab(len(m.debates) - 1)
 
 	m.updateChatView()
 }
@@ -609,34 +1391,155 @@ func (m *Model) closeTab(idx int) {
 	closedDebate := m.debates[idx]
 	if m.store != nil && closedDebate != nil {
 		m.store.UpdateDebateStatus(closedDebate.ID, "abandoned", "")
+		m.lastClosedID = closedDebate.ID
 	}
 
 	m.debates = append(m.debates[:idx], m.debates[idx+1:]...)
 
-	if m.activeTab >= len(m.debates) {
-		m.activeTab = len(m.debates) - 1
+	m.setActiveTab(min(m.activeTab, len(m.debates)-1))
+	m.updateChatView()
+}
+
+// reopenLastClosed marks the most recently closed debate active and opens it
+// in a new tab, or switches to it if it's already open again
+func (m *Model) reopenLastClosed() {
+	id := m.lastClosedID
+	if id == "" {
+		if debate := m.activeDebate(); debate != nil {
+			debate.AddMessage("system", "No closed debate to reopen.")
+			m.updateChatView()
+		}
+		return
+	}
+
+	for i, d := range m.debates {
+		if d.ID == id {
+			m.lastClosedID = ""
+			m.switchTab(i)
+			return
+		}
+	}
+
+	debate, err := m.reactivate(id)
+	if err != nil {
+		if active := m.activeDebate(); active != nil {
+			active.AddMessage("system", fmt.Sprintf("Reopen failed: %v", err))
+			m.updateChatView()
+		}
+		return
+	}
+	m.lastClosedID = ""
+	m.updateConsensus(debate)
+	m.debates = append(m.debates, debate)
+	m.setActiveTab(len(m.debates) - 1)
+	debate.AddMessage("system", fmt.Sprintf("Reopened debate %q.", debate.Name))
+	m.updateChatView()
+}
+
+// readOnlyBanner explains why a read-only debate refuses prompts
+const readOnlyBanner = "Read-only: this debate was closed or paused. Type /resume to reactivate it."
+
+// reactivateReadOnly marks a debate opened read-only active again so it
+// takes prompts
+func (m *Model) reactivateReadOnly(debate *Debate) {
+	if m.store != nil {
+		if err := m.store.UpdateDebateStatus(debate.ID, "active", ""); err != nil {
+			debate.AddMessage("system", fmt.Sprintf("Reactivate failed: %v", err))
+			m.updateChatView()
+			return
+		}
 	}
+	debate.ReadOnly = false
+	debate.Paused = false
+	debate.AddMessage("system", "Debate reactivated. Send a message to continue it.")
 	m.updateChatView()
 }
 
+// reactivate flips a closed debate back to active and loads it from the store
+func (m *Model) reactivate(id string) (*Debate, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if err := m.store.ReactivateDebate(id); err != nil {
+		return nil, err
+	}
+	return ResumeDebate(m.store, id)
+}
+
+// forkDebate copies debate's first index stored messages into a new debate
+// and opens it. An index of zero forks at the latest consensus, or copies
+// everything if there's none. It returns the new debate and the index used.
+func (m *Model) forkDebate(debate *Debate, index int) (*Debate, int, error) {
+	if m.store == nil {
+		return nil, 0, fmt.Errorf("database not available")
+	}
+	messages, err := m.store.GetMessages(debate.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	var stored []db.Message
+	for _, msg := range messages {
+		if msg.MsgType != db.MsgTypeDraft {
+			stored = append(stored, msg)
+		}
+	}
+	if len(stored) == 0 {
+		return nil, 0, fmt.Errorf("debate has no saved messages")
+	}
+
+	if index == 0 {
+		index = len(stored)
+		for i := len(stored) - 1; i >= 0; i-- {
+			if stored[i].Source == "system" && strings.HasPrefix(stored[i].Content, "CONSENSUS REACHED") {
+				index = i + 1
+				break
+			}
+		}
+	} else if index > len(stored) {
+		return nil, 0, fmt.Errorf("debate has %d saved messages", len(stored))
+	}
+
+	id, err := m.store.ForkDebate(debate.ID, "Fork of "+debate.Name, index)
+	if err != nil {
+		return nil, 0, err
+	}
+	forked, err := ResumeDebate(m.store, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return forked, index, nil
+}
+
 func (m *Model) switchTab(idx int) {
 	if idx >= 0 && idx < len(m.debates) {
-		m.activeTab = idx
+		m.setActiveTab(idx)
 		m.updateChatView()
 	}
 }
 
+// setActiveTab switches tabs and remembers the debate so the next launch
+// opens on it
+func (m *Model) setActiveTab(idx int) {
+	m.activeTab = idx
+	if debate := m.activeDebate(); debate != nil && m.store != nil {
+		m.store.SetState(db.StateActiveDebate, debate.ID)
+	}
+}
+
 func (m *Model) updateLayout() {
-	contextWidth := 25
-	modelsWidth := 15
-	chatWidth := m.width - contextWidth - modelsWidth - 6
+	var contextPct, modelsPct int
+	if m.config != nil {
+		contextPct = m.config.UI.ContextWidthPct
+		modelsPct = m.config.UI.ModelsWidthPct
+	}
+	m.panes = computeLayout(m.width, contextPct, modelsPct, !m.hideContext, !m.hideModels)
 	contentHeight := m.height - 10
 
-	m.chatView = viewport.New(chatWidth, contentHeight)
+	m.chatView = viewport.New(m.panes.Chat, contentHeight)
 	m.chatView.Style = lipgloss.NewStyle()
 	m.chatView.MouseWheelEnabled = true
 
-	m.contextView = viewport.New(contextWidth-2, contentHeight)
+	m.contextView = viewport.New(max(m.panes.Context-2, 0), contentHeight)
 	m.contextView.Style = lipgloss.NewStyle()
 
 	m.input.SetWidth(m.width - 4)
@@ -650,7 +1553,7 @@ func (m *Model) updateChatView() {
 		return
 	}
 
-	content := debate.RenderMessages(m.chatView.Width)
+	content := debate.RenderMessages(m.chatView.Width, m.onlySource)
 	m.chatView.SetContent(content)
 	m.chatView.GotoBottom()
 }
@@ -668,18 +1571,27 @@ func (m Model) View() string {
 		return m.historyState.Render(m.width, m.height)
 	}
 
+	if m.viewMode == ViewConfigErrors {
+		return m.renderConfigErrors()
+	}
+
 	// Title bar
 	title := m.renderTitle()
 
 	// Tab bar
 	tabBar := m.renderTabBar()
 
-	// Main content (3 panes)
-	contextPane := m.renderContextPane()
-	chatPane := m.renderChatPane()
-	modelsPane := m.renderModelsPane()
+	// Main content (up to 3 panes)
+	var panes []string
+	if !m.hideContext {
+		panes = append(panes, m.renderContextPane())
+	}
+	panes = append(panes, m.renderChatPane())
+	if !m.hideModels {
+		panes = append(panes, m.renderModelsPane())
+	}
 
-	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, contextPane, chatPane, modelsPane)
+	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, panes...)
 
 	// Status bar
 	statusBar := m.renderStatusBar()
@@ -699,9 +1611,15 @@ func (m Model) renderTitle() string {
 		name = debate.Name
 	}
 	middle := DimStyle.Render(fmt.Sprintf(" %s ", name))
+	if debate != nil && len(debate.Tags) > 0 {
+		middle += SystemStyle.Render(formatTags(debate.Tags) + " ")
+	}
 
 	modelCount := fmt.Sprintf("%d models", m.registry.Count())
 	right := DimStyle.Render(modelCount)
+	if m.noPersist {
+		right = StatusWarn.Render("[not saved] ") + right
+	}
 
 	padding := m.width - lipgloss.Width(left) - lipgloss.Width(middle) - lipgloss.Width(right) - 2
 	if padding < 0 {
@@ -748,17 +1666,89 @@ func (m Model) renderContextPane() string {
 	content.WriteString("\n\n")
 
 	if debate != nil && len(debate.ContextFiles) > 0 {
-		for path := range debate.ContextFiles {
-			content.WriteString(DimStyle.Render("* " + path))
+		files, total, usage := contextPaneSummary(debate, contextBudget, m.preambleFile())
+		for _, line := range files {
+			content.WriteString(DimStyle.Render(line))
 			content.WriteString("\n")
 		}
+		totalStyle := DimStyle
+		switch usage {
+		case contextNearBudget:
+			totalStyle = StatusWarn
+		case contextOverBudget:
+			totalStyle = StatusCrit
+		}
+		content.WriteString("\n")
+		content.WriteString(totalStyle.Render(total))
 	} else {
 		content.WriteString(DimStyle.Render("No files loaded"))
 		content.WriteString("\n")
 		content.WriteString(DimStyle.Render("/context add <path>"))
 	}
 
-	return style.Width(25).Height(m.height - 10).Render(content.String())
+	return style.Width(m.panes.Context).Height(m.height - 10).Render(content.String())
+}
+
+// contextWarnPct is how much of the context budget can be used before the
+// context pane warns
+const contextWarnPct = 80
+
+// contextUsage is how close a debate's context files are to the budget
+type contextUsage int
+
+const (
+	contextUnderBudget contextUsage = iota
+	contextNearBudget               // At least contextWarnPct of the budget
+	contextOverBudget               // Some files won't be sent
+)
+
+// contextPaneSummary lists a debate's context files by path with their
+// sizes, the pinned preamble file first, and totals them against budget
+func contextPaneSummary(debate *Debate, budget int, pinned string) (files []string, total string, usage contextUsage) {
+	paths := make([]string, 0, len(debate.ContextFiles))
+	for path := range debate.ContextFiles {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if (paths[i] == pinned) != (paths[j] == pinned) {
+			return paths[i] == pinned
+		}
+		return paths[i] < paths[j]
+	})
+
+	size := 0
+	for _, path := range paths {
+		n := len(debate.ContextFiles[path])
+		size += n
+		if path == pinned {
+			files = append(files, fmt.Sprintf("* %s (%s, pinned)", path, formatSize(n)))
+		} else {
+			files = append(files, fmt.Sprintf("* %s (%s)", path, formatSize(n)))
+		}
+	}
+
+	pct := size * 100 / budget
+	total = fmt.Sprintf("Total %s of %s (%d%%)", formatSize(size), formatSize(budget), pct)
+	switch {
+	case size > budget:
+		usage = contextOverBudget
+		total += " - over budget, some files won't be sent"
+	case pct >= contextWarnPct:
+		usage = contextNearBudget
+	}
+	return files, total, usage
+}
+
+// formatSize formats a byte count, e.g. "512 B" or "4.2 KB"
+func formatSize(n int) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
 }
 
 func (m Model) renderChatPane() string {
@@ -778,10 +1768,11 @@ func (m Model) renderChatPane() string {
 		msgCount = len(debate.Messages)
 	}
 	title += DimStyle.Render(fmt.Sprintf(" (%d msgs)", msgCount))
+	if m.onlySource != "" {
+		title += " " + ModelStyle(m.onlySource).Render("only "+formatSource(m.onlySource))
+	}
 
-	chatWidth := m.width - 25 - 15 - 6
-
-	return style.Width(chatWidth).Height(m.height - 10).Render(
+	return style.Width(m.panes.Chat).Height(m.height - 10).Render(
 		lipgloss.JoinVertical(lipgloss.Left, title, m.chatView.View()),
 	)
 }
@@ -796,21 +1787,43 @@ func (m Model) renderModelsPane() string {
 	content.WriteString(TitleStyle.Render("MODELS"))
 	content.WriteString("\n\n")
 
-	for _, model := range m.registry.All() {
+	for i, model := range m.registry.All() {
 		info := model.Info()
 		status := model.Status()
 		indicator := statusIndicator(status)
+		if m.health[info.ID] != nil && status == models.StatusIdle {
+			indicator = unhealthyIndicator()
+		}
 		mstyle := ModelStyle(info.ID)
 
 		name := info.Name
-		if status == models.StatusResponding {
-			name += "..."
+		var elapsed string
+		if debate := m.activeDebate(); debate != nil && status == models.StatusResponding {
+			name += debate.streamingIndicator()
+			if start, ok := debate.ModelStartTime[info.ID]; ok {
+				elapsed = formatElapsedTime(time.Since(start))
+			}
 		}
 
-		content.WriteString(fmt.Sprintf("%s %s\n", indicator, mstyle.Render(name)))
+		cursor := ""
+		if m.focus == FocusModels {
+			cursor = "  "
+			if i == m.modelCursor {
+				cursor = "> "
+			}
+		}
+		content.WriteString(fmt.Sprintf("%s%s %s\n", cursor, indicator, mstyle.Render(name)))
+		if p, ok := model.(interface{ Persona() string }); ok && p.Persona() != "" {
+			content.WriteString(DimStyle.Render("  "+p.Persona()) + "\n")
+		}
+		if elapsed != "" {
+			content.WriteString(DimStyle.Render("  ("+elapsed+")") + "\n")
+		} else if stats := m.modelStats[info.ID]; stats != nil {
+			content.WriteString(DimStyle.Render("  "+stats.Hint()) + "\n")
+		}
 	}
 
-	return style.Width(15).Height(m.height - 10).Render(content.String())
+	return style.Width(m.panes.Models).Height(m.height - 10).Render(content.String())
 }
 
 func (m Model) renderStatusBar() string {
@@ -818,7 +1831,9 @@ func (m Model) renderStatusBar() string {
 
 	// Debate status
 	status := StatusOK.Render("* READY")
-	if debate != nil && debate.Paused {
+	if debate != nil && debate.ReadOnly {
+		status = StatusWarn.Render("* READ-ONLY")
+	} else if debate != nil && debate.Paused {
 		status = StatusWarn.Render("* PAUSED")
 	}
 
@@ -829,6 +1844,19 @@ func (m Model) renderStatusBar() string {
 	keys := DimStyle.Render("Enter:send | Shift+Enter:newline | F1:help")
 
 	left := lipgloss.JoinHorizontal(lipgloss.Left, " ", status, "  ", tabInfo)
+	if debate != nil {
+		result := debate.Consensus
+		if meter := consensusMeter(result); meter != "" {
+			style := DimStyle
+			if result.HasConsensus {
+				style = StatusOK
+			}
+			left = lipgloss.JoinHorizontal(lipgloss.Left, left, "  ", style.Render(meter))
+		}
+	}
+	if debate != nil && debate.Usage.TotalTokens() > 0 {
+		left = lipgloss.JoinHorizontal(lipgloss.Left, left, "  ", DimStyle.Render(formatUsage(debate.Usage)))
+	}
 	right := keys + " "
 
 	padding := m.width - lipgloss.Width(left) - lipgloss.Width(right)
@@ -840,15 +1868,69 @@ func (m Model) renderStatusBar() string {
 	return separator + "\n" + left + strings.Repeat(" ", padding) + right
 }
 
+// consensusMeter renders a round's positions compactly, one block per
+// model with agreeing models filled, e.g. "▇▇▇░ 3 agree / 1 unknown / 0 object".
+// It's empty before any model has taken a position.
+func consensusMeter(r consensus.ConsensusResult) string {
+	if r.TotalCount == 0 {
+		return ""
+	}
+	bar := strings.Repeat("▇", r.AgreeCount) + strings.Repeat("░", r.TotalCount-r.AgreeCount)
+	text := fmt.Sprintf("%s %d agree / %d unknown / %d object", bar, r.AgreeCount, r.UnknownCount, r.ObjectCount)
+	if r.AddCount > 0 {
+		text += fmt.Sprintf(" / %d add", r.AddCount)
+	}
+	return text
+}
+
+// formatUsage renders token counts and cost compactly, e.g. "12.3k in / 1.2k out $0.04"
+func formatUsage(u models.Usage) string {
+	text := fmt.Sprintf("%s in / %s out", formatTokens(u.PromptTokens), formatTokens(u.CompletionTokens))
+	if u.Cost > 0 {
+		text += fmt.Sprintf(" $%.2f", u.Cost)
+	}
+	return text
+}
+
+// formatTokens abbreviates large token counts
+func formatTokens(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// updateInputLimits adjusts input state before a keypress is handled.
+// Pasted text may go past the typing limit, which comes back once the input
+// is small again. Any key other than Enter withdraws a pending large send.
+func (m *Model) updateInputLimits(msg tea.KeyMsg) {
+	if msg.Type != tea.KeyEnter {
+		m.confirmSendChars = 0
+	}
+	if msg.Paste {
+		m.input.CharLimit = pasteCharLimit
+	} else if m.input.Length() <= inputCharLimit {
+		m.input.CharLimit = inputCharLimit
+	}
+}
+
 func (m Model) renderInputPane() string {
 	style := InactiveBox
 	if m.focus == FocusInput {
 		style = ActiveBox
 	}
 
-	label := "Message"
+	label := DimStyle.Render("Message")
+	if debate := m.activeDebate(); debate != nil && debate.ReadOnly {
+		label = StatusWarn.Render(readOnlyBanner)
+	}
+	if m.confirm != nil {
+		label = StatusWarn.Render(m.confirm.prompt)
+	} else if m.confirmSendChars > 0 {
+		label = StatusWarn.Render(fmt.Sprintf("Press Enter again to send %d chars", m.confirmSendChars))
+	}
 	return style.Width(m.width - 2).Render(
-		DimStyle.Render(label) + "\n" + m.input.View(),
+		label + "\n" + m.input.View(),
 	)
 }
 
@@ -860,6 +1942,10 @@ func (m Model) updateHistoryView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "ctrl+q":
+			if m.cancelDebate != nil {
+				m.cancelDebate()
+			}
+			m.flushStreaming()
 			return m, tea.Quit
 
 		case "esc", "q":
@@ -879,6 +1965,12 @@ func (m Model) updateHistoryView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "a":
+			if m.historyState != nil {
+				m.historyState.ToggleAllProjects(m.store)
+			}
+			return m, nil
+
 		case "enter":
 			// Resume the selected debate
 			if m.historyState != nil {
@@ -890,7 +1982,7 @@ func (m Model) updateHistoryView(msg tea.Msg) (tea.Model, tea.Cmd) {
 						for i, d := range m.debates {
 							if d.ID == debate.ID {
 								// Switch to existing tab
-								m.activeTab = i
+								m.setActiveTab(i)
 								m.viewMode = ViewNormal
 								m.updateChatView()
 								return m, nil
@@ -898,8 +1990,9 @@ func (m Model) updateHistoryView(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 
 						// Add as new tab
+						m.updateConsensus(debate)
 						m.debates = append(m.debates, debate)
-						m.activeTab = len(m.debates) - 1
+						m.setActiveTab(len(m.debates) - 1)
 						m.updateChatView()
 					}
 				}
@@ -919,182 +2012,485 @@ func (m Model) updateHistoryView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// dispatchToModels sends the prompt to all enabled models in parallel
-// It creates a goroutine that reads from the orchestrator's response channel
-// and forwards messages to the tea.Program via Send()
+// dispatchToModels sends a prompt, with context files, to all models
 func (m *Model) dispatchToModels(prompt string) tea.Cmd {
-	return func() tea.Msg {
-		debate := m.activeDebate()
-		if debate == nil || m.orchestrator == nil {
-			return nil
-		}
-
-		// Create cancellable context
-		ctx, cancel := context.WithCancel(context.Background())
-		m.cancelDebate = cancel
-
-		// Build the full prompt including context files
-		fullPrompt := prompt
-		if len(debate.ContextFiles) > 0 {
-			var contextBuilder strings.Builder
-			contextBuilder.WriteString("=== CONTEXT FILES ===\n\n")
-			for path, content := range debate.ContextFiles {
-				contextBuilder.WriteString(fmt.Sprintf("--- %s ---\n", path))
-				contextBuilder.WriteString(content)
-				contextBuilder.WriteString("\n\n")
-			}
-			contextBuilder.WriteString("=== END CONTEXT ===\n\n")
-			contextBuilder.WriteString("User question:\n")
-			contextBuilder.WriteString(prompt)
-			fullPrompt = contextBuilder.String()
-		}
-
-		// Convert debate messages to model messages format
-		var history []models.Message
-		for _, msg := range debate.Messages {
-			history = append(history, models.Message{
-				Source:    msg.Source,
-				Content:   msg.Content,
-				Timestamp: msg.Timestamp,
-			})
-		}
+	debate := m.activeDebate()
+	if debate == nil || m.orchestrator == nil {
+		return nil
+	}
 
+	promptFor, omitted := contextPromptFor(debate, prompt)
+	if len(omitted) > 0 {
+		m.contextNote(debate, fmt.Sprintf("Context files over the %d KB budget were not sent: %s",
+			contextBudget/1024, strings.Join(omitted, ", ")))
+		m.updateChatView()
+	}
+	m.loadAllMessages(debate)
+	history := m.promptHistory(debate)
+	m.attachTo(debate, history, m.registry.Enabled())
+	m.lastRoundPrompt = prompt
+	ctx, seq := m.startRound()
+	orch := m.orchestrator
+
+	return tea.Batch(m.startTicking(), func() tea.Msg {
 		// Start parallel model requests
-		responses := m.orchestrator.ParallelSeed(ctx, history, fullPrompt)
-
-		// Forward responses to the UI as tea messages
-		go func() {
-			for resp := range responses {
-				if program != nil {
-					program.Send(modelResponseMsg{
-						modelID:   resp.ModelID,
-						content:   resp.Content,
-						done:      resp.Done,
-						err:       resp.Error,
-						isTimeout: resp.IsTimeout,
-					})
-				}
-			}
-			// Signal that all models are done
-			if program != nil {
-				program.Send(allModelsDoneMsg{})
-			}
-		}()
-
+		forwardResponses(seq, orch.ParallelSeedEach(ctx, history, promptFor))
 		return nil
-	}
+	})
 }
 
-// buildDiscussionPrompt creates a prompt for the discussion round
-// that asks models to critique each other's responses using AGREE/OBJECT/ADD format
-func (m *Model) buildDiscussionPrompt(debate *Debate) string {
-	if debate == nil || len(debate.Messages) == 0 {
-		return ""
+// mentionTarget splits "@model question" into the model ID and the question.
+// ok is false if input doesn't start with a mention.
+func mentionTarget(input string) (modelID, prompt string, ok bool) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, "@") {
+		return "", "", false
 	}
+	mention, rest, _ := strings.Cut(input, " ")
+	return strings.ToLower(mention[1:]), strings.TrimSpace(rest), true
+}
 
-	// Find the original user question
-	var userQuestion string
-	for _, msg := range debate.Messages {
-		if msg.Source == "user" {
-			userQuestion = msg.Content
-			break
-		}
+// askModel sends a follow-up to a single model, outside the debate rounds.
+// input is the user's message as typed, mention included.
+func (m *Model) askModel(input, modelID, prompt string) tea.Cmd {
+	debate := m.activeDebate()
+	if debate == nil {
+		return nil
 	}
 
-	// Collect the most recent model responses (since last user message or system prompt for discussion)
-	var modelResponses []struct {
-		source  string
-		content string
+	if m.registry.Get(modelID) == nil {
+		debate.AddMessage("system", fmt.Sprintf("Unknown model @%s. Available: %s", modelID, mentionList(m.registry.Enabled())))
+		m.updateChatView()
+		return nil
 	}
-
-	// Find where the current round of responses started
-	startIdx := 0
-	for i := len(debate.Messages) - 1; i >= 0; i-- {
-		msg := debate.Messages[i]
-		if msg.Source == "user" || (msg.Source == "system" && (strings.Contains(msg.Content, "Discussion Round") || strings.Contains(msg.Content, "review each other"))) {
-			startIdx = i + 1
-			break
-		}
+	if prompt == "" {
+		debate.AddMessage("system", fmt.Sprintf("Add a question after @%s, e.g. @%s why did you object?", modelID, modelID))
+		m.updateChatView()
+		return nil
 	}
 
-	for i := startIdx; i < len(debate.Messages); i++ {
-		msg := debate.Messages[i]
-		if msg.Source != "system" && msg.Source != "user" && !msg.IsError {
-			// Truncate very long responses to keep discussion prompts manageable
-			content := msg.Content
-			const maxResponseLen = 2000
-			if len(content) > maxResponseLen {
-				content = content[:maxResponseLen] + "\n... [response truncated for brevity]"
-			}
-			modelResponses = append(modelResponses, struct {
-				source  string
-				content string
-			}{source: msg.Source, content: content})
-		}
-	}
+	debate.AddMessage("user", input)
+	m.saveMessage(debate.ID, "user", input, "user")
+	debate.AwaitingUser = false
+	m.streamingMsgs = make(map[string]int)
+	m.updateChatView()
+	return m.dispatchToModel(modelID, prompt)
+}
 
-	if len(modelResponses) == 0 {
-		return ""
+// challenge has modelID argue against the emerging consensus. Its reply
+// ends the round like any other, so consensus is checked again afterward.
+func (m *Model) challenge(modelID string) tea.Cmd {
+	debate := m.activeDebate()
+	if debate == nil || m.orchestrator == nil {
+		return nil
+	}
+	if m.registry.Get(modelID) == nil {
+		debate.AddMessage("system", fmt.Sprintf("Unknown model %s. Available: %s", modelID, strings.Join(m.registry.Enabled(), ", ")))
+		m.updateChatView()
+		return nil
 	}
 
-	// Build the discussion prompt
-	var prompt strings.Builder
-	prompt.WriteString(fmt.Sprintf("=== Discussion Round %d ===\n\n", debate.DebateRound+1))
-	prompt.WriteString("Original question: ")
-	prompt.WriteString(userQuestion)
-	prompt.WriteString("\n\n")
-	prompt.WriteString("Previous responses from the roundtable:\n\n")
+	msg := fmt.Sprintf("=== Challenge: %s argues against the emerging consensus ===", formatSource(modelID))
+	debate.AddMessage("system", msg)
+	m.saveMessage(debate.ID, "system", msg, "system")
+	debate.AwaitingUser = false
+	m.streamingMsgs = make(map[string]int)
+	m.updateChatView()
 
-	for _, resp := range modelResponses {
-		prompt.WriteString(fmt.Sprintf("**%s said:**\n%s\n\n", formatSource(resp.source), resp.content))
-	}
+	m.loadAllMessages(debate)
+	history := m.promptHistory(debate)
+	ctx, seq := m.startRound()
+	orch := m.orchestrator
 
-	prompt.WriteString("---\n\n")
-	prompt.WriteString("Review the other models' responses and state your position. You MUST start your response with one of:\n\n")
-	prompt.WriteString("- **AGREE**: If you fully support another model's approach, explain why and what you like about it.\n")
-	prompt.WriteString("- **OBJECT**: If you have concerns or disagree with an approach, explain your reasoning and propose an alternative.\n")
-	prompt.WriteString("- **ADD**: If you generally agree but want to enhance or supplement the approach with additional considerations.\n\n")
-	prompt.WriteString("Be specific about which model(s) you're responding to. If the group is converging on a consensus, acknowledge it.\n")
+	return tea.Batch(m.startTicking(), func() tea.Msg {
+		forwardResponses(seq, orch.ChallengePrompt(ctx, modelID, history))
+		return nil
+	})
+}
 
-	return prompt.String()
+// mentionList formats model IDs as "@a, @b"
+func mentionList(ids []string) string {
+	mentions := make([]string, len(ids))
+	for i, id := range ids {
+		mentions[i] = "@" + id
+	}
+	return strings.Join(mentions, ", ")
 }
 
-// checkDebateConsensus analyzes the most recent round of model responses
-// and returns consensus analysis results
-func (m *Model) checkDebateConsensus(debate *Debate) consensus.ConsensusResult {
-	if debate == nil || len(debate.Messages) == 0 {
-		return consensus.ConsensusResult{}
+// dispatchToModel sends a prompt, with context files, to one model
+func (m *Model) dispatchToModel(modelID, prompt string) tea.Cmd {
+	debate := m.activeDebate()
+	if debate == nil || m.orchestrator == nil {
+		return nil
 	}
 
-	// Find the most recent user message and collect model responses after it
-	positions := make(map[string]consensus.ParsedPosition)
+	promptFor, _ := contextPromptFor(debate, prompt)
+	var fullPrompt string
+	if model := m.registry.Get(modelID); model != nil {
+		fullPrompt = promptFor(model.Info())
+	}
+	m.loadAllMessages(debate)
+	history := m.promptHistory(debate)
+	m.attachTo(debate, history, []string{modelID})
+	ctx, seq := m.startRound()
+	m.round = roundDirect
+	orch := m.orchestrator
+
+	return tea.Batch(m.startTicking(), func() tea.Msg {
+		forwardResponses(seq, orch.SendToModel(ctx, modelID, history, fullPrompt))
+		return nil
+	})
+}
 
-	// Iterate backwards to find the last user message
-	lastUserIdx := -1
+// attachTo moves pending /attach images onto the user message that ends
+// history, and notes which of targets can't see them
+func (m *Model) attachTo(debate *Debate, history []models.Message, targets []string) {
+	if len(m.attachments) == 0 || len(history) == 0 || history[len(history)-1].Source != "user" {
+		return
+	}
+	history[len(history)-1].Attachments = m.attachments
+	names := models.AttachmentNames(m.attachments)
+	m.attachments = nil
+
+	var textOnly []string
+	for _, id := range targets {
+		if model := m.registry.Get(id); model != nil && !model.Info().CanSeeImages {
+			textOnly = append(textOnly, formatSource(id))
+		}
+	}
+	if len(textOnly) > 0 {
+		debate.AddMessage("system", fmt.Sprintf("%s only read text, so they got your prompt without %s.", strings.Join(textOnly, ", "), names))
+		m.updateChatView()
+	}
+}
+
+// roundResults counts the model responses in the current round that
+// succeeded and that failed
+func roundResults(debate *Debate) (succeeded, failed int) {
+	for _, msg := range debate.Messages[roundStart(debate):] {
+		switch {
+		case !isParticipant(msg.Source):
+		case msg.IsError:
+			failed++
+		default:
+			succeeded++
+		}
+	}
+	return succeeded, failed
+}
+
+// allFailed reports whether every model response this round was an error
+func allFailed(debate *Debate) bool {
+	succeeded, failed := roundResults(debate)
+	return succeeded == 0 && failed > 0
+}
+
+// handleFailedRound explains a round in which every model failed and,
+// unless the round was itself a retry, offers to run it once more
+func (m *Model) handleFailedRound(debate *Debate, seq int) {
+	debate.AwaitingUser = true
+	msg := "No model responded successfully this round. Try /retry, check which models are reachable with /models refresh, or raise the timeout with /timeout <seconds> if they timed out."
+	debate.AddMessage("system", msg)
+	m.saveMessage(debate.ID, "system", msg, "system")
+	m.updateChatView()
+
+	if seq == m.autoRetrySeq || m.lastRoundPrompt == "" {
+		return
+	}
+	m.confirm = &confirmation{
+		prompt: "Every model failed. Retry the round once? (y/N)",
+		onYes: func(m *Model) tea.Cmd {
+			if m.activeDebate() != debate {
+				return nil
+			}
+			cmd := m.retryRound()
+			m.autoRetrySeq = m.roundSeq
+			return cmd
+		},
+	}
+}
+
+// retryRound sends the last round's prompt to the models again
+func (m *Model) retryRound() tea.Cmd {
+	debate := m.activeDebate()
+	if debate == nil || m.roundInFlight() {
+		return nil
+	}
+	if debate.ReadOnly {
+		debate.AddMessage("system", readOnlyBanner)
+		m.updateChatView()
+		return nil
+	}
+	if m.lastRoundPrompt == "" {
+		debate.AddMessage("system", "Nothing to retry yet.")
+		m.updateChatView()
+		return nil
+	}
+	msg := "=== Retrying the round ==="
+	debate.AddMessage("system", msg)
+	m.saveMessage(debate.ID, "system", msg, "system")
+	debate.AwaitingUser = false
+	m.streamingMsgs = make(map[string]int)
+	m.updateChatView()
+	return m.dispatchToModels(m.lastRoundPrompt)
+}
+
+// roundStart returns the index of the first message of the current round of
+// responses: the one after the last user message or discussion round marker
+func roundStart(debate *Debate) int {
 	for i := len(debate.Messages) - 1; i >= 0; i-- {
-		if debate.Messages[i].Source == "user" {
-			lastUserIdx = i
+		msg := debate.Messages[i]
+		if msg.Source == "user" || (msg.Source == "system" && (strings.Contains(msg.Content, "Discussion Round") || strings.Contains(msg.Content, "review each other"))) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// isParticipant reports whether messages from source are debate responses,
+// as opposed to the user's, system notes or moderator summaries
+func isParticipant(source string) bool {
+	return source != "user" && source != "system" && source != moderatorSource
+}
+
+// moderatorSource is the source of moderator summaries
+const moderatorSource = "moderator"
+
+// summaryDue reports whether the round that just finished should be summed
+// up: summaries are enabled and more than one model responded
+func summaryDue(debate *Debate, enabled bool) bool {
+	if !enabled || debate == nil {
+		return false
+	}
+	responded := make(map[string]bool)
+	for _, msg := range debate.Messages[roundStart(debate):] {
+		if isParticipant(msg.Source) && !msg.IsError {
+			responded[msg.Source] = true
+		}
+	}
+	return len(responded) > 1
+}
+
+// autoSummarize reports whether a moderator summary follows each round
+func (m *Model) autoSummarize() bool {
+	return m.config != nil && m.config.Defaults.AutoSummarize
+}
+
+// dispatchSummary asks the moderator model to sum up the latest round.
+// Returns nil if the moderator isn't available.
+func (m *Model) dispatchSummary() tea.Cmd {
+	debate := m.activeDebate()
+	if debate == nil || m.orchestrator == nil {
+		return nil
+	}
+	moderator := m.config.Defaults.Moderator
+	if m.registry.Get(moderator) == nil {
+		debate.AddMessage("system", fmt.Sprintf("Moderator %q isn't an enabled model; skipping the summary.", moderator))
+		m.updateChatView()
+		return nil
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("You are the moderator of this debate, not a participant. ")
+	prompt.WriteString("Summarize this round in a few sentences: where the models agree, where they disagree, and what is still open. ")
+	prompt.WriteString("Don't take a side and don't use AGREE/OBJECT/ADD.\n\n")
+	if question := debate.LastUserPrompt(); question != "" {
+		prompt.WriteString("Question: " + question + "\n\n")
+	}
+	for _, msg := range debate.Messages[roundStart(debate):] {
+		if isParticipant(msg.Source) && !msg.IsError {
+			prompt.WriteString(fmt.Sprintf("**%s said:**\n%s\n\n", formatSource(msg.Source), msg.Content))
+		}
+	}
+
+	ctx, seq := m.startRound()
+	m.round = roundSummary
+	orch := m.orchestrator
+	text := prompt.String()
+
+	return tea.Batch(m.startTicking(), func() tea.Msg {
+		forwardResponses(seq, orch.SendToModel(ctx, moderator, nil, text))
+		return nil
+	})
+}
+
+// buildDiscussionPrompt creates a prompt for the discussion round
+// that asks models to critique each other's responses using AGREE/OBJECT/ADD format
+func (m *Model) buildDiscussionPrompt(debate *Debate) string {
+	if debate == nil || len(debate.Messages) == 0 {
+		return ""
+	}
+
+	// Find the original user question
+	var userQuestion string
+	for _, msg := range debate.Messages {
+		if msg.Source == "user" {
+			userQuestion = msg.Content
 			break
 		}
 	}
 
-	if lastUserIdx == -1 {
-		return consensus.ConsensusResult{}
+	// Collect the most recent model responses (since last user message or system prompt for discussion)
+	var modelResponses []struct {
+		source  string
+		content string
 	}
 
-	// Collect model responses after the last user message
-	for i := lastUserIdx + 1; i < len(debate.Messages); i++ {
+	for i := roundStart(debate); i < len(debate.Messages); i++ {
 		msg := debate.Messages[i]
-		// Skip system messages and user messages
-		if msg.Source == "system" || msg.Source == "user" {
-			continue
+		if isParticipant(msg.Source) && !msg.IsError {
+			// Truncate very long responses to keep discussion prompts manageable
+			content := msg.Content
+			const maxResponseLen = 2000
+			if len(content) > maxResponseLen {
+				content = content[:maxResponseLen] + "\n... [response truncated for brevity]"
+			}
+			modelResponses = append(modelResponses, struct {
+				source  string
+				content string
+			}{source: msg.Source, content: content})
 		}
-		// This is a model response
-		parsed := consensus.ParseResponse(msg.Content)
-		positions[msg.Source] = parsed
 	}
 
-	return consensus.AnalyzeConsensus(positions)
+	if len(modelResponses) == 0 {
+		return ""
+	}
+
+	// Build the discussion prompt
+	var prompt strings.Builder
+	prompt.WriteString(fmt.Sprintf("=== Discussion Round %d ===\n\n", debate.DebateRound+1))
+	prompt.WriteString("Original question: ")
+	prompt.WriteString(userQuestion)
+	prompt.WriteString("\n\n")
+	prompt.WriteString("Previous responses from the roundtable:\n\n")
+
+	for _, resp := range modelResponses {
+		prompt.WriteString(fmt.Sprintf("**%s said:**\n%s\n\n", formatSource(resp.source), resp.content))
+	}
+
+	prompt.WriteString("---\n\n")
+	prompt.WriteString("Review the other models' responses and state your position. You MUST start your response with one of:\n\n")
+	prompt.WriteString("- **AGREE**: If you fully support another model's approach, explain why and what you like about it.\n")
+	prompt.WriteString("- **OBJECT**: If you have concerns or disagree with an approach, explain your reasoning and propose an alternative.\n")
+	prompt.WriteString("- **ADD**: If you generally agree but want to enhance or supplement the approach with additional considerations.\n\n")
+	prompt.WriteString("Be specific about which model(s) you're responding to. If the group is converging on a consensus, acknowledge it.\n")
+
+	return prompt.String()
+}
+
+// checkDebateConsensus analyzes the most recent round of model responses
+// and returns consensus analysis results
+func (m *Model) checkDebateConsensus(debate *Debate) consensus.ConsensusResult {
+	return debateConsensus(debate, m.config)
+}
+
+// updateConsensus analyzes the debate's latest round and stores the result
+// for the status bar. Call it when a round finishes or a debate is loaded,
+// not on every render.
+func (m *Model) updateConsensus(debate *Debate) consensus.ConsensusResult {
+	debate.Consensus = m.checkDebateConsensus(debate)
+	return debate.Consensus
+}
+
+// debateConsensus analyzes the debate's latest round with cfg's keywords
+// and participation floor; a nil cfg gives the built-ins and no floor
+func debateConsensus(debate *Debate, cfg *config.Config) consensus.ConsensusResult {
+	positions := latestRoundPositions(debate, newConsensusParser(cfg))
+	if positions == nil {
+		return consensus.ConsensusResult{}
+	}
+	minParticipants := 0
+	if cfg != nil {
+		minParticipants = cfg.Defaults.MinConsensusParticipants
+	}
+	return consensus.AnalyzeConsensusWithMinimum(positions, minParticipants)
+}
+
+// consensusTally describes the positions in the latest round and whether
+// they amount to consensus, for /consensus check
+func (m *Model) consensusTally(debate *Debate) string {
+	if len(latestRoundPositions(debate, m.consensusParser())) == 0 {
+		return "Consensus check: no model positions since your last message."
+	}
+	result := m.checkDebateConsensus(debate)
+	tally := fmt.Sprintf("Consensus check: %d agree, %d object, %d add, %d unclear.",
+		result.AgreeCount, result.ObjectCount, result.AddCount, result.UnknownCount)
+	switch {
+	case result.HasConsensus:
+		tally += " Consensus reached."
+	case result.InsufficientParticipation:
+		tally += " No consensus: " + result.ParticipationMessage() + "."
+	default:
+		tally += " No consensus."
+	}
+	if split := result.DescribeSplit(formatSource); split != "" {
+		tally += "\nNote: " + split + "."
+	}
+	for _, line := range result.DescribeObjections(formatSource) {
+		tally += "\n" + line
+	}
+	if hint := m.consensusGapHint(debate); hint != "" {
+		tally += "\nHint: " + hint
+	}
+	return tally
+}
+
+// consensusGapHint predicts what would need to change for the latest round to
+// reach consensus. Returns empty string if there is nothing to analyze.
+func (m *Model) consensusGapHint(debate *Debate) string {
+	positions := latestRoundPositions(debate, m.consensusParser())
+	if len(positions) == 0 {
+		return ""
+	}
+	gap := consensus.AnalyzeGap(positions, m.minConsensusParticipants())
+	if gap.Reached {
+		return ""
+	}
+	return gap.Describe(formatSource)
+}
+
+// consensusParser returns a position parser with the configured keywords
+func (m *Model) consensusParser() *consensus.Parser {
+	return newConsensusParser(m.config)
+}
+
+// newConsensusParser builds a position parser from the consensus config;
+// a nil config gives the built-in keywords
+func newConsensusParser(cfg *config.Config) *consensus.Parser {
+	if cfg == nil {
+		return consensus.NewParser(consensus.ParserOptions{})
+	}
+	return consensus.NewParser(cfg.Consensus.ParserOptions())
+}
+
+// minConsensusParticipants returns the configured participation floor for consensus
+func (m *Model) minConsensusParticipants() int {
+	if m.config == nil {
+		return 0
+	}
+	return m.config.Defaults.MinConsensusParticipants
+}
+
+// latestRoundPositions parses each model's position from the responses
+// after the most recent user message. Returns nil if there is no user message.
+func latestRoundPositions(debate *Debate, parser *consensus.Parser) map[string]consensus.ParsedPosition {
+	if debate == nil {
+		return nil
+	}
+	return parser.ExtractLatestRound(consensusMessages(debate.Messages))
+}
+
+// consensusMessages converts messages to the shape consensus analysis reads,
+// leaving out responses cut short when the others reached consensus
+func consensusMessages(messages []DebateMessage) []consensus.Message {
+	converted := make([]consensus.Message, 0, len(messages))
+	for _, msg := range messages {
+		if msg.MsgType == db.MsgTypeStopped {
+			continue
+		}
+		converted = append(converted, consensus.Message{Source: msg.Source, Content: msg.Content})
+	}
+	return converted
 }
 
 // handleCommand processes a parsed slash command and returns the updated model
@@ -1113,17 +2509,63 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 		}
 		debateID := uuid.New().String()[:8]
 		newDebate := NewDebate(debateID, name)
+		newDebate.ProjectPath = m.project
 		m.debates = append(m.debates, newDebate)
-		m.activeTab = len(m.debates) - 1
 
 		if m.store != nil {
-			m.store.CreateDebate(debateID, name, "")
+			m.store.CreateDebate(debateID, name, m.project)
 		}
+		pinPreamble(newDebate, m.preambleFile(), m.store)
+		metrics.Default.DebateStarted()
+		m.setActiveTab(len(m.debates) - 1)
 		m.updateChatView()
 		return m, nil
 
 	case commands.CloseDebate:
-		m.closeTab(m.activeTab)
+		m.requestCloseTab(m.activeTab)
+		return m, nil
+
+	case commands.Reopen:
+		m.reopenLastClosed()
+		return m, nil
+
+	case commands.ListCommands:
+		if debate != nil {
+			debate.AddMessage("system", commandsList(debate.ProposedCommands()))
+			m.updateChatView()
+		}
+		return m, nil
+
+	case commands.DebugPaths:
+		if debate != nil {
+			debate.AddMessage("system", debugPaths(m.store))
+			m.updateChatView()
+		}
+		return m, nil
+
+	case commands.Attach:
+		if debate == nil {
+			return m, nil
+		}
+		if a, err := models.LoadImage(expandHome(c.Path)); err != nil {
+			debate.AddMessage("system", fmt.Sprintf("Can't attach: %v", err))
+		} else {
+			m.attachments = append(m.attachments, a)
+			debate.AddMessage("system", fmt.Sprintf("Attached %s (%d KB); it will be sent with your next prompt.", a.Name, (len(a.Data)+1023)/1024))
+		}
+		m.updateChatView()
+		return m, nil
+
+	case commands.DebugDump:
+		if debate == nil {
+			return m, nil
+		}
+		if path, err := dumpDebate(m.store, debate.ID); err != nil {
+			debate.AddMessage("system", fmt.Sprintf("Dump failed: %v", err))
+		} else {
+			debate.AddMessage("system", "Wrote debate record to "+path)
+		}
+		m.updateChatView()
 		return m, nil
 
 	case commands.RenameDebate:
@@ -1146,7 +2588,7 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 		} else {
 			debate.ContextFiles[c.Path] = content
 			m.saveContextFile(debate.ID, c.Path, content)
-			debate.AddMessage("system", fmt.Sprintf("Added context: %s", c.Path))
+			m.contextNote(debate, fmt.Sprintf("Added context: %s", c.Path))
 		}
 		m.updateChatView()
 		return m, nil
@@ -1157,7 +2599,35 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 			if m.store != nil {
 				m.store.RemoveContextFile(debate.ID, c.Path)
 			}
-			debate.AddMessage("system", fmt.Sprintf("Removed context: %s", c.Path))
+			m.contextNote(debate, fmt.Sprintf("Removed context: %s", c.Path))
+			m.updateChatView()
+		}
+		return m, nil
+
+	case commands.AddTag:
+		if debate != nil {
+			if debate.AddTag(c.Name) {
+				if m.store != nil {
+					m.store.AddTag(debate.ID, c.Name)
+				}
+				debate.AddMessage("system", fmt.Sprintf("Tagged #%s", c.Name))
+			} else {
+				debate.AddMessage("system", fmt.Sprintf("Already tagged #%s", c.Name))
+			}
+			m.updateChatView()
+		}
+		return m, nil
+
+	case commands.RemoveTag:
+		if debate != nil {
+			if debate.RemoveTag(c.Name) {
+				if m.store != nil {
+					m.store.RemoveTag(debate.ID, c.Name)
+				}
+				debate.AddMessage("system", fmt.Sprintf("Removed tag #%s", c.Name))
+			} else {
+				debate.AddMessage("system", fmt.Sprintf("Not tagged #%s", c.Name))
+			}
 			m.updateChatView()
 		}
 		return m, nil
@@ -1177,15 +2647,57 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case commands.RefreshContext:
+		if debate != nil {
+			m.contextNote(debate, m.refreshContext(debate))
+			m.updateChatView()
+		}
+		return m, nil
+
 	case commands.ToggleModels:
+		if m.hideModels {
+			m.togglePane(FocusModels)
+		}
 		m.focus = FocusModels
+		m.input.Blur()
 		return m, nil
 
+	case commands.RefreshModels:
+		if debate != nil {
+			debate.AddMessage("system", "Checking models...")
+			m.updateChatView()
+		}
+		registry := m.registry
+		return m, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+			return modelsRefreshedMsg{registry: registry, result: registry.Refresh(ctx)}
+		}
+
 	case commands.ForceConsensus:
 		if debate != nil {
 			// Dispatch consensus check to all models
 			m.streamingMsgs = make(map[string]int)
-			return m, m.dispatchConsensusCheck()
+			cmd := m.dispatchConsensusCheck()
+			return m, cmd
+		}
+		return m, nil
+
+	case commands.Challenge:
+		if m.roundInFlight() {
+			if debate != nil {
+				debate.AddMessage("system", "Wait for the current round to finish before starting a challenge.")
+				m.updateChatView()
+			}
+			return m, nil
+		}
+		cmd := m.challenge(c.Model)
+		return m, cmd
+
+	case commands.CheckConsensus:
+		if debate != nil {
+			debate.AddMessage("system", m.consensusTally(debate))
+			m.updateChatView()
 		}
 		return m, nil
 
@@ -1196,15 +2708,33 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 		// Check for consensus before allowing execution
 		consensusResult := m.checkDebateConsensus(debate)
 		if !consensusResult.HasConsensus {
-			debate.AddMessage("system", "Cannot execute: consensus not reached. Use /consensus to check positions.")
+			reason := "consensus not reached"
+			if consensusResult.InsufficientParticipation {
+				reason = consensusResult.ParticipationMessage()
+			}
+			debate.AddMessage("system", fmt.Sprintf("Cannot execute: %s. Use /consensus to check positions.", reason))
+			m.updateChatView()
+			return m, nil
+		}
+
+		executor := m.executorID()
+		if executor == "" {
+			debate.AddMessage("system", "Cannot execute: no enabled model can execute tools.")
 			m.updateChatView()
 			return m, nil
 		}
+		debate.AddMessage("system", fmt.Sprintf("Execution requested. Sending to %s for implementation...", formatSource(executor)))
+		m.updateChatView()
+		cmd := m.dispatchExecution(executor)
+		return m, cmd
 
-		// Send execution request to Claude (the only executor)
-		debate.AddMessage("system", "Execution requested. Sending to Claude for implementation...")
+	case commands.SetExecutor:
+		if debate == nil {
+			return m, nil
+		}
+		debate.AddMessage("system", m.setExecutor(c.Model))
 		m.updateChatView()
-		return m, m.dispatchExecutionToClaude()
+		return m, nil
 
 	case commands.Pause:
 		if debate != nil {
@@ -1214,7 +2744,28 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case commands.SetTimeout:
+		if debate == nil || m.orchestrator == nil {
+			return m, nil
+		}
+		if c.Seconds == 0 {
+			debate.AddMessage("system", fmt.Sprintf("Model timeout: %s. Change it with /timeout <seconds>.", m.orchestrator.Timeout()))
+		} else {
+			m.orchestrator.SetTimeout(time.Duration(c.Seconds) * time.Second)
+			debate.AddMessage("system", fmt.Sprintf("Model timeout set to %s, from the next round on.", m.orchestrator.Timeout()))
+		}
+		m.updateChatView()
+		return m, nil
+
+	case commands.Retry:
+		cmd := m.retryRound()
+		return m, cmd
+
 	case commands.Resume:
+		if debate != nil && debate.ReadOnly {
+			m.reactivateReadOnly(debate)
+			return m, nil
+		}
 		if debate != nil {
 			debate.Paused = false
 			debate.AwaitingUser = false
@@ -1230,7 +2781,8 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 					debate.AddMessage("system", roundMsg)
 					m.saveMessage(debate.ID, "system", roundMsg, "system")
 					m.updateChatView()
-					return m, m.dispatchToModels(discussionPrompt)
+					cmd := m.dispatchToModels(discussionPrompt)
+					return m, cmd
 				}
 			}
 
@@ -1240,50 +2792,34 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case commands.ShowHistory:
-		m.viewMode = ViewHistory
-		if m.historyState == nil {
-			m.historyState = NewHistoryState()
-		}
-		m.historyState.SetMaxHeight(m.height)
-		m.historyState.LoadDebates(m.store)
+		m.openHistory(c.Tag)
 		return m, nil
 
 	case commands.Export:
-		if debate != nil {
-			// Build export data
-			debateExport := &export.DebateExport{
-				ID:          debate.ID,
-				Name:        debate.Name,
-				ProjectPath: debate.ProjectPath,
-				CreatedAt:   debate.CreatedAt,
-			}
-
-			// Convert messages
-			for _, msg := range debate.Messages {
-				debateExport.Messages = append(debateExport.Messages, export.DebateMessage{
-					Source:    msg.Source,
-					Content:   msg.Content,
-					Timestamp: msg.Timestamp,
-				})
+		if debate != nil && c.Format == "json" {
+			cwd, _ := os.Getwd()
+			var path string
+			record, err := m.exportRecord(debate)
+			if err == nil {
+				if m.excludeContext() {
+					record = record.WithoutContext()
+				}
+				path, err = export.WriteDebateJSON(record, cwd)
 			}
-
-			// Collect context file paths
-			for path := range debate.ContextFiles {
-				debateExport.ContextFiles = append(debateExport.ContextFiles, path)
-			}
-
-			// Collect participants (unique model sources)
-			seen := make(map[string]bool)
-			for _, msg := range debate.Messages {
-				if msg.Source != "user" && msg.Source != "system" && !seen[msg.Source] {
-					debateExport.Participants = append(debateExport.Participants, msg.Source)
-					seen[msg.Source] = true
-				}
+			if err != nil {
+				debate.AddMessage("system", fmt.Sprintf("Export failed: %v", err))
+			} else {
+				debate.AddMessage("system", fmt.Sprintf("Debate exported to: %s", path))
 			}
+			m.updateChatView()
+			return m, nil
+		}
+		if debate != nil {
+			m.loadAllMessages(debate)
 
 			// Write to file in current directory
 			cwd, _ := os.Getwd()
-			path, err := export.WriteDebate(debateExport, cwd)
+			path, err := export.WriteDebate(markdownExport(debate, m.excludeContext()), cwd)
 			if err != nil {
 				debate.AddMessage("system", fmt.Sprintf("Export failed: %v", err))
 			} else {
@@ -1293,6 +2829,119 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case commands.ExportArchive:
+		if debate == nil {
+			return m, nil
+		}
+		path := expandHome(c.Path)
+		if m.store == nil {
+			debate.AddMessage("system", "Export failed: database not available")
+		} else if err := export.WriteArchive(m.store, path); err != nil {
+			debate.AddMessage("system", fmt.Sprintf("Export failed: %v", err))
+		} else {
+			debate.AddMessage("system", fmt.Sprintf("All debates exported to: %s", path))
+		}
+		m.updateChatView()
+		return m, nil
+
+	case commands.Import:
+		if debate == nil {
+			return m, nil
+		}
+		imported, err := m.importDebate(expandHome(c.Path))
+		if err != nil {
+			debate.AddMessage("system", fmt.Sprintf("Import failed: %v", err))
+			m.updateChatView()
+			return m, nil
+		}
+		m.updateConsensus(imported)
+		m.debates = append(m.debates, imported)
+		m.setActiveTab(len(m.debates) - 1)
+		imported.AddMessage("system", fmt.Sprintf("Imported debate %q as %s", imported.Name, imported.ID))
+		m.updateChatView()
+		return m, nil
+
+	case commands.Fork:
+		if debate == nil {
+			return m, nil
+		}
+		forked, n, err := m.forkDebate(debate, c.Index)
+		if err != nil {
+			debate.AddMessage("system", fmt.Sprintf("Fork failed: %v", err))
+			m.updateChatView()
+			return m, nil
+		}
+		m.updateConsensus(forked)
+		m.debates = append(m.debates, forked)
+		m.setActiveTab(len(m.debates) - 1)
+		forked.AddMessage("system", fmt.Sprintf("Forked from %q at message %d", debate.Name, n))
+		m.updateChatView()
+		return m, nil
+
+	case commands.Reload:
+		if m.roundInFlight() {
+			// Swapping the registry mid-response would orphan in-flight models
+			m.pendingReload = true
+			if debate != nil {
+				debate.AddMessage("system", "Models are still responding - config will reload when they finish.")
+				m.updateChatView()
+			}
+			return m, nil
+		}
+		return m, m.reloadConfig()
+
+	case commands.Preview:
+		if debate == nil {
+			return m, nil
+		}
+		m.loadAllMessages(debate)
+		if preview := previewPrompt(debate, m.claudePreamble(), c.Prompt, m.maxHistoryMessages()); preview == "" {
+			debate.AddMessage("system", "Nothing to preview. Use /preview <prompt>.")
+		} else {
+			debate.AddMessage("system", "=== PROMPT PREVIEW (not sent) ===\n"+preview)
+		}
+		m.updateChatView()
+		return m, nil
+
+	case commands.SetTheme:
+		if debate == nil {
+			return m, nil
+		}
+		if c.Name == "" {
+			debate.AddMessage("system", fmt.Sprintf("Themes: %s (current: %s)", strings.Join(ThemeNames(), ", "), ActiveTheme.Name))
+			m.updateChatView()
+			return m, nil
+		}
+		theme, err := LookupTheme(c.Name)
+		if err != nil {
+			debate.AddMessage("system", err.Error())
+			m.updateChatView()
+			return m, nil
+		}
+		// Keep the user's color overrides; they were validated at load
+		if m.config != nil {
+			if t, err := theme.WithColors(m.config.UI.Colors); err == nil {
+				theme = t
+			}
+		}
+		ApplyTheme(theme)
+		debate.AddMessage("system", fmt.Sprintf("Theme set to %s. Set ui.theme in the config file to keep it.", theme.Name))
+		m.updateChatView()
+		return m, nil
+
+	case commands.FilterSource:
+		if debate == nil {
+			return m, nil
+		}
+		if c.Source != "" && m.registry.Get(c.Source) == nil && !debate.hasSource(c.Source) {
+			debate.AddMessage("system", fmt.Sprintf("No messages or model named %q.", c.Source))
+			m.updateChatView()
+			return m, nil
+		}
+		m.onlySource = c.Source
+		m.updateChatView()
+		return m, nil
+
 	case commands.ParseError:
 		if debate != nil {
 			debate.AddMessage("system", fmt.Sprintf("Command error: %s\n\n%s", c.Message, commands.HelpText()))
@@ -1304,102 +2953,633 @@ func (m Model) handleCommand(cmd commands.Command) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// dispatchConsensusCheck sends the consensus prompt to all models
-func (m *Model) dispatchConsensusCheck() tea.Cmd {
-	return func() tea.Msg {
-		debate := m.activeDebate()
-		if debate == nil || m.orchestrator == nil {
-			return nil
+// reloadConfig re-reads the config file and rebuilds the registry and
+// orchestrator, reporting which models were added or removed
+func (m *Model) reloadConfig() tea.Cmd {
+	m.pendingReload = false
+	debate := m.activeDebate()
+
+	cfg, err := config.Reload()
+	if err != nil {
+		if debate != nil {
+			debate.AddMessage("system", fmt.Sprintf("Reload failed, keeping current config: %v", err))
+			m.updateChatView()
 		}
+		return nil
+	}
 
-		ctx, cancel := context.WithCancel(context.Background())
-		m.cancelDebate = cancel
+	problems := config.Validate(cfg)
+	theme, err := configTheme(cfg)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	ApplyTheme(theme)
 
-		// Convert debate messages to model messages format
-		var history []models.Message
-		for _, msg := range debate.Messages {
-			history = append(history, models.Message{
-				Source:    msg.Source,
-				Content:   msg.Content,
-				Timestamp: msg.Timestamp,
-			})
+	oldIDs := m.registry.Enabled()
+	registry := models.NewRegistry(cfg)
+	registry.SetWorkDir(m.project)
+	setModelColors(cfg, registry)
+
+	m.config = cfg
+	m.registry = registry
+	// Consensus keywords or the participation floor may have changed
+	for _, d := range m.debates {
+		m.updateConsensus(d)
+	}
+	m.orchestrator = orchestrator.NewFromConfig(cfg, registry)
+	m.health = nil
+	m.healthCh = startHealthCheck(registry)
+	m.hideContext = cfg.UI.HideContext
+	m.hideModels = cfg.UI.HideModels
+	if m.ready {
+		m.updateLayout()
+	}
+
+	if debate != nil {
+		added, removed := diffModelIDs(oldIDs, registry.Enabled())
+		msg := "Config reloaded."
+		if len(added) > 0 {
+			msg += " Added: " + strings.Join(added, ", ") + "."
 		}
+		if len(removed) > 0 {
+			msg += " Removed: " + strings.Join(removed, ", ") + "."
+		}
+		if len(added) == 0 && len(removed) == 0 {
+			msg += " Models unchanged."
+		}
+		if len(problems) > 0 {
+			msg += "\nConfig problems:\n" + formatConfigErrors(problems)
+		}
+		debate.AddMessage("system", msg)
+		m.updateChatView()
+	}
 
+	return waitForHealthCheck(m.healthCh)
+}
+
+// refreshSummary describes which models /models refresh found reachable or
+// unreachable since the previous check
+func refreshSummary(registry *models.Registry, result models.RefreshResult) string {
+	name := func(id string) string {
+		if model := registry.Get(id); model != nil {
+			return model.Info().Name
+		}
+		return id
+	}
+
+	var lines []string
+	if len(result.Recovered) > 0 {
+		names := make([]string, len(result.Recovered))
+		for i, id := range result.Recovered {
+			names[i] = name(id)
+		}
+		lines = append(lines, "Now reachable: "+strings.Join(names, ", "))
+	}
+	if len(result.Lost) > 0 {
+		lines = append(lines, "Now unreachable:")
+		for _, id := range result.Lost {
+			lines = append(lines, fmt.Sprintf("  %s: %v", name(id), result.Health[id]))
+		}
+	}
+	if len(lines) == 0 {
+		return "Models refreshed. No changes."
+	}
+	return "Models refreshed.\n" + strings.Join(lines, "\n")
+}
+
+// diffModelIDs returns the IDs present only in after (added) and only in before (removed)
+func diffModelIDs(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, id := range before {
+		inBefore[id] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, id := range after {
+		inAfter[id] = true
+		if !inBefore[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range before {
+		if !inAfter[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+// dispatchConsensusCheck sends the consensus prompt to all models
+func (m *Model) dispatchConsensusCheck() tea.Cmd {
+	debate := m.activeDebate()
+	if debate == nil || m.orchestrator == nil {
+		return nil
+	}
+
+	m.loadAllMessages(debate)
+	history := m.promptHistory(debate)
+	ctx, seq := m.startRound()
+	orch := m.orchestrator
+
+	return tea.Batch(m.startTicking(), func() tea.Msg {
 		// Use orchestrator's consensus prompt
-		responses := m.orchestrator.ConsensusPrompt(ctx, history)
-
-		// Forward responses to the UI
-		go func() {
-			for resp := range responses {
-				if program != nil {
-					program.Send(modelResponseMsg{
-						modelID:   resp.ModelID,
-						content:   resp.Content,
-						done:      resp.Done,
-						err:       resp.Error,
-						isTimeout: resp.IsTimeout,
-					})
-				}
-			}
-			if program != nil {
-				program.Send(allModelsDoneMsg{})
-			}
-		}()
+		forwardResponses(seq, orch.ConsensusPrompt(ctx, history))
+		return nil
+	})
+}
 
+// autoConsensusDue reports whether to poll the models for their positions:
+// polling is on (after > 0), rounds have gone by without consensus since the
+// last poll, and the round that just ended wasn't itself a poll
+func autoConsensusDue(after, roundsWithoutConsensus int, lastWasPoll bool) bool {
+	return after > 0 && !lastWasPoll && roundsWithoutConsensus >= after
+}
+
+// autoPollConsensus asks every model for its position after a run of rounds
+// without consensus. Returns nil if the poll couldn't be sent.
+func (m *Model) autoPollConsensus(debate *Debate) tea.Cmd {
+	m.streamingMsgs = make(map[string]int)
+	cmd := m.dispatchConsensusCheck()
+	if cmd == nil {
 		return nil
 	}
+	note := fmt.Sprintf("No consensus after %d rounds - asking every model for its position.", debate.RoundsWithoutConsensus)
+	debate.AddMessage("system", note)
+	m.saveMessage(debate.ID, "system", note, "system")
+	m.updateChatView()
+	debate.RoundsWithoutConsensus = 0
+	debate.AutoPolled = true
+	return cmd
 }
 
-// dispatchExecutionToClaude sends the execution request to Claude only
-func (m *Model) dispatchExecutionToClaude() tea.Cmd {
-	return func() tea.Msg {
-		debate := m.activeDebate()
-		if debate == nil || m.orchestrator == nil {
-			return nil
+// autoConsensusAfter returns the configured rounds before an automatic
+// consensus poll; 0 means never
+func (m *Model) autoConsensusAfter() int {
+	if m.config == nil {
+		return 0
+	}
+	return m.config.Defaults.AutoConsensusAfterRounds
+}
+
+// execCapable returns the enabled models that can execute tools, in order
+func (m *Model) execCapable() []string {
+	var ids []string
+	for _, id := range m.registry.Enabled() {
+		if model := m.registry.Get(id); model != nil && model.Info().CanExec {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// executorID returns the model /execute sends to: the one picked with
+// /executor, else defaults.executor, else the first enabled model that can
+// execute tools. Empty means no model can.
+func (m *Model) executorID() string {
+	capable := m.execCapable()
+	preferred := []string{m.executor}
+	if m.config != nil {
+		preferred = append(preferred, m.config.Defaults.Executor)
+	}
+	for _, id := range preferred {
+		if id != "" && slices.Contains(capable, id) {
+			return id
+		}
+	}
+	if len(capable) == 0 {
+		return ""
+	}
+	return capable[0]
+}
+
+// setExecutor handles /executor: an empty id describes the current
+// executor, otherwise modelID becomes the executor if it can execute tools.
+// Returns a note for the chat.
+func (m *Model) setExecutor(modelID string) string {
+	if modelID == "" {
+		if id := m.executorID(); id != "" {
+			return fmt.Sprintf("Executor: %s. Change it with /executor <model>.", formatSource(id))
 		}
+		return "No enabled model can execute tools."
+	}
+	model := m.registry.Get(modelID)
+	if model == nil {
+		return fmt.Sprintf("Unknown model %s. Available: %s", modelID, strings.Join(m.registry.Enabled(), ", "))
+	}
+	if !model.Info().CanExec {
+		capable := "none"
+		if ids := m.execCapable(); len(ids) > 0 {
+			capable = strings.Join(ids, ", ")
+		}
+		return fmt.Sprintf("%s can't execute tools, so it can't be the executor. Models that can: %s", formatSource(modelID), capable)
+	}
+	m.executor = modelID
+	return fmt.Sprintf("Executor set to %s.", formatSource(modelID))
+}
 
-		ctx, cancel := context.WithCancel(context.Background())
-		m.cancelDebate = cancel
+// dispatchExecution sends the execution request to the executor only
+func (m *Model) dispatchExecution(executor string) tea.Cmd {
+	debate := m.activeDebate()
+	if debate == nil || m.orchestrator == nil {
+		return nil
+	}
 
-		// Build execution prompt with context from debate
-		executionPrompt := `Based on the consensus reached in this debate, please implement the agreed-upon approach.
+	// Build execution prompt with context from debate
+	executionPrompt := `Based on the consensus reached in this debate, please implement the agreed-upon approach.
 
 You have execution capabilities. The other models provided advisory input, but you are the executor.
 
 Summarize what you're about to do, then proceed with implementation. If you need user confirmation for destructive operations, ask first.`
 
-		// Convert debate messages to model messages format
-		var history []models.Message
-		for _, msg := range debate.Messages {
-			history = append(history, models.Message{
-				Source:    msg.Source,
-				Content:   msg.Content,
-				Timestamp: msg.Timestamp,
-			})
+	m.loadAllMessages(debate)
+	history := m.promptHistory(debate)
+	ctx, seq := m.startRound()
+	orch := m.orchestrator
+
+	return tea.Batch(m.startTicking(), func() tea.Msg {
+		forwardResponses(seq, orch.SendToModel(ctx, executor, history, executionPrompt))
+		return nil
+	})
+}
+
+// startRound cancels any round still in flight and returns the context and
+// sequence number for a new one. Call it from Update, not from inside a
+// tea.Cmd, so the cancel func is stored on the live model.
+func (m *Model) startRound() (context.Context, int) {
+	if m.cancelDebate != nil {
+		m.cancelDebate()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelDebate = cancel
+	m.roundSeq++
+	m.round = roundDebate
+	metrics.Default.RoundDispatched()
+	return ctx, m.roundSeq
+}
+
+// contextBudget caps the bytes of context files sent with a prompt, so a
+// large /context add can't push the prompt past model limits
+const contextBudget = 256 * 1024
+
+// contextPromptFor builds a prompt per model: models that can read files get
+// the debate's context files in full, others only a summary of them.
+// Returns the paths left out of the full prompt to fit the budget.
+func contextPromptFor(debate *Debate, prompt string) (orchestrator.PromptFunc, []string) {
+	full, omitted := withContextFiles(debate, prompt)
+	summary, _ := buildContextPrompt(debate, prompt, summarizeContextEntry)
+	return func(info models.ModelInfo) string {
+		if info.CanRead {
+			return full
 		}
+		return summary
+	}, omitted
+}
 
-		// Send only to Claude
-		responses := m.orchestrator.SendToModel(ctx, "claude", history, executionPrompt)
-
-		// Forward responses to the UI
-		go func() {
-			for resp := range responses {
-				if program != nil {
-					program.Send(modelResponseMsg{
-						modelID:   resp.ModelID,
-						content:   resp.Content,
-						done:      resp.Done,
-						err:       resp.Error,
-						isTimeout: resp.IsTimeout,
-					})
-				}
-			}
-			if program != nil {
-				program.Send(allModelsDoneMsg{})
+// summarizeContextEntry stands in for a context file's contents with its
+// size, for models that can't read files. Directory listings are kept, as
+// they're summaries already.
+func summarizeContextEntry(path, content string) string {
+	if strings.HasPrefix(content, "=== Directory: ") {
+		return content
+	}
+	return fmt.Sprintf("=== File: %s (%s, contents omitted) ===\n", path, formatSize(len(content)))
+}
+
+// withContextFiles prepends the debate's context files to a prompt, in path
+// order, until contextBudget is used up. Returns the paths left out.
+func withContextFiles(debate *Debate, prompt string) (string, []string) {
+	return buildContextPrompt(debate, prompt, nil)
+}
+
+// buildContextPrompt is withContextFiles with each file's content passed
+// through transform first, if it's non-nil
+func buildContextPrompt(debate *Debate, prompt string, transform func(path, content string) string) (string, []string) {
+	if len(debate.ContextFiles) == 0 {
+		return prompt, nil
+	}
+
+	paths := make([]string, 0, len(debate.ContextFiles))
+	for path := range debate.ContextFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var contextBuilder strings.Builder
+	var omitted []string
+	used := 0
+	contextBuilder.WriteString("=== CONTEXT FILES ===\n\n")
+	for _, path := range paths {
+		// Content is already formatted by the context loader, with its own header
+		content := debate.ContextFiles[path]
+		if transform != nil {
+			content = transform(path, content)
+		}
+		if used+len(content) > contextBudget {
+			omitted = append(omitted, path)
+			continue
+		}
+		used += len(content)
+		contextBuilder.WriteString(content)
+		contextBuilder.WriteString("\n")
+	}
+	if len(omitted) > 0 {
+		contextBuilder.WriteString(fmt.Sprintf("(Omitted to fit the context budget: %s)\n\n", strings.Join(omitted, ", ")))
+	}
+	contextBuilder.WriteString("=== END CONTEXT ===\n\n")
+	contextBuilder.WriteString("User question:\n")
+	contextBuilder.WriteString(prompt)
+	return contextBuilder.String(), omitted
+}
+
+// previewPrompt builds the prompt Claude receives for a user prompt, mirroring
+// dispatchToModels and ClaudeModel.Send. An empty prompt previews the last user
+// prompt as it was sent; otherwise prompt is previewed as if sent now.
+func previewPrompt(debate *Debate, preamble, prompt string, maxHistory int) string {
+	history := debateHistory(debate)
+	if prompt == "" {
+		// Rebuild the history as it stood when the last user prompt was sent
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Source == "user" {
+				prompt = history[i].Content
+				history = history[:i+1]
+				break
 			}
-		}()
+		}
+		if prompt == "" {
+			return ""
+		}
+	} else {
+		history = append(history, models.Message{Source: "user", Content: prompt})
+	}
+	fullPrompt, _ := withContextFiles(debate, prompt)
+	return models.BuildPrompt(preamble, trimHistory(history, maxHistory), fullPrompt)
+}
+
+// claudePreamble returns the preamble Claude is configured with
+func (m *Model) claudePreamble() string {
+	if p, ok := m.registry.Get("claude").(interface{ Preamble() string }); ok {
+		return p.Preamble()
+	}
+	return models.DefaultPreamble
+}
 
+// startTicking schedules the tick loop unless it's already running
+func (m *Model) startTicking() tea.Cmd {
+	if m.ticking {
 		return nil
 	}
+	m.ticking = true
+	return tick()
+}
+
+// tick schedules the next tickMsg
+func tick() tea.Cmd {
+	return tea.Tick(tickInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// roundInFlight reports whether models are working on a dispatched round
+func (m *Model) roundInFlight() bool {
+	return m.cancelDebate != nil
+}
+
+// cancelRound stops the in-flight round, keeping whatever the models said so far.
+// Late responses from the cancelled round are dropped.
+func (m *Model) cancelRound() tea.Cmd {
+	if m.cancelDebate != nil {
+		m.cancelDebate()
+		m.cancelDebate = nil
+	}
+	m.roundSeq++
+	if m.orchestrator != nil {
+		m.orchestrator.StopAll()
+	}
+
+	// Persist partial responses so the transcript matches the screen
+	m.flushStreaming()
+
+	if debate := m.activeDebate(); debate != nil {
+		for modelID, status := range debate.ModelStatus {
+			if status == models.StatusResponding || status == models.StatusWaiting {
+				debate.UpdateModelStatus(modelID, models.StatusIdle)
+			}
+		}
+		debate.AwaitingUser = true
+		debate.AddMessage("system", "Round cancelled.")
+		m.saveMessage(debate.ID, "system", "Round cancelled.", "system")
+	}
+	m.updateChatView()
+
+	if m.pendingReload {
+		return m.reloadConfig()
+	}
+	return nil
+}
+
+// flushTimeout bounds how long flushStreaming spends saving on the way out
+const flushTimeout = 500 * time.Millisecond
+
+// flushStreaming saves responses still streaming into the active debate as
+// they stand and stops tracking them, so a quit or cancel doesn't lose them.
+// Best effort: messages not saved within flushTimeout are dropped.
+func (m *Model) flushStreaming() {
+	debate := m.activeDebate()
+	if debate != nil && len(m.streamingMsgs) > 0 {
+		deadline := time.Now().Add(flushTimeout)
+		for _, idx := range m.streamingMsgs {
+			if time.Now().After(deadline) {
+				break
+			}
+			if idx >= len(debate.Messages) {
+				continue
+			}
+			msgType := "model"
+			if debate.Messages[idx].Source == moderatorSource {
+				msgType = moderatorSource
+			}
+			m.saveStreamed(debate, idx, msgType)
+		}
+	}
+	m.streamingMsgs = make(map[string]int)
+}
+
+// debateHistory converts debate messages to the models' message format
+func debateHistory(debate *Debate) []models.Message {
+	var history []models.Message
+	for _, msg := range debate.Messages {
+		history = append(history, models.Message{
+			Source:    msg.Source,
+			Content:   msg.Content,
+			Timestamp: msg.Timestamp,
+		})
+	}
+	return history
+}
+
+// expandHome replaces a leading "~/" in path with the user's home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// elidedMarker stands in for messages trimHistory drops
+const elidedMarker = "[…earlier discussion elided…]"
+
+// trimHistory keeps the first user prompt, system messages, and the most
+// recent max messages, replacing each run of dropped messages with
+// elidedMarker. max <= 0 keeps everything.
+func trimHistory(history []models.Message, max int) []models.Message {
+	if max <= 0 || len(history) <= max {
+		return history
+	}
+
+	firstUser := -1
+	for i, msg := range history {
+		if msg.Source == "user" {
+			firstUser = i
+			break
+		}
+	}
+
+	cut := len(history) - max
+	var trimmed []models.Message
+	dropping := false
+	for i, msg := range history[:cut] {
+		if i == firstUser || msg.Source == "system" {
+			trimmed = append(trimmed, msg)
+			dropping = false
+			continue
+		}
+		if !dropping {
+			trimmed = append(trimmed, models.Message{Source: "system", Content: elidedMarker})
+			dropping = true
+		}
+	}
+	return append(trimmed, history[cut:]...)
+}
+
+// promptHistory returns the debate history to send to models, trimmed to
+// the configured window
+func (m *Model) promptHistory(debate *Debate) []models.Message {
+	return trimHistory(debateHistory(debate), m.maxHistoryMessages())
+}
+
+// maxHistoryMessages returns the configured history window; 0 means unlimited
+func (m *Model) maxHistoryMessages() int {
+	if m.config == nil {
+		return 0
+	}
+	return m.config.Defaults.MaxHistoryMessages
 }
 
+// forwardResponses relays orchestrator responses to the UI as tea messages,
+// coalescing streamed text, then signals allModelsDoneMsg once the channel
+// closes
+func forwardResponses(seq int, responses <-chan orchestrator.Response) {
+	for resp := range coalesceResponses(responses, coalesceWindow) {
+		if program != nil {
+			program.Send(modelResponseMsg{
+				seq:       seq,
+				modelID:   resp.ModelID,
+				content:   resp.Content,
+				done:      resp.Done,
+				started:   resp.Started,
+				err:       resp.Error,
+				isTimeout: resp.IsTimeout,
+				usage:     resp.Usage,
+				stopped:   resp.Stopped,
+			})
+		}
+	}
+	if program != nil {
+		program.Send(allModelsDoneMsg{seq: seq})
+	}
+}
+
+// markdownExport builds the data for a Markdown export of debate, leaving
+// out context notes if excludeContext is set
+func markdownExport(debate *Debate, excludeContext bool) *export.DebateExport {
+	debateExport := &export.DebateExport{
+		ID:          debate.ID,
+		Name:        debate.Name,
+		ProjectPath: debate.ProjectPath,
+		CreatedAt:   debate.CreatedAt,
+	}
+
+	// Convert messages
+	for _, msg := range debate.Messages {
+		if excludeContext && msg.MsgType == db.MsgTypeContext {
+			continue
+		}
+		debateExport.Messages = append(debateExport.Messages, export.DebateMessage{
+			Source:    msg.Source,
+			Content:   msg.Content,
+			Timestamp: msg.Timestamp,
+		})
+	}
+
+	// Collect context file paths
+	for path := range debate.ContextFiles {
+		debateExport.ContextFiles = append(debateExport.ContextFiles, path)
+	}
+
+	// Collect participants (unique model sources)
+	seen := make(map[string]bool)
+	for _, msg := range debate.Messages {
+		if msg.Source != "user" && msg.Source != "system" && !seen[msg.Source] {
+			debateExport.Participants = append(debateExport.Participants, msg.Source)
+			seen[msg.Source] = true
+		}
+	}
+	return debateExport
+}
+
+// excludeContext reports whether exports leave out context notes
+func (m *Model) excludeContext() bool {
+	return m.config != nil && m.config.Export.ExcludeContext
+}
+
+// contextNote adds and saves a system note about the debate's injected
+// context, typed so exports can leave it out
+func (m *Model) contextNote(debate *Debate, note string) {
+	debate.Messages = append(debate.Messages, DebateMessage{
+		Source:    "system",
+		Content:   note,
+		Timestamp: time.Now(),
+		Round:     debate.Round,
+		MsgType:   db.MsgTypeContext,
+	})
+	m.saveMessage(debate.ID, "system", note, db.MsgTypeContext)
+}
+
+// exportRecord reads the current state of debate from the store for a JSON export
+func (m *Model) exportRecord(debate *Debate) (*db.ExportedDebate, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	return m.store.ExportDebate(debate.ID)
+}
+
+// importDebate stores the JSON export at path as a new debate and opens it
+func (m *Model) importDebate(path string) (*Debate, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	record, err := db.ParseExportedDebate(data)
+	if err != nil {
+		return nil, err
+	}
+	id, err := m.store.ImportDebate(*record)
+	if err != nil {
+		return nil, err
+	}
+	return ResumeDebate(m.store, id)
+}
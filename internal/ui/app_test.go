@@ -0,0 +1,1542 @@
+// internal/ui/app_test.go
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"roundtable/internal/commands"
+	"roundtable/internal/config"
+	"roundtable/internal/consensus"
+	ctxloader "roundtable/internal/context"
+	"roundtable/internal/db"
+	"roundtable/internal/export"
+	"roundtable/internal/models"
+	"roundtable/internal/orchestrator"
+)
+
+// newTestModel creates a Model with one debate, no store, and an empty registry
+func newTestModel() Model {
+	registry := models.NewRegistry(&config.Config{})
+	return Model{
+		registry:      registry,
+		orchestrator:  orchestrator.New(registry, time.Second),
+		input:         textarea.New(),
+		debates:       []*Debate{NewDebate("test", "Test")},
+		streamingMsgs: make(map[string]int),
+	}
+}
+
+func TestCancelRound(t *testing.T) {
+	m := newTestModel()
+	ctx, seq := m.startRound()
+
+	// A model starts streaming
+	updated, _ := m.Update(modelResponseMsg{seq: seq, modelID: "claude", content: "partial answer"})
+	m = updated.(Model)
+	debate := m.activeDebate()
+	if debate.ModelStatus["claude"] != models.StatusResponding {
+		t.Fatalf("expected claude responding, got %v", debate.ModelStatus["claude"])
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = updated.(Model)
+	debate = m.activeDebate()
+
+	if ctx.Err() == nil {
+		t.Error("expected round context to be cancelled")
+	}
+	if m.roundInFlight() {
+		t.Error("expected no round in flight after cancel")
+	}
+	if len(m.streamingMsgs) != 0 {
+		t.Errorf("expected streaming state cleared, got %v", m.streamingMsgs)
+	}
+	if debate.ModelStatus["claude"] != models.StatusIdle {
+		t.Errorf("expected claude idle after cancel, got %v", debate.ModelStatus["claude"])
+	}
+	last := debate.Messages[len(debate.Messages)-1]
+	if last.Source != "system" || last.Content != "Round cancelled." {
+		t.Errorf("expected round cancelled message, got %+v", last)
+	}
+	if debate.Messages[0].Content != "partial answer" {
+		t.Errorf("expected partial response to be kept, got %q", debate.Messages[0].Content)
+	}
+
+	// Late responses from the cancelled round are dropped
+	count := len(debate.Messages)
+	updated, _ = m.Update(modelResponseMsg{seq: seq, modelID: "gpt", content: "too late"})
+	m = updated.(Model)
+	updated, _ = m.Update(allModelsDoneMsg{seq: seq})
+	m = updated.(Model)
+	if got := len(m.activeDebate().Messages); got != count {
+		t.Errorf("expected stale responses to be ignored, message count went from %d to %d", count, got)
+	}
+}
+
+func TestQuit_FlushesStreamingMessages(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("db.OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	m := newTestModel()
+	m.store = store
+	store.CreateDebate("test", "Test", "")
+	ctx, seq := m.startRound()
+
+	updated, _ := m.Update(modelResponseMsg{seq: seq, modelID: "claude", content: "half of an "})
+	m = updated.(Model)
+	updated, _ = m.Update(modelResponseMsg{seq: seq, modelID: "claude", content: "answer"})
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = updated.(Model)
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("expected ctrl+c to quit")
+	}
+	if ctx.Err() == nil {
+		t.Error("expected the round to be cancelled")
+	}
+	if len(m.streamingMsgs) != 0 {
+		t.Errorf("expected streaming state cleared, got %v", m.streamingMsgs)
+	}
+
+	saved, err := store.GetMessages("test")
+	if err != nil {
+		t.Fatalf("GetMessages() failed: %v", err)
+	}
+	if len(saved) != 1 || saved[0].Source != "claude" || saved[0].Content != "half of an answer" || saved[0].MsgType != "model" {
+		t.Errorf("expected the partial response to be saved, got %+v", saved)
+	}
+}
+
+func TestCancelRound_NothingInFlight(t *testing.T) {
+	m := newTestModel()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = updated.(Model)
+
+	if got := len(m.activeDebate().Messages); got != 0 {
+		t.Errorf("expected no messages when nothing is running, got %d", got)
+	}
+}
+
+func TestTick_RunsWhileRoundInFlight(t *testing.T) {
+	m := newTestModel()
+
+	cmd := m.dispatchToModels("hello")
+	if cmd == nil {
+		t.Fatal("expected dispatch command")
+	}
+	if !m.ticking {
+		t.Fatal("expected tick loop to start on dispatch")
+	}
+
+	// A second dispatch doesn't start a second loop
+	if m.startTicking() != nil {
+		t.Error("expected no second tick loop while one is running")
+	}
+
+	// Ticks keep going and advance the animation while models respond
+	frame := m.activeDebate().AnimationFrame
+	updated, next := m.Update(tickMsg(time.Now()))
+	m = updated.(Model)
+	if next == nil {
+		t.Error("expected another tick while the round is running")
+	}
+	if m.activeDebate().AnimationFrame == frame {
+		t.Error("expected tick to advance the animation frame")
+	}
+
+	// Once all models are done, the next tick stops the loop
+	updated, _ = m.Update(allModelsDoneMsg{seq: m.roundSeq})
+	m = updated.(Model)
+	updated, next = m.Update(tickMsg(time.Now()))
+	m = updated.(Model)
+	if next != nil {
+		t.Error("expected tick loop to stop after all models are done")
+	}
+	if m.ticking {
+		t.Error("expected ticking to be cleared")
+	}
+}
+
+func TestPreviewPrompt(t *testing.T) {
+	d := NewDebate("test", "Test")
+	d.ContextFiles["main.go"] = ctxloader.FormatForContext("main.go", "package main")
+	d.AddMessage("user", "First question")
+	d.AddMessage("claude", "First answer")
+
+	// Previewing a new prompt includes context files and the prompt as if just sent
+	got := previewPrompt(d, models.DefaultPreamble, "Follow-up", 0)
+	for _, want := range []string{"[claude]: First answer", "[user]: Follow-up", "=== File: main.go ===", "User question:\nFollow-up"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+
+	// An empty prompt previews the last user prompt with history as it was then
+	got = previewPrompt(d, models.DefaultPreamble, "", 0)
+	if strings.Contains(got, "First answer") {
+		t.Errorf("last-prompt preview should not include later responses:\n%s", got)
+	}
+	if !strings.Contains(got, "User question:\nFirst question") {
+		t.Errorf("expected last user prompt in preview:\n%s", got)
+	}
+
+	if previewPrompt(NewDebate("empty", "Empty"), models.DefaultPreamble, "", 0) != "" {
+		t.Error("expected empty preview for a debate with no user prompt")
+	}
+}
+
+func TestDispatchToModels_SendsContextFiles(t *testing.T) {
+	reqFile := filepath.Join(t.TempDir(), "request.json")
+	script := filepath.Join(t.TempDir(), "mock.sh")
+	body := "#!/bin/sh\ncat > " + reqFile + "\necho '{\"text\":\"ok\",\"done\":true}'\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Models.Exec = []config.ExecModelConfig{{ID: "mock", Enabled: true, Command: script}}
+	registry := models.NewRegistry(cfg)
+
+	m := newTestModel()
+	m.registry = registry
+	m.orchestrator = orchestrator.New(registry, 5*time.Second)
+	debate := m.activeDebate()
+	debate.ContextFiles["schema.sql"] = ctxloader.FormatForContext("schema.sql", "CREATE TABLE widgets (id INT);")
+	debate.ContextFiles["huge.txt"] = strings.Repeat("x", contextBudget+1)
+
+	runCmd(m.dispatchToModels("Is this schema OK?"))
+
+	data, err := os.ReadFile(reqFile)
+	if err != nil {
+		t.Fatalf("mock model never received a request: %v", err)
+	}
+	var req models.ExecRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("bad request %s: %v", data, err)
+	}
+	for _, want := range []string{"=== File: schema.sql ===", "CREATE TABLE widgets (id INT);", "User question:\nIs this schema OK?"} {
+		if !strings.Contains(req.Prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, req.Prompt)
+		}
+	}
+	if strings.Contains(req.Prompt, "xxxx") {
+		t.Error("context over budget should not be sent")
+	}
+	last := debate.Messages[len(debate.Messages)-1]
+	if last.Source != "system" || !strings.Contains(last.Content, "huge.txt") {
+		t.Errorf("expected a note about the omitted file, got %+v", last)
+	}
+}
+
+func TestMentionTarget(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantModel  string
+		wantPrompt string
+		wantOK     bool
+	}{
+		{"@gemini why did you object?", "gemini", "why did you object?", true},
+		{"  @GPT   expand on that  ", "gpt", "expand on that", true},
+		{"@claude", "claude", "", true},
+		{"what about @gemini?", "", "", false},
+		{"plain question", "", "", false},
+	}
+
+	for _, tt := range tests {
+		model, prompt, ok := mentionTarget(tt.input)
+		if model != tt.wantModel || prompt != tt.wantPrompt || ok != tt.wantOK {
+			t.Errorf("mentionTarget(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				tt.input, model, prompt, ok, tt.wantModel, tt.wantPrompt, tt.wantOK)
+		}
+	}
+}
+
+func TestAskModel(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Models.Exec = []config.ExecModelConfig{{ID: "mock", Enabled: true, Command: "true"}}
+	registry := models.NewRegistry(cfg)
+
+	m := newTestModel()
+	m.registry = registry
+	m.orchestrator = orchestrator.New(registry, time.Second)
+	debate := m.activeDebate()
+
+	// Unknown models get a hint instead of a round
+	if cmd := m.askModel("@gemini why?", "gemini", "why?"); cmd != nil {
+		t.Error("expected no dispatch for an unknown model")
+	}
+	last := debate.Messages[len(debate.Messages)-1]
+	if last.Source != "system" || !strings.Contains(last.Content, "Unknown model @gemini") || !strings.Contains(last.Content, "@mock") {
+		t.Errorf("expected an unknown-model hint listing @mock, got %+v", last)
+	}
+	if m.roundInFlight() {
+		t.Error("no round should start for an unknown model")
+	}
+
+	// A known model gets a direct round that doesn't trigger a discussion
+	if cmd := m.askModel("@mock why?", "mock", "why?"); cmd == nil {
+		t.Fatal("expected a dispatch to the mentioned model")
+	}
+	if m.round != roundDirect {
+		t.Error("expected the round to be marked as a direct question")
+	}
+	count := len(debate.Messages)
+	updated, _ := m.Update(allModelsDoneMsg{seq: m.roundSeq})
+	m = updated.(Model)
+	if m.round != roundDebate || !m.activeDebate().AwaitingUser {
+		t.Errorf("expected direct round finished and awaiting user, got round=%v", m.round)
+	}
+	if got := len(m.activeDebate().Messages); got != count {
+		t.Errorf("a direct question shouldn't add round messages, got %d new", got-count)
+	}
+}
+
+func TestSummaryDue(t *testing.T) {
+	round := func(sources ...string) *Debate {
+		d := NewDebate("test", "Test")
+		d.AddMessage("user", "Question")
+		for _, src := range sources {
+			d.AddMessage(src, "answer from "+src)
+		}
+		return d
+	}
+	failed := round("claude")
+	failed.AddErrorMessage("gemini", "boom", false)
+
+	tests := []struct {
+		name    string
+		debate  *Debate
+		enabled bool
+		want    bool
+	}{
+		{"two models responded", round("claude", "gemini"), true, true},
+		{"disabled", round("claude", "gemini"), false, false},
+		{"one model responded", round("claude"), true, false},
+		{"errors don't count", failed, true, false},
+		{"system and moderator don't count", round("claude", "system", moderatorSource), true, false},
+	}
+	for _, tt := range tests {
+		if got := summaryDue(tt.debate, tt.enabled); got != tt.want {
+			t.Errorf("%s: summaryDue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAllModelsDone_DispatchesSummary(t *testing.T) {
+	cfg := config.Default()
+	cfg.Models.Exec = []config.ExecModelConfig{{ID: "mock", Enabled: true, Command: "true"}}
+	cfg.Defaults.AutoSummarize = true
+	cfg.Defaults.Moderator = "mock"
+	registry := models.NewRegistry(cfg)
+
+	m := newTestModel()
+	m.config = cfg
+	m.registry = registry
+	m.orchestrator = orchestrator.New(registry, time.Second)
+	debate := m.activeDebate()
+	debate.AddMessage("user", "Question")
+	debate.AddMessage("claude", "AGREE: yes")
+	debate.AddMessage("gemini", "OBJECT: no")
+
+	_, seq := m.startRound()
+	updated, cmd := m.Update(allModelsDoneMsg{seq: seq})
+	m = updated.(Model)
+	if cmd == nil || m.round != roundSummary {
+		t.Fatalf("expected a summary round, got round=%v", m.round)
+	}
+
+	// The summary streams in as the moderator and isn't a position
+	updated, _ = m.Update(modelResponseMsg{seq: m.roundSeq, modelID: "mock", content: "They disagree.", done: true})
+	m = updated.(Model)
+	last := m.activeDebate().Messages[len(m.activeDebate().Messages)-1]
+	if last.Source != moderatorSource {
+		t.Errorf("summary source = %q, want %q", last.Source, moderatorSource)
+	}
+	if _, ok := latestRoundPositions(m.activeDebate(), m.consensusParser())[moderatorSource]; ok {
+		t.Error("the moderator's summary shouldn't count as a position")
+	}
+
+	// Once the summary is in, the debate continues as usual
+	updated, _ = m.Update(allModelsDoneMsg{seq: m.roundSeq})
+	m = updated.(Model)
+	if m.round != roundDebate {
+		t.Errorf("expected a debate round after the summary, got %v", m.round)
+	}
+	last = m.activeDebate().Messages[len(m.activeDebate().Messages)-1]
+	if !strings.Contains(last.Content, "Discussion Round 1") {
+		t.Errorf("expected the discussion to continue after the summary, last message %+v", last)
+	}
+}
+
+func TestRoundResults(t *testing.T) {
+	tests := []struct {
+		name          string
+		build         func(d *Debate)
+		wantSucceeded int
+		wantFailed    int
+		wantAllFailed bool
+	}{
+		{"no responses", func(d *Debate) {}, 0, 0, false},
+		{"mixed", func(d *Debate) {
+			d.AddMessage("claude", "AGREE: yes")
+			d.AddErrorMessage("gpt", "request timed out", true)
+		}, 1, 1, false},
+		{"all fail", func(d *Debate) {
+			d.AddErrorMessage("claude", "request timed out", true)
+			d.AddErrorMessage("gpt", "API error 500", false)
+			d.AddMessage("system", "note")
+		}, 0, 2, true},
+		{"earlier round succeeded", func(d *Debate) {
+			d.AddMessage("claude", "AGREE: yes")
+			d.AddMessage("user", "Follow-up")
+			d.AddErrorMessage("claude", "request timed out", true)
+		}, 0, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDebate("d", "D")
+			d.AddMessage("user", "Question")
+			tt.build(d)
+			succeeded, failed := roundResults(d)
+			if succeeded != tt.wantSucceeded || failed != tt.wantFailed {
+				t.Errorf("roundResults() = %d, %d; want %d, %d", succeeded, failed, tt.wantSucceeded, tt.wantFailed)
+			}
+			if got := allFailed(d); got != tt.wantAllFailed {
+				t.Errorf("allFailed() = %v, want %v", got, tt.wantAllFailed)
+			}
+		})
+	}
+}
+
+func TestAllModelsDone_AllFailedOffersRetryOnce(t *testing.T) {
+	m := newTestModel()
+	debate := m.activeDebate()
+	debate.AddMessage("user", "Question")
+	m.lastRoundPrompt = "Question"
+	debate.AddErrorMessage("claude", "request timed out", true)
+	debate.AddErrorMessage("gpt", "API error 500", false)
+
+	_, seq := m.startRound()
+	updated, _ := m.Update(allModelsDoneMsg{seq: seq})
+	m = updated.(Model)
+	last := debate.Messages[len(debate.Messages)-1]
+	if !strings.Contains(last.Content, "/retry") {
+		t.Errorf("last message = %q, want guidance mentioning /retry", last.Content)
+	}
+	if m.confirm == nil {
+		t.Fatal("expected an offer to retry the round")
+	}
+
+	// Accepting retries the round; if that fails too, no second offer
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if !m.roundInFlight() {
+		t.Fatal("accepting the offer should start a new round")
+	}
+	debate.AddErrorMessage("claude", "request timed out", true)
+	updated, _ = m.Update(allModelsDoneMsg{seq: m.roundSeq})
+	m = updated.(Model)
+	if m.confirm != nil {
+		t.Error("a failed retry shouldn't offer another retry")
+	}
+}
+
+func TestAutoConsensusDue(t *testing.T) {
+	tests := []struct {
+		name        string
+		after       int
+		rounds      int
+		lastWasPoll bool
+		want        bool
+	}{
+		{"disabled", 0, 5, false, false},
+		{"too few rounds", 3, 2, false, false},
+		{"enough rounds", 3, 3, false, true},
+		{"more than enough", 2, 4, false, true},
+		{"just polled", 1, 1, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := autoConsensusDue(tt.after, tt.rounds, tt.lastWasPoll); got != tt.want {
+				t.Errorf("autoConsensusDue(%d, %d, %v) = %v, want %v", tt.after, tt.rounds, tt.lastWasPoll, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllModelsDone_AutoConsensusPoll(t *testing.T) {
+	m := newTestModel()
+	m.config = &config.Config{}
+	m.config.Defaults.AutoConsensusAfterRounds = 2
+	debate := m.activeDebate()
+	debate.MaxRounds = 0 // No auto-discussion; only the poll starts rounds
+	debate.AddMessage("user", "Which cache should we use?")
+	debate.AddMessage("claude", "OBJECT: memcached is simpler")
+
+	finishRound := func() {
+		t.Helper()
+		_, seq := m.startRound()
+		updated, _ := m.Update(allModelsDoneMsg{seq: seq})
+		m = updated.(Model)
+	}
+
+	finishRound()
+	if debate.AutoPolled || debate.RoundsWithoutConsensus != 1 {
+		t.Fatalf("after one round: polled=%v rounds=%d, want no poll yet", debate.AutoPolled, debate.RoundsWithoutConsensus)
+	}
+
+	finishRound()
+	if !debate.AutoPolled || !m.roundInFlight() {
+		t.Fatal("a second round without consensus should start a poll")
+	}
+	if last := debate.Messages[len(debate.Messages)-1]; !strings.Contains(last.Content, "No consensus after 2 rounds") {
+		t.Errorf("last message = %q, want the poll announced", last.Content)
+	}
+
+	// The poll's own round doesn't count or trigger another poll
+	updated, _ := m.Update(allModelsDoneMsg{seq: m.roundSeq})
+	m = updated.(Model)
+	if debate.AutoPolled || debate.RoundsWithoutConsensus != 0 || m.roundInFlight() {
+		t.Errorf("after the poll: polled=%v rounds=%d inFlight=%v", debate.AutoPolled, debate.RoundsWithoutConsensus, m.roundInFlight())
+	}
+}
+
+func TestMarkdownExport_ExcludeContext(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("db.OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	m := newTestModel()
+	m.store = store
+	debate := m.activeDebate()
+	store.CreateDebate(debate.ID, debate.Name, "")
+	debate.AddMessage("user", "Question")
+	m.contextNote(debate, "Added context: main.go")
+	debate.AddMessage("claude", "AGREE: yes")
+	debate.AddMessage("system", "=== Discussion Round 1 of 3 ===")
+
+	contents := func(e *export.DebateExport) []string {
+		var out []string
+		for _, msg := range e.Messages {
+			out = append(out, msg.Content)
+		}
+		return out
+	}
+	if got := contents(markdownExport(debate, false)); len(got) != 4 {
+		t.Errorf("full export = %q, want all 4 messages", got)
+	}
+	got := contents(markdownExport(debate, true))
+	want := []string{"Question", "AGREE: yes", "=== Discussion Round 1 of 3 ==="}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("export without context = %q, want %q", got, want)
+	}
+
+	// The note is stored with its type, so resumed debates filter it too
+	resumed, err := ResumeDebate(store, debate.ID)
+	if err != nil {
+		t.Fatalf("ResumeDebate() failed: %v", err)
+	}
+	if len(resumed.Messages) != 1 || resumed.Messages[0].MsgType != db.MsgTypeContext {
+		t.Errorf("resumed messages = %+v, want the stored context note", resumed.Messages)
+	}
+}
+
+func TestCheckConsensus_TalliesWithoutPolling(t *testing.T) {
+	m := newTestModel()
+	debate := m.activeDebate()
+	debate.AddMessage("user", "Question")
+	debate.AddMessage("claude", "AGREE: yes")
+	debate.AddMessage("gemini", "OBJECT: no")
+	before := len(debate.Messages)
+
+	updated, cmd := m.handleCommand(commands.CheckConsensus{})
+	m = updated.(Model)
+	if cmd != nil || m.roundInFlight() {
+		t.Fatal("a local consensus check shouldn't dispatch to models")
+	}
+	if len(debate.Messages) != before+1 {
+		t.Fatalf("expected one tally message, got %d new messages", len(debate.Messages)-before)
+	}
+	last := debate.Messages[len(debate.Messages)-1]
+	if last.Source != "system" || !strings.Contains(last.Content, "1 agree, 1 object") {
+		t.Errorf("tally message = %+v, want a system message with 1 agree, 1 object", last)
+	}
+}
+
+func TestConsensusMeter(t *testing.T) {
+	tests := []struct {
+		name   string
+		result consensus.ConsensusResult
+		want   string
+	}{
+		{"no responses", consensus.ConsensusResult{}, ""},
+		{"mixed", consensus.ConsensusResult{AgreeCount: 3, UnknownCount: 1, TotalCount: 4}, "▇▇▇░ 3 agree / 1 unknown / 0 object"},
+		{"all agree", consensus.ConsensusResult{AgreeCount: 2, TotalCount: 2}, "▇▇ 2 agree / 0 unknown / 0 object"},
+		{"none agree", consensus.ConsensusResult{ObjectCount: 2, TotalCount: 2}, "░░ 0 agree / 0 unknown / 2 object"},
+		{"with additions", consensus.ConsensusResult{AgreeCount: 1, AddCount: 1, TotalCount: 2}, "▇░ 1 agree / 0 unknown / 0 object / 1 add"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := consensusMeter(tt.result); got != tt.want {
+				t.Errorf("consensusMeter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusBar_ShowsConsensusMeter(t *testing.T) {
+	m := newTestModel()
+	m.width = 120
+	debate := m.activeDebate()
+	debate.AddMessage("user", "Question")
+	if strings.Contains(m.renderStatusBar(), "agree") {
+		t.Error("status bar shows a meter before any model responded")
+	}
+
+	_, seq := m.startRound()
+	debate.AddMessage("claude", "AGREE: yes")
+	debate.AddMessage("gemini", "OBJECT: no")
+	if strings.Contains(m.renderStatusBar(), "agree") {
+		t.Error("status bar analyzed a round still in flight")
+	}
+
+	updated, _ := m.Update(allModelsDoneMsg{seq: seq})
+	m = updated.(Model)
+	if bar := m.renderStatusBar(); !strings.Contains(bar, "1 agree / 0 unknown / 1 object") {
+		t.Errorf("status bar = %q, want the consensus meter", bar)
+	}
+
+	// A new prompt starts a new round with no positions yet
+	m.activeDebate().AddMessage("user", "Follow-up")
+	if strings.Contains(m.renderStatusBar(), "agree") {
+		t.Error("status bar still shows the previous round's meter")
+	}
+}
+
+// runCmd runs a command and any commands it batches, discarding messages
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmd(c)
+		}
+	}
+}
+
+func TestTrimHistory(t *testing.T) {
+	msg := func(source, content string) models.Message {
+		return models.Message{Source: source, Content: content}
+	}
+	history := []models.Message{
+		msg("user", "original question"),
+		msg("claude", "a1"),
+		msg("gemini", "b1"),
+		msg("system", "Added context: main.go"),
+		msg("claude", "a2"),
+		msg("gemini", "b2"),
+		msg("user", "follow-up"),
+		msg("claude", "a3"),
+		msg("gemini", "b3"),
+	}
+
+	got := trimHistory(history, 3)
+	want := []string{
+		"original question",
+		elidedMarker,
+		"Added context: main.go",
+		elidedMarker,
+		"follow-up",
+		"a3",
+		"b3",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("trimHistory() kept %d messages %+v, want %d", len(got), got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Content != w {
+			t.Errorf("message %d = %q, want %q", i, got[i].Content, w)
+		}
+	}
+
+	if got := trimHistory(history, 0); len(got) != len(history) {
+		t.Errorf("max 0 should keep everything, got %d messages", len(got))
+	}
+	if got := trimHistory(history, len(history)); len(got) != len(history) {
+		t.Errorf("a window as big as the history should keep everything, got %d messages", len(got))
+	}
+}
+
+func TestTogglePane_ChatGrowsAndChoiceIsSaved(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	m := newTestModel()
+	m.config = config.Default()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m = updated.(Model)
+	before := m.panes.Chat
+
+	m.focus = FocusContext
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	m = updated.(Model)
+
+	if !m.hideContext || m.panes.Context != 0 {
+		t.Fatalf("expected context pane hidden, got hideContext=%v panes=%+v", m.hideContext, m.panes)
+	}
+	if m.panes.Chat <= before {
+		t.Errorf("chat width = %d after hiding context, want more than %d", m.panes.Chat, before)
+	}
+	if m.focus != FocusInput {
+		t.Errorf("focus should leave the hidden pane, got %v", m.focus)
+	}
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !saved.UI.HideContext || saved.UI.HideModels {
+		t.Errorf("saved UI = %+v, want only HideContext", saved.UI)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	m = updated.(Model)
+	if m.hideContext || m.panes.Chat != before {
+		t.Errorf("expected layout restored, got hideContext=%v chat=%d", m.hideContext, m.panes.Chat)
+	}
+}
+
+func TestModelsPane_Reorder(t *testing.T) {
+	cfg := &config.Config{}
+	for _, id := range []string{"a", "b", "c"} {
+		cfg.Models.Exec = append(cfg.Models.Exec, config.ExecModelConfig{ID: id, Enabled: true, Command: "true"})
+	}
+	m := newTestModel()
+	m.registry = models.NewRegistry(cfg)
+	m.focus = FocusModels
+
+	for _, key := range []tea.KeyType{tea.KeyDown, tea.KeyShiftDown, tea.KeyShiftUp, tea.KeyShiftUp, tea.KeyShiftUp} {
+		updated, _ := m.Update(tea.KeyMsg{Type: key})
+		m = updated.(Model)
+	}
+	if got := m.registry.Enabled(); !slices.Equal(got, []string{"b", "a", "c"}) || m.modelCursor != 0 {
+		t.Errorf("order = %v, cursor = %d; want [b a c] with b selected", got, m.modelCursor)
+	}
+}
+
+func TestCheckpointStreaming(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	m := newTestModel()
+	m.store = store
+	store.CreateDebate("test", "Test", "")
+	_, seq := m.startRound()
+
+	updated, _ := m.Update(modelResponseMsg{seq: seq, modelID: "claude", content: "Half an "})
+	m = updated.(Model)
+	m.checkpointStreaming()
+	if drafts, _ := store.GetDrafts(); len(drafts) != 1 || drafts[0].Content != "Half an " {
+		t.Fatalf("expected the partial response saved as a draft, got %+v", drafts)
+	}
+
+	updated, _ = m.Update(modelResponseMsg{seq: seq, modelID: "claude", content: "answer."})
+	m = updated.(Model)
+	m.checkpointStreaming()
+	updated, _ = m.Update(modelResponseMsg{seq: seq, modelID: "claude", done: true})
+	m = updated.(Model)
+
+	if drafts, _ := store.GetDrafts(); len(drafts) != 0 {
+		t.Errorf("expected the draft finalized, got %+v", drafts)
+	}
+	messages, _ := store.GetMessages("test")
+	if len(messages) != 1 || messages[0].Content != "Half an answer." || messages[0].MsgType != "model" {
+		t.Errorf("expected one final message, got %+v", messages)
+	}
+}
+
+func TestRecoverDrafts(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	store.CreateDebate("d1", "Cache design", "")
+	store.SaveDraft(0, "d1", "gemini", "I think Redis", 1)
+
+	// A draft checkpointed within idle may still be streaming elsewhere
+	if note := recoverDrafts(store, time.Hour); note != "" {
+		t.Errorf("expected a live draft left alone, got %q", note)
+	}
+
+	note := recoverDrafts(store, 0)
+	if !strings.Contains(note, `Recovered 1 partial response(s)`) || !strings.Contains(note, `Gemini in "Cache design"`) {
+		t.Errorf("unexpected note: %q", note)
+	}
+	messages, _ := store.GetMessages("d1")
+	if len(messages) != 1 || messages[0].MsgType != "model" || !strings.HasSuffix(messages[0].Content, interruptedNote) {
+		t.Errorf("expected the draft kept and marked interrupted, got %+v", messages)
+	}
+	if recoverDrafts(store, 0) != "" {
+		t.Error("expected nothing left to recover")
+	}
+}
+
+func TestReopenLastClosed(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	altShiftW := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}, Alt: true}
+
+	m := newTestModel()
+	m.store = store
+	closed := NewDebate("closed", "Cache design")
+	store.CreateDebate(closed.ID, closed.Name, "")
+	store.AddMessage(closed.ID, "user", "Which cache?", "user")
+	m.debates = append(m.debates, closed)
+
+	// Nothing closed yet
+	updated, _ := m.Update(altShiftW)
+	m = updated.(Model)
+	notes := m.debates[0].Messages
+	if len(m.debates) != 2 || len(notes) == 0 || !strings.Contains(notes[len(notes)-1].Content, "No closed debate") {
+		t.Fatalf("expected a note and no new tab, got %d tabs", len(m.debates))
+	}
+
+	m.closeTab(1)
+	if d, _ := store.GetDebate("closed"); d.Status != "abandoned" || m.lastClosedID != "closed" {
+		t.Fatalf("expected debate abandoned and remembered, got status=%q last=%q", d.Status, m.lastClosedID)
+	}
+
+	updated, _ = m.Update(altShiftW)
+	m = updated.(Model)
+	if len(m.debates) != 2 || m.activeDebate().ID != "closed" {
+		t.Fatalf("expected the closed debate reopened and active, got %d tabs", len(m.debates))
+	}
+	if d, _ := store.GetDebate("closed"); d.Status != "active" {
+		t.Errorf("Status = %q, want active", d.Status)
+	}
+	if m.activeDebate().Paused || m.activeDebate().Messages[0].Content != "Which cache?" {
+		t.Errorf("expected the debate loaded from the store and not paused")
+	}
+	if m.lastClosedID != "" {
+		t.Errorf("lastClosedID = %q, want cleared", m.lastClosedID)
+	}
+
+	// A second undo has nothing left to reopen
+	updated, _ = m.Update(altShiftW)
+	m = updated.(Model)
+	if len(m.debates) != 2 {
+		t.Errorf("expected no extra tab, got %d", len(m.debates))
+	}
+}
+
+func TestCloseTab_Confirmation(t *testing.T) {
+	altW := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}, Alt: true}
+	key := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
+
+	newModel := func() Model {
+		m := newTestModel()
+		busy := NewDebate("busy", "Busy")
+		busy.AddMessage("user", "Question")
+		busy.AddMessage("claude", "Answer")
+		busy.AddMessage("gemini", "Objection")
+		m.debates = append(m.debates, busy)
+		m.activeTab = 1
+		return m
+	}
+
+	// Cancel: anything but "y" keeps the tab
+	m := newModel()
+	updated, _ := m.Update(altW)
+	m = updated.(Model)
+	if m.confirm == nil {
+		t.Fatal("expected a confirmation before closing a debate with a discussion")
+	}
+	if len(m.debates) != 2 {
+		t.Fatalf("tab closed before confirming")
+	}
+	updated, _ = m.Update(key('n'))
+	m = updated.(Model)
+	if m.confirm != nil || len(m.debates) != 2 {
+		t.Errorf("expected confirmation dismissed and tab kept, got confirm=%v tabs=%d", m.confirm, len(m.debates))
+	}
+
+	// Accept: "y" closes the tab
+	updated, _ = m.Update(altW)
+	m = updated.(Model)
+	updated, _ = m.Update(key('y'))
+	m = updated.(Model)
+	if m.confirm != nil || len(m.debates) != 1 || m.debates[0].ID != "test" {
+		t.Errorf("expected busy tab closed, got confirm=%v tabs=%d", m.confirm, len(m.debates))
+	}
+
+	// A streaming round always asks, and confirming cancels it
+	m = newModel()
+	m.debates[1].Messages = nil
+	ctx, _ := m.startRound()
+	updated, _ = m.Update(altW)
+	m = updated.(Model)
+	if m.confirm == nil {
+		t.Fatal("expected a confirmation while a round is streaming")
+	}
+	updated, _ = m.Update(key('y'))
+	m = updated.(Model)
+	if ctx.Err() == nil || len(m.debates) != 1 {
+		t.Errorf("expected round cancelled and tab closed, got ctxErr=%v tabs=%d", ctx.Err(), len(m.debates))
+	}
+
+	// A near-empty debate closes without asking
+	m = newModel()
+	m.debates[1].Messages = nil
+	updated, _ = m.Update(altW)
+	m = updated.(Model)
+	if m.confirm != nil || len(m.debates) != 1 {
+		t.Errorf("expected immediate close, got confirm=%v tabs=%d", m.confirm, len(m.debates))
+	}
+}
+
+func TestResumeDebate_LoadsTailThenOlderOnScroll(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store, err := db.Open()
+	if err != nil {
+		t.Fatalf("db.Open() failed: %v", err)
+	}
+	defer store.Close()
+
+	total := messagePageSize + 50
+	store.CreateDebate("long", "Long", "")
+	for i := 0; i < total; i++ {
+		store.AddMessage("long", "claude", fmt.Sprintf("message %d", i), "model")
+	}
+
+	debate, err := ResumeDebate(store, "long")
+	if err != nil {
+		t.Fatalf("ResumeDebate() failed: %v", err)
+	}
+	if len(debate.Messages) != messagePageSize || !debate.HasOlder {
+		t.Fatalf("expected only the newest %d messages, got %d (HasOlder=%v)", messagePageSize, len(debate.Messages), debate.HasOlder)
+	}
+	if debate.Messages[0].Content != "message 50" {
+		t.Errorf("first loaded message = %q, want message 50", debate.Messages[0].Content)
+	}
+
+	m := newTestModel()
+	m.debates = []*Debate{debate}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+	m.focus = FocusChat
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	m = updated.(Model)
+	if len(debate.Messages) != total || debate.HasOlder {
+		t.Errorf("expected all %d messages after scrolling to the top, got %d (HasOlder=%v)", total, len(debate.Messages), debate.HasOlder)
+	}
+	if debate.Messages[0].Content != "message 0" {
+		t.Errorf("first message = %q, want message 0", debate.Messages[0].Content)
+	}
+}
+
+func TestResumeDebate_RestoresQualityWarnings(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	prompt := "Should we cache sessions in Redis?"
+	store.CreateDebate("d1", "Cache", "")
+	store.AddMessage("d1", "user", prompt, "user")
+	store.AddMessage("d1", "gpt", prompt, "model") // Echo, on the older page
+	for i := 0; i < messagePageSize-2; i++ {
+		store.AddMessage("d1", "claude", fmt.Sprintf("A substantive answer, part %d", i), "model")
+	}
+	store.AddMessage("d1", "gemini", "ok", "model")
+
+	debate, err := ResumeDebate(store, "d1")
+	if err != nil {
+		t.Fatalf("ResumeDebate() failed: %v", err)
+	}
+	last := debate.Messages[len(debate.Messages)-1]
+	if last.QualityWarning != "too short" {
+		t.Errorf("resumed warning = %q, want %q", last.QualityWarning, "too short")
+	}
+
+	// The echo is only recognized once its round's prompt is loaded
+	debate.LoadOlder(0)
+	if debate.Messages[0].Source != "user" || debate.Messages[1].QualityWarning != "echoes prompt" {
+		t.Errorf("expected the echo flagged after loading its prompt, got %+v", debate.Messages[:2])
+	}
+	if debate.Messages[2].QualityWarning != "" {
+		t.Errorf("substantive answer flagged %q", debate.Messages[2].QualityWarning)
+	}
+}
+
+func TestResumeDebate_AbandonedIsReadOnly(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("db.OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+	store.CreateDebate("open", "Open", "")
+	store.CreateDebate("closed", "Closed", "")
+	store.UpdateDebateStatus("closed", "abandoned", "")
+
+	if debate, err := ResumeDebate(store, "open"); err != nil || debate.Paused || debate.ReadOnly {
+		t.Fatalf("ResumeDebate(active) = %+v, %v; want an editable debate", debate, err)
+	}
+	debate, err := ResumeDebate(store, "closed")
+	if err != nil {
+		t.Fatalf("ResumeDebate() failed: %v", err)
+	}
+	if !debate.Paused || !debate.ReadOnly {
+		t.Fatalf("ResumeDebate(abandoned): Paused=%v ReadOnly=%v, want both", debate.Paused, debate.ReadOnly)
+	}
+
+	m := newTestModel()
+	m.store = store
+	m.debates = []*Debate{debate}
+	m.input.SetValue("one more thing")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || m.roundInFlight() {
+		t.Fatal("a read-only debate shouldn't dispatch prompts")
+	}
+	if last := debate.Messages[len(debate.Messages)-1]; last.Content != readOnlyBanner {
+		t.Errorf("last message = %q, want the read-only banner", last.Content)
+	}
+	if m.input.Value() != "one more thing" {
+		t.Errorf("input = %q, want it kept", m.input.Value())
+	}
+
+	updated, _ = m.handleCommand(commands.Resume{})
+	m = updated.(Model)
+	if debate.ReadOnly || debate.Paused {
+		t.Error("/resume should make the debate editable")
+	}
+	if d, _ := store.GetDebate("closed"); d.Status != "active" {
+		t.Errorf("stored status = %q, want active", d.Status)
+	}
+}
+
+func TestNew_OpensOnlyProjectDebates(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dataDir := t.TempDir()
+	project := t.TempDir()
+
+	store, err := db.OpenDir(dataDir)
+	if err != nil {
+		t.Fatalf("db.OpenDir() failed: %v", err)
+	}
+	store.CreateDebate("mine", "Mine", project)
+	store.CreateDebate("other", "Other", "/somewhere/else")
+	store.Close()
+
+	m := New(Options{DataDir: dataDir, Project: project})
+	defer m.store.Close()
+
+	if len(m.debates) != 1 || m.debates[0].ID != "mine" {
+		t.Fatalf("expected only the project's debate, got %d debates", len(m.debates))
+	}
+
+	m.openHistory("")
+	if len(m.historyState.debates) != 1 {
+		t.Errorf("history lists %d debates, want 1 from the project", len(m.historyState.debates))
+	}
+	m.historyState.ToggleAllProjects(m.store)
+	if len(m.historyState.debates) != 2 {
+		t.Errorf("history lists %d debates across all projects, want 2", len(m.historyState.debates))
+	}
+
+	m.createTab()
+	if got := m.activeDebate().ProjectPath; got != project {
+		t.Errorf("new debate project = %q, want %q", got, project)
+	}
+}
+
+func TestInputHistory(t *testing.T) {
+	var h inputHistory
+	if _, ok := h.Prev("typing"); ok {
+		t.Fatal("Prev() with no history should do nothing")
+	}
+
+	h.Push("first")
+	h.Push("second")
+	h.Push("second")
+
+	steps := []struct {
+		op     string
+		want   string
+		wantOK bool
+	}{
+		{"prev", "second", true},
+		{"prev", "first", true},
+		{"prev", "", false},
+		{"next", "second", true},
+		{"next", "draft", true}, // Back past the newest entry restores the draft
+		{"next", "", false},
+		{"prev", "second", true},
+	}
+	for i, step := range steps {
+		var got string
+		var ok bool
+		if step.op == "prev" {
+			got, ok = h.Prev("draft")
+		} else {
+			got, ok = h.Next()
+		}
+		if got != step.want || ok != step.wantOK {
+			t.Fatalf("step %d (%s) = %q, %v, want %q, %v", i, step.op, got, ok, step.want, step.wantOK)
+		}
+	}
+
+	// Sending stops browsing
+	h.Push("third")
+	if got, _ := h.Prev(""); got != "third" {
+		t.Errorf("Prev() after Push = %q, want third", got)
+	}
+	if len(h.entries) != 3 {
+		t.Errorf("expected repeated inputs to be stored once, got %v", h.entries)
+	}
+}
+
+func TestInputHistory_UpDownKeys(t *testing.T) {
+	m := newTestModel()
+	m.focus = FocusInput
+	m.input.Focus()
+	m.inputHistory.Push("/history")
+
+	m.input.SetValue("half typed")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = updated.(Model)
+	if got := m.input.Value(); got != "/history" {
+		t.Fatalf("after up, input = %q, want /history", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if got := m.input.Value(); got != "half typed" {
+		t.Errorf("after down, input = %q, want the draft back", got)
+	}
+}
+
+func TestLargePaste_NeedsSecondEnter(t *testing.T) {
+	m := newTestModel()
+	m.focus = FocusInput
+	m.input.Focus()
+	m.input.SetWidth(80)
+	debate := m.activeDebate()
+
+	big := strings.Repeat("x", largePromptChars+100)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(big), Paste: true})
+	m = updated.(Model)
+	if got := m.input.Length(); got != len(big) {
+		t.Fatalf("pasted %d chars, input holds %d", len(big), got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if len(debate.Messages) != 0 {
+		t.Fatalf("first Enter should not send, got %d messages", len(debate.Messages))
+	}
+	if !strings.Contains(m.renderInputPane(), fmt.Sprintf("send %d chars", len(big))) {
+		t.Error("expected the input pane to ask for a second Enter")
+	}
+
+	// Any other key withdraws the pending send
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if len(debate.Messages) != 0 {
+		t.Fatal("Enter after another key should ask again")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if len(debate.Messages) != 1 || debate.Messages[0].Content != big {
+		t.Fatalf("second Enter should send the prompt, got %d messages", len(debate.Messages))
+	}
+	if m.input.Value() != "" || m.confirmSendChars != 0 {
+		t.Error("expected input and pending send cleared after sending")
+	}
+}
+
+func TestModelResponse_StoppedOnConsensus(t *testing.T) {
+	m := newTestModel()
+	_, seq := m.startRound()
+
+	updated, _ := m.Update(modelResponseMsg{seq: seq, modelID: "grok", content: "Half an answ"})
+	m = updated.(Model)
+	updated, _ = m.Update(modelResponseMsg{seq: seq, modelID: "grok", done: true, stopped: true})
+	m = updated.(Model)
+
+	debate := m.activeDebate()
+	if debate.ModelStatus["grok"] != models.StatusIdle {
+		t.Errorf("expected grok idle after being stopped, got %v", debate.ModelStatus["grok"])
+	}
+	last := debate.Messages[len(debate.Messages)-1]
+	if last.Source != "system" || !strings.Contains(last.Content, "Stopped Grok") {
+		t.Errorf("expected a note that Grok was stopped, got %+v", last)
+	}
+}
+
+func TestModelResponse_StoppedPartialIsNotAPosition(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	m := newTestModel()
+	m.store = store
+	debate := m.activeDebate()
+	store.CreateDebate(debate.ID, debate.Name, "")
+	debate.AddMessage("user", "Postgres or MySQL?")
+	_, seq := m.startRound()
+
+	for _, resp := range []modelResponseMsg{
+		{modelID: "claude", content: "AGREE: Postgres, for its JSON support."},
+		{modelID: "claude", done: true},
+		{modelID: "gemini", content: "AGREE: Postgres is the safer pick."},
+		{modelID: "gemini", done: true},
+		{modelID: "grok", content: "OBJECT: MySQL is fast"},
+		{modelID: "grok", done: true, stopped: true},
+	} {
+		resp.seq = seq
+		updated, _ := m.Update(resp)
+		m = updated.(Model)
+	}
+	updated, _ := m.Update(allModelsDoneMsg{seq: seq})
+	m = updated.(Model)
+
+	result := m.activeDebate().Consensus
+	if result.AgreeCount != 2 || result.ObjectCount != 0 || !result.HasConsensus {
+		t.Errorf("stopped partial response counted as a position: %+v", result)
+	}
+
+	messages, _ := store.GetMessages(debate.ID)
+	for _, msg := range messages {
+		if msg.Source == "grok" && msg.MsgType != db.MsgTypeStopped {
+			t.Errorf("stopped response saved as %q, want %q", msg.MsgType, db.MsgTypeStopped)
+		}
+	}
+}
+
+func TestRefreshContext_PicksUpChangedFiles(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "schema.sql")
+	notes := filepath.Join(dir, "notes.md")
+	for path, content := range map[string]string{schema: "CREATE TABLE a (id INT);", notes: "keep it small"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := newTestModel()
+	m.store = store
+	debate := m.activeDebate()
+	store.CreateDebate(debate.ID, debate.Name, "")
+	for _, path := range []string{schema, notes} {
+		updated, _ := m.handleCommand(commands.AddContext{Path: path})
+		m = updated.(Model)
+	}
+
+	if err := os.WriteFile(schema, []byte("CREATE TABLE b (id INT);"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(notes)
+
+	updated, _ := m.handleCommand(commands.RefreshContext{})
+	m = updated.(Model)
+
+	if !strings.Contains(debate.ContextFiles[schema], "CREATE TABLE b") {
+		t.Errorf("expected the new schema in context, got %q", debate.ContextFiles[schema])
+	}
+	if !strings.Contains(debate.ContextFiles[notes], "keep it small") {
+		t.Error("a missing file should keep its last version")
+	}
+	summary := debate.Messages[len(debate.Messages)-1].Content
+	for _, want := range []string{"1 updated, 0 unchanged", "Updated: " + schema, "Missing, keeping the last version: " + notes} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	files, err := store.GetContextFiles(debate.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if f.Path == schema && !strings.Contains(f.Content, "CREATE TABLE b") {
+			t.Errorf("stored schema not updated: %q", f.Content)
+		}
+	}
+	if len(files) != 2 {
+		t.Errorf("expected 2 stored context files, got %d", len(files))
+	}
+}
+
+func TestContextPaneSummary(t *testing.T) {
+	tests := []struct {
+		name      string
+		sizes     map[string]int
+		wantTotal string
+		wantUsage contextUsage
+	}{
+		{"small", map[string]int{"b.go": 512, "a.go": 2048}, "Total 2.5 KB of 10.0 KB (25%)", contextUnderBudget},
+		{"near budget", map[string]int{"a.go": 8 * 1024}, "Total 8.0 KB of 10.0 KB (80%)", contextNearBudget},
+		{"at budget", map[string]int{"a.go": 10 * 1024}, "Total 10.0 KB of 10.0 KB (100%)", contextNearBudget},
+		{"over budget", map[string]int{"a.go": 6 * 1024, "b.go": 6 * 1024}, "Total 12.0 KB of 10.0 KB (120%) - over budget, some files won't be sent", contextOverBudget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			debate := NewDebate("test", "Test")
+			for path, n := range tt.sizes {
+				debate.ContextFiles[path] = strings.Repeat("x", n)
+			}
+			files, total, usage := contextPaneSummary(debate, 10*1024, "")
+			if total != tt.wantTotal {
+				t.Errorf("total = %q, want %q", total, tt.wantTotal)
+			}
+			if usage != tt.wantUsage {
+				t.Errorf("usage = %v, want %v", usage, tt.wantUsage)
+			}
+			if len(files) != len(tt.sizes) {
+				t.Errorf("got %d file lines, want %d", len(files), len(tt.sizes))
+			}
+		})
+	}
+
+	debate := NewDebate("test", "Test")
+	debate.ContextFiles["b.go"] = strings.Repeat("x", 512)
+	debate.ContextFiles["a.go"] = strings.Repeat("x", 2048)
+	files, _, _ := contextPaneSummary(debate, 10*1024, "")
+	want := []string{"* a.go (2.0 KB)", "* b.go (512 B)"}
+	if strings.Join(files, "|") != strings.Join(want, "|") {
+		t.Errorf("files = %q, want %q", files, want)
+	}
+
+	// The pinned preamble file comes first
+	files, _, _ = contextPaneSummary(debate, 10*1024, "b.go")
+	want = []string{"* b.go (512 B, pinned)", "* a.go (2.0 KB)"}
+	if strings.Join(files, "|") != strings.Join(want, "|") {
+		t.Errorf("files = %q, want %q", files, want)
+	}
+}
+
+func TestNewDebate_PinsPreambleFile(t *testing.T) {
+	facts := filepath.Join(t.TempDir(), "facts.md")
+	if err := os.WriteFile(facts, []byte("We deploy with Nomad, not Kubernetes."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("db.OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+
+	m := newTestModel()
+	m.store = store
+	m.config = &config.Config{}
+	m.config.Defaults.PreambleFile = facts
+
+	updated, _ := m.handleCommand(commands.NewDebate{Name: "Deploys"})
+	m = updated.(Model)
+	debate := m.activeDebate()
+	if !strings.Contains(debate.ContextFiles[facts], "We deploy with Nomad") {
+		t.Fatalf("context files = %v, want the preamble file", debate.ContextFiles)
+	}
+	if prompt, _ := withContextFiles(debate, "Which scheduler?"); !strings.Contains(prompt, "We deploy with Nomad") {
+		t.Errorf("prompt doesn't include the preamble file:\n%s", prompt)
+	}
+	if stored, _ := store.GetContextFiles(debate.ID); len(stored) != 1 || stored[0].Path != facts {
+		t.Errorf("stored context files = %+v, want the preamble file", stored)
+	}
+	if files, _, _ := contextPaneSummary(debate, contextBudget, m.preambleFile()); len(files) != 1 || !strings.Contains(files[0], "pinned") {
+		t.Errorf("context pane = %q, want the preamble file pinned", files)
+	}
+}
+
+func TestSetExecutor_RequiresCanExec(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Models.Claude.Enabled = true
+	cfg.Models.GPT.Enabled = true
+	cfg.Models.GPT.APIKey = "test-key"
+
+	m := newTestModel()
+	m.registry = models.NewRegistry(cfg)
+	m.config = cfg
+	debate := m.activeDebate()
+
+	updated, _ := m.handleCommand(commands.SetExecutor{Model: "gpt"})
+	m = updated.(Model)
+	if m.executor != "" || m.executorID() != "claude" {
+		t.Errorf("executor = %q (resolves to %q), want gpt rejected", m.executor, m.executorID())
+	}
+	if last := debate.Messages[len(debate.Messages)-1]; !strings.Contains(last.Content, "can't execute tools") {
+		t.Errorf("last message = %q, want a rejection", last.Content)
+	}
+
+	updated, _ = m.handleCommand(commands.SetExecutor{Model: "claude"})
+	m = updated.(Model)
+	if m.executor != "claude" {
+		t.Errorf("executor = %q, want claude", m.executor)
+	}
+
+	// A configured executor that can't execute falls back to one that can
+	m.executor = ""
+	cfg.Defaults.Executor = "gpt"
+	if got := m.executorID(); got != "claude" {
+		t.Errorf("executorID() = %q, want claude", got)
+	}
+}
+
+func TestContextPromptFor_SummarizesForNonReaders(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n\nfunc secretSauce() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	debate := NewDebate("test", "Test")
+	for _, path := range []string{file, dir} {
+		content, err := ctxloader.LoadContext(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		debate.ContextFiles[path] = content
+	}
+
+	promptFor, omitted := contextPromptFor(debate, "Is this safe?")
+	if len(omitted) != 0 {
+		t.Errorf("omitted = %v, want none", omitted)
+	}
+
+	reader := promptFor(models.ModelInfo{ID: "claude", CanRead: true})
+	if !strings.Contains(reader, "func secretSauce") || !strings.HasSuffix(reader, "Is this safe?") {
+		t.Errorf("reader prompt should have the full file:\n%s", reader)
+	}
+
+	summary := promptFor(models.ModelInfo{ID: "other", CanRead: false})
+	if strings.Contains(summary, "func secretSauce") {
+		t.Errorf("non-reader prompt shouldn't have file contents:\n%s", summary)
+	}
+	for _, want := range []string{"main.go (", "contents omitted", "=== Directory: " + dir, "Is this safe?"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("non-reader prompt missing %q:\n%s", want, summary)
+		}
+	}
+}
+
+func TestHistoryDetail(t *testing.T) {
+	tests := []struct {
+		consensus string
+		want      string
+	}{
+		{"", "No consensus recorded"},
+		{"Use NATS\nwith JetStream", "Consensus: Use NATS with JetStream"},
+		{strings.Repeat("a", consensusPreviewLen+10), "Consensus: " + strings.Repeat("a", consensusPreviewLen) + "..."},
+	}
+	for _, tt := range tests {
+		got := historyDetail(db.Debate{ID: "12345678", Consensus: tt.consensus})
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("historyDetail(%q) = %q, want it to contain %q", tt.consensus, got, tt.want)
+		}
+	}
+
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	store.CreateDebate("resolved-1", "Queues", "")
+	store.AddMessage("resolved-1", "user", "Kafka or NATS?", "user")
+	store.AddMessage("resolved-1", "claude", "AGREE: NATS", "model")
+	store.UpdateDebateStatus("resolved-1", "resolved", "Use NATS")
+
+	h := NewHistoryState()
+	if err := h.LoadDebates(store, ""); err != nil {
+		t.Fatal(err)
+	}
+	view := h.Render(120, 40)
+	if !strings.Contains(view, "Consensus: Use NATS") {
+		t.Errorf("history view missing the consensus preview:\n%s", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "Queues") && !strings.HasSuffix(strings.TrimRight(line, " │"), " 2") {
+			t.Errorf("expected the row to end with its message count, got %q", line)
+		}
+	}
+}
+
+func TestForkCommand_DefaultsToLatestConsensus(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("db.OpenInMemory() failed: %v", err)
+	}
+	defer store.Close()
+	store.CreateDebate("d1", "Caching", "")
+	store.AddMessage("d1", "user", "Which cache?", "user")
+	store.AddMessage("d1", "claude", "AGREE: redis", "model")
+	store.AddMessage("d1", "system", "CONSENSUS REACHED: 2 models agree (no objections). Ready for execution.", "system")
+	store.AddMessage("d1", "user", "What about eviction?", "user")
+
+	debate, err := ResumeDebate(store, "d1")
+	if err != nil {
+		t.Fatalf("ResumeDebate() failed: %v", err)
+	}
+	m := newTestModel()
+	m.store = store
+	m.debates = []*Debate{debate}
+
+	updated, _ := m.handleCommand(commands.Fork{})
+	m = updated.(Model)
+	if len(m.debates) != 2 || m.activeTab != 1 {
+		t.Fatalf("got %d debates, active tab %d; want the fork opened in a second tab", len(m.debates), m.activeTab)
+	}
+	forked := m.debates[1]
+	if forked.ID == debate.ID || forked.Name != "Fork of Caching" {
+		t.Errorf("fork = %s %q", forked.ID, forked.Name)
+	}
+	if stored, _ := store.GetMessages(forked.ID); len(stored) != 3 {
+		t.Errorf("fork has %d messages, want the 3 up to the consensus", len(stored))
+	}
+	if record, _ := store.GetDebate(forked.ID); record.ParentID != "d1" {
+		t.Errorf("fork parent = %q, want d1", record.ParentID)
+	}
+
+	updated, _ = m.handleCommand(commands.Fork{Index: 9})
+	m = updated.(Model)
+	if len(m.debates) != 2 {
+		t.Error("forking past the last message should fail")
+	}
+}
@@ -0,0 +1,83 @@
+// internal/ui/coalesce.go
+package ui
+
+import (
+	"time"
+
+	"roundtable/internal/orchestrator"
+)
+
+// coalesceWindow is how long streamed text is gathered before it's sent to
+// the UI, so many small deltas don't flood the update loop
+const coalesceWindow = 50 * time.Millisecond
+
+// streamedText reports whether resp only carries text, so it can be merged
+// with the same model's neighboring text
+func streamedText(resp orchestrator.Response) bool {
+	return resp.Content != "" && !resp.Done && !resp.Started && resp.Error == nil && resp.Usage == nil && !resp.Stopped
+}
+
+// coalesceResponses merges each model's text responses that arrive within
+// window of the first into one. Any other response first flushes its
+// model's pending text, so a done signal is delivered promptly and after
+// the text before it. Everything pending is flushed when in closes.
+func coalesceResponses(in <-chan orchestrator.Response, window time.Duration) <-chan orchestrator.Response {
+	out := make(chan orchestrator.Response, cap(in))
+
+	go func() {
+		defer close(out)
+
+		pending := make(map[string]*orchestrator.Response)
+		var order []string // Models with pending text, in arrival order
+		var timer <-chan time.Time
+
+		flush := func(modelID string) {
+			if resp, ok := pending[modelID]; ok {
+				out <- *resp
+				delete(pending, modelID)
+				for i, id := range order {
+					if id == modelID {
+						order = append(order[:i], order[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+		flushAll := func() {
+			for _, id := range order {
+				out <- *pending[id]
+				delete(pending, id)
+			}
+			order = order[:0]
+			timer = nil
+		}
+
+		for {
+			select {
+			case resp, ok := <-in:
+				if !ok {
+					flushAll()
+					return
+				}
+				if !streamedText(resp) {
+					flush(resp.ModelID)
+					out <- resp
+					continue
+				}
+				if p, ok := pending[resp.ModelID]; ok {
+					p.Content += resp.Content
+					continue
+				}
+				pending[resp.ModelID] = &resp
+				order = append(order, resp.ModelID)
+				if timer == nil {
+					timer = time.After(window)
+				}
+			case <-timer:
+				flushAll()
+			}
+		}
+	}()
+
+	return out
+}
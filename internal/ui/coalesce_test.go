@@ -0,0 +1,61 @@
+// internal/ui/coalesce_test.go
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"roundtable/internal/orchestrator"
+)
+
+func TestCoalesceResponses_MergesChunksWithinWindow(t *testing.T) {
+	in := make(chan orchestrator.Response, 10)
+	in <- orchestrator.Response{ModelID: "claude", Started: true}
+	in <- orchestrator.Response{ModelID: "claude", Content: "Use "}
+	in <- orchestrator.Response{ModelID: "gpt", Content: "AGREE"}
+	in <- orchestrator.Response{ModelID: "claude", Content: "Redis "}
+	in <- orchestrator.Response{ModelID: "claude", Content: "here."}
+	in <- orchestrator.Response{ModelID: "claude", Done: true}
+	in <- orchestrator.Response{ModelID: "gpt", Content: ": claude"}
+	close(in)
+
+	var got []orchestrator.Response
+	for resp := range coalesceResponses(in, time.Hour) {
+		got = append(got, resp)
+	}
+
+	want := []orchestrator.Response{
+		{ModelID: "claude", Started: true},
+		{ModelID: "claude", Content: "Use Redis here."},
+		{ModelID: "claude", Done: true},
+		{ModelID: "gpt", Content: "AGREE: claude"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d responses %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("response %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCoalesceResponses_FlushesAfterWindow(t *testing.T) {
+	in := make(chan orchestrator.Response)
+	out := coalesceResponses(in, 10*time.Millisecond)
+
+	in <- orchestrator.Response{ModelID: "claude", Content: "partial"}
+	select {
+	case resp := <-out:
+		if resp.Content != "partial" {
+			t.Errorf("flushed %+v, want the pending text", resp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending text wasn't flushed after the window")
+	}
+
+	close(in)
+	if _, ok := <-out; ok {
+		t.Error("output should close once the input does")
+	}
+}
@@ -0,0 +1,79 @@
+// internal/ui/confirm.go
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// closeConfirmMessages is how many user/model messages a debate can have
+// before closing it asks for confirmation
+const closeConfirmMessages = 2
+
+// confirmation is a pending yes/no question shown in the input pane.
+// It intercepts the next keypress: "y" runs onYes, anything else cancels.
+type confirmation struct {
+	prompt string
+	onYes  func(m *Model) tea.Cmd
+}
+
+// updateConfirm resolves the pending confirmation with a keypress
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	m.confirm = nil
+
+	switch msg.String() {
+	case "y", "Y":
+		cmd := c.onYes(&m)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// requestCloseTab closes a tab, first asking for confirmation if it holds a
+// discussion or a round is streaming into it
+func (m *Model) requestCloseTab(idx int) {
+	if idx < 0 || idx >= len(m.debates) || len(m.debates) <= 1 {
+		return
+	}
+
+	debate := m.debates[idx]
+	streaming := idx == m.activeTab && m.roundInFlight()
+	if !streaming && discussionLength(debate) <= closeConfirmMessages {
+		m.closeTab(idx)
+		return
+	}
+
+	prompt := fmt.Sprintf("Close %q? (y/N)", debate.Name)
+	if streaming {
+		prompt = fmt.Sprintf("Models are still responding. Close %q? (y/N)", debate.Name)
+	}
+	m.confirm = &confirmation{
+		prompt: prompt,
+		onYes: func(m *Model) tea.Cmd {
+			// The tab may have moved while the question was open
+			i := debateIndex(m.debates, debate.ID)
+			if m.debates[i] != debate {
+				return nil
+			}
+			var cmd tea.Cmd
+			if i == m.activeTab && m.roundInFlight() {
+				cmd = m.cancelRound()
+			}
+			m.closeTab(i)
+			return cmd
+		},
+	}
+}
+
+// discussionLength counts a debate's user and model messages
+func discussionLength(d *Debate) int {
+	n := 0
+	for _, msg := range d.Messages {
+		if msg.Source != "system" {
+			n++
+		}
+	}
+	return n
+}
@@ -3,21 +3,32 @@ package ui
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"roundtable/internal/consensus"
+	"roundtable/internal/db"
 	"roundtable/internal/models"
 )
 
 // DebateMessage represents a message in the debate
 type DebateMessage struct {
-	Source    string    // claude, gpt, gemini, grok, user, system, error
+	Source    string // claude, gpt, gemini, grok, user, system, error
 	Content   string
 	Timestamp time.Time
-	IsError   bool      // If true, render in error style
-	IsTimeout bool      // If true, this is specifically a timeout error
+	IsError   bool   // If true, render in error style
+	IsTimeout bool   // If true, this is specifically a timeout error
+	Round     int    // User-prompt round this message belongs to
+	MsgType   string // Stored message type, e.g. db.MsgTypeContext; may be empty for unsaved notes
+
+	QualityWarning string // Non-empty if the response was flagged as low-effort
+
+	// Checkpoint of a response still streaming: the store ID of its draft
+	draftID int64
 }
 
 // Debate represents a single debate session
@@ -28,17 +39,94 @@ type Debate struct {
 	CreatedAt    time.Time
 	Messages     []DebateMessage
 	ContextFiles map[string]string // path -> content
+	Tags         []string          // Sorted
 	Paused       bool
+	ReadOnly     bool // Opened from history to read; prompts are refused until /resume
+	Round        int  // User-prompt round, incremented on each user message
 
 	// Debate rounds tracking
-	DebateRound    int  // Current round (0 = initial, 1+ = discussion rounds)
-	MaxRounds      int  // Max auto-debate rounds before requiring user input (default 3)
-	AwaitingUser   bool // True if waiting for user input to continue
+	DebateRound  int  // Current round (0 = initial, 1+ = discussion rounds)
+	MaxRounds    int  // Max auto-debate rounds before requiring user input (default 3)
+	AwaitingUser bool // True if waiting for user input to continue
+
+	// Automatic consensus polls (defaults.auto_consensus_after_rounds)
+	RoundsWithoutConsensus int  // Rounds since consensus was reached or last polled for
+	AutoPolled             bool // The latest round was an automatic poll
+
+	// Analysis of the latest finished round, shown in the status bar; reset
+	// when the user starts a new round
+	Consensus consensus.ConsensusResult
 
 	// Model states
 	ModelStatus    map[string]models.ModelStatus
 	ModelStartTime map[string]time.Time // When each model started responding
-	AnimationFrame int                   // For streaming indicator animation
+	AnimationFrame int                  // For streaming indicator animation
+
+	// Token/cost usage
+	Usage      models.Usage            // Debate total
+	ModelUsage map[string]models.Usage // Per-model totals
+
+	// Resumed debates load only their newest messages; older ones stay in
+	// the store until LoadOlder is called
+	HasOlder bool
+	store    *db.Store
+	oldestID int64 // Store ID of the oldest loaded message
+
+	// Rendered messages, so a streaming chunk only re-renders its own message
+	render renderCache
+}
+
+// renderKey is everything a message's rendering depends on, besides the
+// width and styles
+type renderKey struct {
+	source, content, qualityWarning string
+	timestamp                       time.Time
+	isError, isTimeout              bool
+}
+
+// renderKey returns the fields of msg its rendering depends on
+func (msg DebateMessage) renderKey() renderKey {
+	return renderKey{
+		source:         msg.Source,
+		content:        msg.Content,
+		qualityWarning: msg.QualityWarning,
+		timestamp:      msg.Timestamp,
+		isError:        msg.IsError,
+		isTimeout:      msg.IsTimeout,
+	}
+}
+
+// renderCache holds each message's rendering by index, all at one content
+// width and style generation. An entry whose key no longer matches its
+// message is rendered again.
+type renderCache struct {
+	width      int
+	generation int
+	keys       []renderKey
+	rendered   []string
+}
+
+// renderedMessage returns the rendering of d.Messages[i], from the cache if
+// the message hasn't changed since it was last rendered
+func (d *Debate) renderedMessage(i, contentWidth int) string {
+	c := &d.render
+	if c.width != contentWidth || c.generation != styleGeneration {
+		*c = renderCache{width: contentWidth, generation: styleGeneration}
+	}
+	n := len(d.Messages)
+	for len(c.keys) < n {
+		c.keys = append(c.keys, renderKey{})
+		c.rendered = append(c.rendered, "")
+	}
+	c.keys, c.rendered = c.keys[:n], c.rendered[:n]
+
+	msg := d.Messages[i]
+	key := msg.renderKey()
+	if c.rendered[i] == "" || c.keys[i] != key {
+		c.keys[i] = key
+		c.rendered[i] = renderMessage(msg, contentWidth)
+	}
+	return c.rendered[i]
 }
 
 func NewDebate(id, name string) *Debate {
@@ -50,6 +138,7 @@ func NewDebate(id, name string) *Debate {
 		ContextFiles:   make(map[string]string),
 		ModelStatus:    make(map[string]models.ModelStatus),
 		ModelStartTime: make(map[string]time.Time),
+		ModelUsage:     make(map[string]models.Usage),
 		AnimationFrame: 0,
 		DebateRound:    0,
 		MaxRounds:      3,
@@ -97,14 +186,70 @@ func formatElapsedTime(elapsed time.Duration) string {
 	return fmt.Sprintf("%dm%ds", mins, secs)
 }
 
+// AddMessage appends a message to the debate. A user message starts a new round.
 func (d *Debate) AddMessage(source, content string) {
+	if source == "user" {
+		d.Round++
+		d.Consensus = consensus.ConsensusResult{}
+	}
 	d.Messages = append(d.Messages, DebateMessage{
 		Source:    source,
 		Content:   content,
 		Timestamp: time.Now(),
+		Round:     d.Round,
 	})
 }
 
+// LastUserPrompt returns the content of the most recent user message
+func (d *Debate) LastUserPrompt() string {
+	for i := len(d.Messages) - 1; i >= 0; i-- {
+		if d.Messages[i].Source == "user" {
+			return d.Messages[i].Content
+		}
+	}
+	return ""
+}
+
+// AddUsage accumulates a model's token/cost usage into the debate totals
+func (d *Debate) AddUsage(modelID string, usage models.Usage) {
+	d.Usage.Add(usage)
+	modelUsage := d.ModelUsage[modelID]
+	modelUsage.Add(usage)
+	d.ModelUsage[modelID] = modelUsage
+}
+
+// AddTag adds tag to the debate, keeping Tags sorted. Returns false if the
+// debate already had it.
+func (d *Debate) AddTag(tag string) bool {
+	i := sort.SearchStrings(d.Tags, tag)
+	if i < len(d.Tags) && d.Tags[i] == tag {
+		return false
+	}
+	d.Tags = append(d.Tags, "")
+	copy(d.Tags[i+1:], d.Tags[i:])
+	d.Tags[i] = tag
+	return true
+}
+
+// RemoveTag removes tag from the debate. Returns false if it didn't have it.
+func (d *Debate) RemoveTag(tag string) bool {
+	i := sort.SearchStrings(d.Tags, tag)
+	if i == len(d.Tags) || d.Tags[i] != tag {
+		return false
+	}
+	d.Tags = append(d.Tags[:i], d.Tags[i+1:]...)
+	return true
+}
+
+// formatTags renders tags as "#a #b"
+func formatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = "#" + tag
+	}
+	return strings.Join(parts, " ")
+}
+
 // AddErrorMessage adds an error message that will be rendered in red
 func (d *Debate) AddErrorMessage(source, content string, isTimeout bool) {
 	d.Messages = append(d.Messages, DebateMessage{
@@ -113,10 +258,13 @@ func (d *Debate) AddErrorMessage(source, content string, isTimeout bool) {
 		Timestamp: time.Now(),
 		IsError:   true,
 		IsTimeout: isTimeout,
+		Round:     d.Round,
 	})
 }
 
-func (d *Debate) RenderMessages(width int) string {
+// RenderMessages renders the chat. If only is set, messages from other models
+// are hidden; user, system and moderator messages are always shown.
+func (d *Debate) RenderMessages(width int, only string) string {
 	var sb strings.Builder
 
 	// Account for indent (2 spaces) and some padding
@@ -125,75 +273,180 @@ func (d *Debate) RenderMessages(width int) string {
 		contentWidth = 20
 	}
 
-	for _, msg := range d.Messages {
-		ts := msg.Timestamp.Format("15:04")
-
-		// Use error style for error messages, otherwise model style
-		var style lipgloss.Style
-		var header string
+	lastRound := 0
+	for i, msg := range d.Messages {
+		if only != "" && msg.Source != only && isParticipant(msg.Source) {
+			continue
+		}
 
-		if msg.IsError {
-			style = ErrorStyle
-			errorType := "Error"
-			if msg.IsTimeout {
-				errorType = "Timeout"
-			}
-			header = style.Render(fmt.Sprintf("[%s] %s %s:", ts, formatSource(msg.Source), errorType))
-		} else {
-			style = ModelStyle(msg.Source)
-			header = style.Render(fmt.Sprintf("[%s] %s:", ts, formatSource(msg.Source)))
+		// Mark where each new round's prompt begins
+		if msg.Source == "user" && msg.Round > lastRound {
+			sb.WriteString(roundSeparator(msg.Round))
+			sb.WriteString("\n\n")
+		}
+		if msg.Round > lastRound {
+			lastRound = msg.Round
 		}
 
-		sb.WriteString(header)
-		sb.WriteString("\n")
+		sb.WriteString(d.renderedMessage(i, contentWidth))
+	}
+
+	return sb.String()
+}
+
+// renderMessage renders a message's header and wrapped, indented content
+func renderMessage(msg DebateMessage, contentWidth int) string {
+	var sb strings.Builder
 
-		// Message content with indent and word wrapping
-		lines := strings.Split(msg.Content, "\n")
-		for _, line := range lines {
-			// Word wrap each line
-			wrapped := wordWrap(line, contentWidth)
-			for _, wline := range wrapped {
-				sb.WriteString("  ")
-				if msg.IsError {
-					sb.WriteString(ErrorStyle.Render(wline))
-				} else {
-					sb.WriteString(wline)
-				}
-				sb.WriteString("\n")
+	ts := msg.Timestamp.Format("15:04")
+
+	// Use error style for error messages, otherwise model style
+	var style lipgloss.Style
+	var header string
+
+	if msg.IsError {
+		style = ErrorStyle
+		errorType := "Error"
+		if msg.IsTimeout {
+			errorType = "Timeout"
+		}
+		header = style.Render(fmt.Sprintf("[%s] %s %s:", ts, formatSource(msg.Source), errorType))
+	} else {
+		style = ModelStyle(msg.Source)
+		header = style.Render(fmt.Sprintf("[%s] %s:", ts, formatSource(msg.Source)))
+		if msg.QualityWarning != "" {
+			header += " " + StatusWarn.Render("⚠ "+msg.QualityWarning)
+		}
+	}
+
+	sb.WriteString(header)
+	sb.WriteString("\n")
+
+	// Message content with indent and word wrapping
+	lines := strings.Split(msg.Content, "\n")
+	for _, line := range lines {
+		// Word wrap each line
+		wrapped := wordWrap(line, contentWidth)
+		for _, wline := range wrapped {
+			sb.WriteString("  ")
+			if msg.IsError {
+				sb.WriteString(ErrorStyle.Render(wline))
+			} else {
+				sb.WriteString(wline)
 			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
 	}
+	sb.WriteString("\n")
 
 	return sb.String()
 }
 
-// wordWrap wraps text to fit within the specified width
+// hasSource reports whether any message came from source
+func (d *Debate) hasSource(source string) bool {
+	for _, msg := range d.Messages {
+		if msg.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
+// roundSeparator renders a dim "── Round N ──" divider
+func roundSeparator(round int) string {
+	return DimStyle.Render(fmt.Sprintf("── Round %d ──", round))
+}
+
+// listMarker matches the bullet or number that starts a list item
+var listMarker = regexp.MustCompile(`^([-*+]|\d+[.)]) `)
+
+// minWrapWidth is the narrowest text column wordWrap indents to; deeper
+// indentation is dropped rather than squeezing the text further
+const minWrapWidth = 20
+
+// wordWrap wraps text to fit within the specified width, measured in
+// terminal columns. Words wider than a line are split across lines.
+// Leading indentation is kept on every line, and list items hang their
+// continuation lines under the item's text.
 func wordWrap(text string, width int) []string {
-	if width <= 0 || len(text) <= width {
+	if width <= 0 || lipgloss.Width(text) <= width {
 		return []string{text}
 	}
 
-	var lines []string
 	words := strings.Fields(text)
 	if len(words) == 0 {
 		return []string{""}
 	}
 
-	currentLine := words[0]
-	for _, word := range words[1:] {
-		if len(currentLine)+1+len(word) <= width {
-			currentLine += " " + word
+	indent := strings.ReplaceAll(text[:len(text)-len(strings.TrimLeft(text, " \t"))], "\t", "    ")
+	hang := indent
+	if m := listMarker.FindString(strings.Join(words, " ")); m != "" {
+		hang += strings.Repeat(" ", len(m))
+	}
+	if width-len(hang) < minWrapWidth {
+		indent, hang = "", ""
+	}
+
+	lines := wrapWords(words, width-len(indent), width-len(hang))
+	for i := range lines {
+		if i == 0 {
+			lines[i] = indent + lines[i]
 		} else {
+			lines[i] = hang + lines[i]
+		}
+	}
+	return lines
+}
+
+// wrapWords fills lines with words, the first line up to firstWidth
+// columns and the rest up to width. Words too wide for a line are split.
+func wrapWords(words []string, firstWidth, width int) []string {
+	var lines []string
+	lineWidth := firstWidth
+
+	var currentLine string
+	var currentWidth int
+	for i, word := range words {
+		wordWidth := lipgloss.Width(word)
+		if i > 0 {
+			if currentWidth+1+wordWidth <= lineWidth {
+				currentLine += " " + word
+				currentWidth += 1 + wordWidth
+				continue
+			}
 			lines = append(lines, currentLine)
-			currentLine = word
+			lineWidth = width
 		}
+
+		// The word starts a new line; split it if it's too wide for one
+		for wordWidth > lineWidth {
+			var head string
+			head, word = splitAtWidth(word, lineWidth)
+			lines = append(lines, head)
+			lineWidth = width
+			wordWidth = lipgloss.Width(word)
+		}
+		currentLine, currentWidth = word, wordWidth
 	}
 	lines = append(lines, currentLine)
 
 	return lines
 }
 
+// splitAtWidth splits s after as many runes as fit in width columns,
+// taking at least one so wide characters can't stall wrapping
+func splitAtWidth(s string, width int) (head, rest string) {
+	used := 0
+	for i, r := range s {
+		w := lipgloss.Width(string(r))
+		if i > 0 && used+w > width {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}
+
 func formatSource(source string) string {
 	switch source {
 	case "claude":
@@ -208,6 +461,8 @@ func formatSource(source string) string {
 		return "You"
 	case "system":
 		return "System"
+	case moderatorSource:
+		return "Moderator"
 	default:
 		return source
 	}
@@ -270,6 +525,11 @@ func statusIndicator(status models.ModelStatus) string {
 	}
 }
 
+// unhealthyIndicator marks a model that failed its last health check
+func unhealthyIndicator() string {
+	return StatusCrit.Render("!")
+}
+
 // DebateView wraps a debate with viewport for scrolling
 type DebateView struct {
 	Debate   *Debate
@@ -288,7 +548,7 @@ func NewDebateView(debate *Debate, width, height int) *DebateView {
 }
 
 func (v *DebateView) Update() {
-	content := v.Debate.RenderMessages(v.Viewport.Width)
+	content := v.Debate.RenderMessages(v.Viewport.Width, "")
 	v.Viewport.SetContent(content)
 	v.Viewport.GotoBottom()
 }
@@ -0,0 +1,250 @@
+// internal/ui/debate_test.go
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestDebate_AddMessageTracksRounds(t *testing.T) {
+	d := NewDebate("test", "Test")
+	d.AddMessage("system", "Welcome")
+	d.AddMessage("user", "First question")
+	d.AddMessage("claude", "First answer")
+	d.AddMessage("user", "Second question")
+	d.AddErrorMessage("gpt", "timed out", true)
+
+	wantRounds := []int{0, 1, 1, 2, 2}
+	for i, want := range wantRounds {
+		if got := d.Messages[i].Round; got != want {
+			t.Errorf("message %d round = %d, want %d", i, got, want)
+		}
+	}
+	if d.Round != 2 {
+		t.Errorf("debate round = %d, want 2", d.Round)
+	}
+}
+
+func TestDebate_RenderMessagesRoundSeparators(t *testing.T) {
+	d := NewDebate("test", "Test")
+	d.AddMessage("system", "Welcome")
+	d.AddMessage("user", "First question")
+	d.AddMessage("claude", "First answer")
+	d.AddMessage("user", "Second question")
+	d.AddMessage("claude", "Second answer")
+
+	out := d.RenderMessages(80, "")
+
+	if strings.Count(out, "── Round") != 2 {
+		t.Errorf("expected 2 round separators, got:\n%s", out)
+	}
+
+	round1 := strings.Index(out, "── Round 1 ──")
+	round2 := strings.Index(out, "── Round 2 ──")
+	if round1 < 0 || round2 < 0 {
+		t.Fatalf("missing round separators in:\n%s", out)
+	}
+
+	// Each separator sits between the previous round and the new prompt
+	if !(strings.Index(out, "Welcome") < round1 && round1 < strings.Index(out, "First question")) {
+		t.Error("Round 1 separator should precede the first user message")
+	}
+	if !(strings.Index(out, "First answer") < round2 && round2 < strings.Index(out, "Second question")) {
+		t.Error("Round 2 separator should sit between round 1's answer and the second user message")
+	}
+}
+
+func TestDebate_RenderMessagesOnlySource(t *testing.T) {
+	d := NewDebate("test", "Test")
+	d.AddMessage("system", "Welcome")
+	d.AddMessage("user", "What about caching?")
+	d.AddMessage("claude", "Claude says cache")
+	d.AddMessage("gemini", "Gemini says don't")
+	d.AddErrorMessage("gpt", "GPT failed", false)
+
+	out := d.RenderMessages(80, "claude")
+	for _, want := range []string{"Welcome", "What about caching?", "Claude says cache"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("filtered output missing %q:\n%s", want, out)
+		}
+	}
+	for _, hidden := range []string{"Gemini says don't", "GPT failed"} {
+		if strings.Contains(out, hidden) {
+			t.Errorf("filtered output should hide %q:\n%s", hidden, out)
+		}
+	}
+
+	all := d.RenderMessages(80, "")
+	if !strings.Contains(all, "Gemini says don't") || !strings.Contains(all, "GPT failed") {
+		t.Errorf("unfiltered output should show every message:\n%s", all)
+	}
+}
+
+func TestDebate_RenderMessagesCache(t *testing.T) {
+	d := NewDebate("test", "Test")
+	d.AddMessage("user", "What about caching?")
+	d.AddMessage("claude", "Claude says")
+	d.AddMessage("gpt", "GPT says")
+
+	// fresh renders d without the cache's help
+	fresh := func(width int) string {
+		t.Helper()
+		d.render = renderCache{}
+		return d.RenderMessages(width, "")
+	}
+	check := func(step string, width int) {
+		t.Helper()
+		got := d.RenderMessages(width, "")
+		if want := fresh(width); got != want {
+			t.Errorf("%s: cached render differs:\n%s\nwant:\n%s", step, got, want)
+		}
+	}
+
+	d.RenderMessages(80, "")
+	d.Messages[1].Content += " cache everything"
+	check("streamed chunk", 80)
+
+	d.RenderMessages(80, "")
+	d.Messages[2].QualityWarning = "very short"
+	d.Messages[2].IsError = true
+	check("flagged message", 80)
+
+	d.RenderMessages(80, "")
+	check("narrower", 30)
+
+	d.RenderMessages(80, "")
+	d.Messages = append([]DebateMessage{{Source: "system", Content: "Older message"}}, d.Messages...)
+	check("older message loaded", 80)
+
+	d.RenderMessages(80, "")
+	d.Messages = d.Messages[:2]
+	check("messages removed", 80)
+
+	d.RenderMessages(80, "")
+	ApplyTheme(LightTheme)
+	defer ApplyTheme(DarkTheme)
+	if d.render.generation == styleGeneration {
+		t.Error("changing the theme should outdate the cache")
+	}
+	check("theme change", 80)
+}
+
+// BenchmarkRenderMessages_Streaming renders a long debate after each chunk
+// of a streaming response, with and without the render cache
+func BenchmarkRenderMessages_Streaming(b *testing.B) {
+	paragraph := strings.Repeat("The models weigh caching against consistency and argue about eviction. ", 12)
+	newDebate := func() *Debate {
+		d := NewDebate("bench", "Bench")
+		for round := 0; round < 50; round++ {
+			d.AddMessage("user", "Next question about the design?")
+			for _, id := range []string{"claude", "gpt", "gemini", "grok"} {
+				d.AddMessage(id, paragraph+"\n\n- first point\n- second point\n\n"+paragraph)
+			}
+		}
+		d.AddMessage("claude", "")
+		return d
+	}
+
+	for _, cached := range []bool{true, false} {
+		name := "cached"
+		if !cached {
+			name = "uncached"
+		}
+		b.Run(name, func(b *testing.B) {
+			d := newDebate()
+			last := len(d.Messages) - 1
+			d.RenderMessages(120, "")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !cached {
+					d.render = renderCache{}
+				}
+				d.Messages[last].Content += "token "
+				d.RenderMessages(120, "")
+			}
+		})
+	}
+}
+
+func TestWordWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{"fits", "short line", 20, []string{"short line"}},
+		{"ascii", "the quick brown fox jumps", 10, []string{"the quick", "brown fox", "jumps"}},
+		{"cjk", "你好世界 这是 一个测试", 8, []string{"你好世界", "这是", "一个测试"}},
+		{"cjk exactly full", "你好 世界", 5, []string{"你好", "世界"}},
+		{"emoji", "🎉🎉 party time", 10, []string{"🎉🎉 party", "time"}},
+		{"long token", "see abcdefghijklmnopqrstuvwxyz now", 10, []string{"see", "abcdefghij", "klmnopqrst", "uvwxyz now"}},
+		{"long cjk token", "一二三四五六七八九十", 7, []string{"一二三", "四五六", "七八九", "十"}},
+		{"blank", "   ", 2, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wordWrap(tt.text, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wordWrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			for _, line := range got {
+				if w := lipgloss.Width(line); w > tt.width {
+					t.Errorf("line %q is %d columns wide, over %d", line, w, tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestWordWrap_Indentation(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{"indented block", "    return compute(first, second, third)", 26,
+			[]string{"    return compute(first,", "    second, third)"}},
+		{"tab indent", "\tfirst second third fourth fifth sixth", 28,
+			[]string{"    first second third", "    fourth fifth sixth"}},
+		{"bullet", "- the cache should expire entries after an hour", 24,
+			[]string{"- the cache should", "  expire entries after", "  an hour"}},
+		{"nested bullet", "  * keep the old endpoint until clients move", 30,
+			[]string{"  * keep the old endpoint", "    until clients move"}},
+		{"numbered", "12. run the migration before deploying", 26,
+			[]string{"12. run the migration", "    before deploying"}},
+		{"not a list", "-flag values are parsed first", 22,
+			[]string{"-flag values are", "parsed first"}},
+		{"too deep to keep", "                    deeply nested text here", 30,
+			[]string{"deeply nested text here"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wordWrap(tt.text, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wordWrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			for _, line := range got {
+				if w := lipgloss.Width(line); w > tt.width {
+					t.Errorf("line %q is %d columns wide, over %d", line, w, tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestWordWrap_StyledText(t *testing.T) {
+	red := func(s string) string { return "\x1b[31m" + s + "\x1b[0m" }
+	styled := red("error") + " " + red("message") + " text"
+	for _, line := range wordWrap(styled, 13) {
+		if w := lipgloss.Width(line); w > 13 {
+			t.Errorf("line %q is %d columns wide, over 13", line, w)
+		}
+	}
+	if got := wordWrap(styled, 18); len(got) != 1 {
+		t.Errorf("styled text 18 columns wide should fit on one line, got %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+// internal/ui/debug.go
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"roundtable/internal/config"
+	"roundtable/internal/db"
+)
+
+// debugPaths describes where the config and debates are stored
+func debugPaths(store *db.Store) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Config file: %s\n", config.ConfigPath())
+
+	switch {
+	case store == nil:
+		b.WriteString("Database: not available")
+	case store.Path() == "":
+		b.WriteString("Database: in memory (nothing is saved this session)")
+	default:
+		fmt.Fprintf(&b, "Data dir: %s\n", filepath.Dir(store.Path()))
+		fmt.Fprintf(&b, "Database: %s", store.Path())
+	}
+	return b.String()
+}
+
+// debateDump is the raw stored record of a debate, as written by /debug dump
+type debateDump struct {
+	Debate   *db.Debate
+	Messages []db.Message
+}
+
+// dumpDebate writes a debate's stored row and messages to a temp file as
+// JSON and returns the file's path
+func dumpDebate(store *db.Store, id string) (string, error) {
+	if store == nil {
+		return "", fmt.Errorf("database not available")
+	}
+	d, err := store.GetDebate(id)
+	if err != nil {
+		return "", err
+	}
+	messages, err := store.GetMessages(id)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(debateDump{Debate: d, Messages: messages}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "roundtable-"+id+"-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
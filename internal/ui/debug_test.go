@@ -0,0 +1,42 @@
+// internal/ui/debug_test.go
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"roundtable/internal/db"
+)
+
+func TestDumpDebate(t *testing.T) {
+	store, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	store.CreateDebate("d1", "Cache", "")
+	store.AddMessage("d1", "user", "Which cache?", "user")
+
+	path, err := dumpDebate(store, "d1")
+	if err != nil {
+		t.Fatalf("dumpDebate() failed: %v", err)
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dump debateDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("dump isn't JSON: %v", err)
+	}
+	if dump.Debate.Name != "Cache" || len(dump.Messages) != 1 || dump.Messages[0].Content != "Which cache?" {
+		t.Errorf("unexpected dump: %+v", dump)
+	}
+
+	if _, err := dumpDebate(store, "missing"); err == nil {
+		t.Error("expected an error for an unknown debate")
+	}
+}
@@ -10,41 +10,50 @@ import (
 // Help overlay content and rendering
 
 var (
-	// Help section title style
+	helpTitleStyle   lipgloss.Style // Help section title style
+	helpSectionStyle lipgloss.Style // Help section header style
+	helpKeyStyle     lipgloss.Style // Keybindings
+	helpCmdStyle     lipgloss.Style // Slash commands
+	helpDescStyle    lipgloss.Style // Descriptions
+	helpDimStyle     lipgloss.Style // Secondary info
+
+	// Status indicator styles for help
+	helpStatusOK   lipgloss.Style
+	helpStatusWarn lipgloss.Style
+	helpStatusDim  lipgloss.Style
+	helpStatusErr  lipgloss.Style
+)
+
+// applyHelpTheme rebuilds the help styles from t; called by ApplyTheme
+func applyHelpTheme(t Theme) {
 	helpTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Cyan).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(t.Accent).
+		MarginBottom(1)
 
-	// Help section header style
 	helpSectionStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(Yellow).
-				MarginTop(1)
+		Bold(true).
+		Foreground(t.Highlight).
+		MarginTop(1)
 
-	// Help key style (for keybindings)
 	helpKeyStyle = lipgloss.NewStyle().
-			Foreground(Green).
-			Bold(true)
+		Foreground(t.Success).
+		Bold(true)
 
-	// Help command style (for slash commands)
 	helpCmdStyle = lipgloss.NewStyle().
-			Foreground(Magenta)
+		Foreground(t.Secondary)
 
-	// Help description style
 	helpDescStyle = lipgloss.NewStyle().
-			Foreground(White)
+		Foreground(t.Text)
 
-	// Help dim style (for secondary info)
 	helpDimStyle = lipgloss.NewStyle().
-			Foreground(Dim)
+		Foreground(t.Dim)
 
-	// Status indicator styles for help
-	helpStatusOK   = lipgloss.NewStyle().Foreground(Green).Bold(true)
-	helpStatusWarn = lipgloss.NewStyle().Foreground(Orange).Bold(true)
-	helpStatusDim  = lipgloss.NewStyle().Foreground(Dim)
-	helpStatusErr  = lipgloss.NewStyle().Foreground(Red).Bold(true)
-)
+	helpStatusOK = lipgloss.NewStyle().Foreground(t.Success).Bold(true)
+	helpStatusWarn = lipgloss.NewStyle().Foreground(t.Warning).Bold(true)
+	helpStatusDim = lipgloss.NewStyle().Foreground(t.Dim)
+	helpStatusErr = lipgloss.NewStyle().Foreground(t.Danger).Bold(true)
+}
 
 // HelpContent returns the formatted help overlay content
 func HelpContent(width, height int) string {
@@ -67,10 +76,12 @@ func HelpContent(width, height int) string {
 		{"Alt+[", "Previous tab"},
 		{"Alt+]", "Next tab"},
 		{"Alt+N", "Create new debate tab"},
-		{"Alt+W", "Close current tab"},
+		{"Alt+W", "Close current tab (confirm with y)"},
+		{"Alt+Shift+W", "Reopen the last closed tab"},
 		{"Alt+H", "Browse past debates (history)"},
 		{"Enter", "Send message to all models"},
 		{"Shift+Enter", "Insert newline (multi-line input)"},
+		{"↑  ↓", "Recall earlier inputs (when input focused)"},
 		{"F1", "Toggle this help overlay"},
 		{"Tab", "Cycle focus (Input -> Chat -> Context -> Models)"},
 		{"Shift+Tab", "Cycle focus backward"},
@@ -78,7 +89,11 @@ func HelpContent(width, height int) string {
 		{"PgUp/Ctrl+U", "Scroll half page up"},
 		{"PgDn/Ctrl+D", "Scroll half page down"},
 		{"Home/g End/G", "Jump to top/bottom of chat"},
-		{"Esc", "Close help / Return to input"},
+		{"Esc", "Close help / Cancel round / Return to input"},
+		{"Ctrl+X", "Cancel the current round"},
+		{"Ctrl+B", "Show/hide the context pane"},
+		{"Ctrl+G", "Show/hide the models pane"},
+		{"Shift+↑  ↓", "Reorder models (when models focused)"},
 		{"Ctrl+C / Ctrl+Q", "Quit Roundtable"},
 	}
 
@@ -100,16 +115,37 @@ func HelpContent(width, height int) string {
 		{"/help", "Show this help overlay"},
 		{"/new [name]", "Create a new debate (optional name)"},
 		{"/close", "Close the current debate tab"},
+		{"/reopen", "Reopen the last closed debate tab"},
 		{"/context add <path>", "Load a file into debate context"},
 		{"/context list", "List all loaded context files"},
 		{"/context remove <path>", "Remove a file from context"},
+		{"/context refresh", "Re-read context files changed on disk"},
+		{"/tag add|remove <name>", "Tag or untag the current debate"},
 		{"/models", "Open model picker/configuration"},
-		{"/consensus", "Force a consensus check among models"},
+		{"/models refresh", "Re-check which models are reachable"},
+		{"/consensus [poll]", "Ask every model for its position again"},
+		{"/consensus check", "Tally positions already given, no polling"},
 		{"/execute", "Execute the agreed-upon approach"},
+		{"/executor [model]", "Show or change the model that executes"},
 		{"/pause", "Pause automatic debate progression"},
 		{"/resume", "Resume automatic debate progression"},
-		{"/history", "Browse past debate sessions"},
+		{"/retry", "Send the last round's prompt again"},
+		{"/timeout [seconds]", "Show or change the model timeout"},
+		{"/fork [message]", "Branch the debate into a new tab"},
+		{"/history [tag]", "Browse past debates, optionally by tag"},
 		{"/export [format]", "Export debate to markdown or JSON"},
+		{"/export archive <path>", "Export every debate to a zip file"},
+		{"/import <path>", "Import a debate exported as JSON"},
+		{"/reload", "Reload config and rebuild models"},
+		{"/preview [prompt]", "Show the exact prompt sent to models"},
+		{"/theme [name]", "Switch color theme (dark, light, high-contrast)"},
+		{"/only <model>|all", "Show only one model's messages, or all"},
+		{"/challenge <model>", "Have a model argue against the consensus"},
+		{"/commands", "List shell commands models proposed"},
+		{"/attach <path>", "Send an image with the next prompt"},
+		{"/whereami", "Show the config, data and database paths"},
+		{"/debug dump", "Write the debate's stored record to JSON"},
+		{"@model <question>", "Ask one model a follow-up, e.g. @gemini why?"},
 	}
 
 	for _, cmd := range commands {
@@ -133,6 +169,7 @@ func HelpContent(width, height int) string {
 		{"○", helpStatusDim, "Waiting - Model is queued, waiting for its turn"},
 		{"◌", helpStatusDim, "Timeout - Model response timed out"},
 		{"✗", helpStatusErr, "Error - Model encountered an error"},
+		{"!", helpStatusErr, "Unhealthy - Model failed its last health check"},
 	}
 
 	for _, ind := range indicators {
@@ -175,7 +212,7 @@ func HelpContent(width, height int) string {
 	// Build the overlay box
 	overlayStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(Cyan).
+		BorderForeground(ActiveTheme.Accent).
 		Padding(1, 3).
 		MaxWidth(width - 10).
 		MaxHeight(height - 4)
@@ -3,11 +3,14 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"roundtable/internal/consensus"
 	"roundtable/internal/db"
+	"roundtable/internal/models"
 )
 
 // ViewMode represents the current view state
@@ -16,14 +19,20 @@ type ViewMode int
 const (
 	ViewNormal ViewMode = iota
 	ViewHistory
+	ViewConfigErrors
 )
 
 // HistoryState holds the state for the history browser
 type HistoryState struct {
-	debates   []db.Debate
-	cursor    int
-	scrollTop int
-	maxHeight int
+	debates     []db.Debate
+	tags        map[string][]string // Debate ID -> tags
+	counts      map[string]int      // Debate ID -> message count
+	tag         string              // Only debates with this tag; empty means all
+	project     string              // Only debates from this project, unless allProjects
+	allProjects bool
+	cursor      int
+	scrollTop   int
+	maxHeight   int
 }
 
 // NewHistoryState creates a new history state
@@ -66,16 +75,58 @@ func (h *HistoryState) Selected() *db.Debate {
 	return nil
 }
 
-// LoadDebates loads debates from the database
-func (h *HistoryState) LoadDebates(store *db.Store) error {
+// SetProject limits the list to debates from project
+func (h *HistoryState) SetProject(project string) {
+	h.project = project
+}
+
+// ToggleAllProjects switches between the current project's debates and
+// every project's, then reloads
+func (h *HistoryState) ToggleAllProjects(store *db.Store) error {
+	h.allProjects = !h.allProjects
+	return h.LoadDebates(store, h.tag)
+}
+
+// LoadDebates loads debates from the database, only those tagged tag if
+// it's set
+func (h *HistoryState) LoadDebates(store *db.Store, tag string) error {
 	if store == nil {
 		return fmt.Errorf("database not available")
 	}
-	debates, err := store.ListDebates()
+	project := h.project
+	if h.allProjects {
+		project = ""
+	}
+	var debates []db.Debate
+	var err error
+	switch {
+	case tag != "":
+		debates, err = store.ListDebatesByTag(tag)
+		if project != "" {
+			debates = slices.DeleteFunc(debates, func(d db.Debate) bool {
+				return d.ProjectPath != project && d.ProjectPath != ""
+			})
+		}
+	case project != "":
+		debates, err = store.ListDebatesByProject(project)
+	default:
+		debates, err = store.ListDebates()
+	}
 	if err != nil {
 		return err
 	}
 	h.debates = debates
+	h.tag = tag
+	h.tags = make(map[string][]string)
+	h.counts = make(map[string]int)
+	for _, d := range debates {
+		if tags, err := store.GetTags(d.ID); err == nil && len(tags) > 0 {
+			h.tags[d.ID] = tags
+		}
+		if n, err := store.CountMessages(d.ID); err == nil {
+			h.counts[d.ID] = n
+		}
+	}
 	h.cursor = 0
 	h.scrollTop = 0
 	return nil
@@ -96,14 +147,25 @@ func (h *HistoryState) Render(width, height int) string {
 	// Title
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(Cyan).
+		Foreground(ActiveTheme.Accent).
 		Render("DEBATE HISTORY")
 	content.WriteString(title)
+	if h.tag != "" {
+		content.WriteString(" " + SystemStyle.Render(formatTags([]string{h.tag})))
+	}
 	content.WriteString("\n")
-	content.WriteString(DimStyle.Render("Select a past debate to resume"))
+	scope := "all projects"
+	if h.project != "" && !h.allProjects {
+		scope = h.project
+	}
+	content.WriteString(DimStyle.Render("Select a past debate to resume (" + scope + ")"))
 	content.WriteString("\n\n")
 
-	if len(h.debates) == 0 {
+	if len(h.debates) == 0 && h.tag != "" {
+		content.WriteString(DimStyle.Render(fmt.Sprintf("No debates tagged #%s.", h.tag)))
+		content.WriteString("\n\n")
+		content.WriteString(DimStyle.Render("Tag the current debate with /tag add " + h.tag))
+	} else if len(h.debates) == 0 {
 		content.WriteString(DimStyle.Render("No past debates found."))
 		content.WriteString("\n\n")
 		content.WriteString(DimStyle.Render("Start a new debate and it will appear here."))
@@ -144,7 +206,7 @@ func (h *HistoryState) Render(width, height int) string {
 			case "active":
 				statusStyle = StatusOK
 			case "resolved":
-				statusStyle = lipgloss.NewStyle().Foreground(Green)
+				statusStyle = lipgloss.NewStyle().Foreground(ActiveTheme.Success)
 			case "abandoned":
 				statusStyle = DimStyle
 			default:
@@ -156,16 +218,23 @@ func (h *HistoryState) Render(width, height int) string {
 			lineStyle := DimStyle
 			if i == h.cursor {
 				cursor = "> "
-				lineStyle = lipgloss.NewStyle().Foreground(Cyan)
+				lineStyle = lipgloss.NewStyle().Foreground(ActiveTheme.Accent)
 			}
 
 			statusStr := statusStyle.Width(10).Render(d.Status)
-			line := fmt.Sprintf("%-8s  %-20s  %s  %-19s",
-				d.ID[:8], name, statusStr, timeStr)
+			line := fmt.Sprintf("%-8s  %-20s  %s  %-19s  %-8d",
+				d.ID[:8], name, statusStr, timeStr, h.counts[d.ID])
 
 			content.WriteString(cursor)
 			content.WriteString(lineStyle.Render(line))
+			if tags := h.tags[d.ID]; len(tags) > 0 {
+				content.WriteString("  " + SystemStyle.Render(formatTags(tags)))
+			}
 			content.WriteString("\n")
+			if i == h.cursor {
+				content.WriteString(historyDetail(d))
+				content.WriteString("\n")
+			}
 		}
 
 		// Scroll indicator
@@ -179,13 +248,13 @@ func (h *HistoryState) Render(width, height int) string {
 
 	// Footer with keybindings
 	content.WriteString("\n\n")
-	footer := DimStyle.Render("Up/Down: Navigate | Enter: Resume | Esc: Cancel")
+	footer := DimStyle.Render("Up/Down: Navigate | Enter: Resume | a: All projects | Esc: Cancel")
 	content.WriteString(footer)
 
 	// Build the overlay box
 	overlayStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(Cyan).
+		BorderForeground(ActiveTheme.Accent).
 		Padding(1, 2).
 		MaxWidth(width - 10).
 		MaxHeight(height - 4)
@@ -199,6 +268,23 @@ func (h *HistoryState) Render(width, height int) string {
 	)
 }
 
+// consensusPreviewLen is how much of a debate's consensus the history
+// browser shows under the selected row
+const consensusPreviewLen = 70
+
+// historyDetail describes the selected debate's outcome: the start of its
+// consensus, or that none was recorded
+func historyDetail(d db.Debate) string {
+	preview := strings.Join(strings.Fields(d.Consensus), " ")
+	if preview == "" {
+		return DimStyle.Render("    No consensus recorded")
+	}
+	if runes := []rune(preview); len(runes) > consensusPreviewLen {
+		preview = string(runes[:consensusPreviewLen]) + "..."
+	}
+	return lipgloss.NewStyle().Foreground(ActiveTheme.Success).Render("    Consensus: " + preview)
+}
+
 // ResumeDebate loads a debate from the database into a Debate struct
 func ResumeDebate(store *db.Store, debateID string) (*Debate, error) {
 	if store == nil {
@@ -215,20 +301,17 @@ func ResumeDebate(store *db.Store, debateID string) (*Debate, error) {
 	debate := NewDebate(dbDebate.ID, dbDebate.Name)
 	debate.ProjectPath = dbDebate.ProjectPath
 	debate.Paused = dbDebate.Status != "active"
+	debate.ReadOnly = dbDebate.Status == "paused" || dbDebate.Status == "abandoned"
 
-	// Load messages from database
-	messages, err := store.GetMessages(debateID)
-	if err != nil {
+	// Load the most recent messages; older ones load on scroll
+	if err := loadMessages(store, debate); err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
 
-	// Populate debate.Messages from stored messages
-	for _, msg := range messages {
-		debate.Messages = append(debate.Messages, DebateMessage{
-			Source:    msg.Source,
-			Content:   msg.Content,
-			Timestamp: msg.CreatedAt,
-		})
+	loadUsage(store, debate)
+
+	if tags, err := store.GetTags(debateID); err == nil {
+		debate.Tags = tags
 	}
 
 	// Load context files
@@ -244,3 +327,90 @@ func ResumeDebate(store *db.Store, debateID string) (*Debate, error) {
 
 	return debate, nil
 }
+
+// messagePageSize is how many messages are loaded when a debate is opened
+// and each time the user scrolls past the oldest loaded one
+const messagePageSize = 100
+
+// loadMessages loads a debate's most recent page of messages from the store
+func loadMessages(store *db.Store, debate *Debate) error {
+	messages, err := store.GetMessagesTail(debate.ID, messagePageSize)
+	if err != nil {
+		return err
+	}
+	debate.store = store
+	debate.prependStored(messages)
+	debate.HasOlder = len(messages) == messagePageSize
+	return nil
+}
+
+// LoadOlder loads up to n messages older than those already loaded; n <= 0
+// loads all of them. Returns how many messages were prepended.
+func (d *Debate) LoadOlder(n int) int {
+	if !d.HasOlder || d.store == nil {
+		return 0
+	}
+	messages, err := d.store.GetMessagesBefore(d.ID, d.oldestID, n)
+	if err != nil {
+		return 0
+	}
+	d.prependStored(messages)
+	d.HasOlder = n > 0 && len(messages) == n
+	return len(messages)
+}
+
+// prependStored adds stored messages ahead of those already loaded
+func (d *Debate) prependStored(messages []db.Message) {
+	if len(messages) == 0 {
+		return
+	}
+	loaded := make([]DebateMessage, 0, len(messages)+len(d.Messages))
+	for _, msg := range messages {
+		loaded = append(loaded, DebateMessage{
+			Source:    msg.Source,
+			Content:   msg.Content,
+			Timestamp: msg.CreatedAt,
+			Round:     msg.Round,
+			MsgType:   msg.MsgType,
+		})
+		d.Round = max(d.Round, msg.Round)
+	}
+	// Quality warnings aren't stored. Reassess the new messages and the
+	// responses after them that lacked their round's prompt until now.
+	end := len(loaded)
+	for end < len(loaded)+len(d.Messages) && d.Messages[end-len(loaded)].Source != "user" {
+		end++
+	}
+	d.Messages = append(loaded, d.Messages...)
+	d.oldestID = messages[0].ID
+	assessStored(d.Messages[:end])
+}
+
+// assessStored flags stored model responses that look low-effort against the
+// user prompt before them, as is done when a response finishes streaming
+func assessStored(messages []DebateMessage) {
+	prompt := ""
+	for i := range messages {
+		switch msg := &messages[i]; {
+		case msg.Source == "user":
+			prompt = msg.Content
+		case msg.MsgType == "model":
+			msg.QualityWarning = consensus.AssessQuality(prompt, msg.Content).Warning()
+		}
+	}
+}
+
+// loadUsage restores a debate's accumulated token/cost usage from the database
+func loadUsage(store *db.Store, debate *Debate) {
+	records, err := store.GetUsage(debate.ID)
+	if err != nil {
+		return
+	}
+	for _, u := range records {
+		debate.AddUsage(u.ModelID, models.Usage{
+			PromptTokens:     u.PromptTokens,
+			CompletionTokens: u.CompletionTokens,
+			Cost:             u.Cost,
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// internal/ui/inputhistory.go
+package ui
+
+// maxInputHistory is how many sent inputs the session remembers
+const maxInputHistory = 100
+
+// inputHistory recalls previously sent inputs, bash-style. pos is the entry
+// being shown; len(entries) means the user isn't browsing, and draft holds
+// what they had typed before they started.
+type inputHistory struct {
+	entries []string
+	pos     int
+	draft   string
+}
+
+// Push records a sent input and stops browsing. Repeating the last entry
+// doesn't add it again.
+func (h *inputHistory) Push(input string) {
+	if n := len(h.entries); n == 0 || h.entries[n-1] != input {
+		h.entries = append(h.entries, input)
+		if len(h.entries) > maxInputHistory {
+			h.entries = h.entries[len(h.entries)-maxInputHistory:]
+		}
+	}
+	h.pos = len(h.entries)
+	h.draft = ""
+}
+
+// Prev returns the entry before the one shown, saving current as the draft
+// when browsing starts. ok is false at the oldest entry.
+func (h *inputHistory) Prev(current string) (string, bool) {
+	if h.pos == 0 {
+		return "", false
+	}
+	if h.pos == len(h.entries) {
+		h.draft = current
+	}
+	h.pos--
+	return h.entries[h.pos], true
+}
+
+// Next returns the entry after the one shown, or the draft after the newest.
+// ok is false when not browsing.
+func (h *inputHistory) Next() (string, bool) {
+	if h.pos >= len(h.entries) {
+		return "", false
+	}
+	h.pos++
+	if h.pos == len(h.entries) {
+		return h.draft, true
+	}
+	return h.entries[h.pos], true
+}
@@ -0,0 +1,71 @@
+// internal/ui/layout.go
+package ui
+
+// Pane sizing limits, in columns of content (excluding borders)
+const (
+	minContextWidth = 16
+	minModelsWidth  = 14
+	minChatWidth    = 30
+
+	// Each pane has a one-column border on both sides
+	paneBorder = 2
+
+	defaultContextWidthPct = 20
+	defaultModelsWidthPct  = 12
+)
+
+// paneWidths holds the content width of each of the three main panes.
+// A hidden pane has width 0.
+type paneWidths struct {
+	Context int
+	Chat    int
+	Models  int
+}
+
+// computeLayout splits a terminal width between the context, chat and models
+// panes. Side panes scale with the width but never drop below their minimums
+// unless the chat would otherwise be squeezed below its own; the chat width is
+// never negative. Hidden side panes give all their space to the chat.
+func computeLayout(width, contextPct, modelsPct int, showContext, showModels bool) paneWidths {
+	if contextPct <= 0 {
+		contextPct = defaultContextWidthPct
+	}
+	if modelsPct <= 0 {
+		modelsPct = defaultModelsWidthPct
+	}
+
+	borders := paneBorder
+	if showContext {
+		borders += paneBorder
+	}
+	if showModels {
+		borders += paneBorder
+	}
+	avail := max(width-borders, 0)
+
+	var ctx, mdl int
+	if showContext {
+		ctx = max(avail*contextPct/100, minContextWidth)
+	}
+	if showModels {
+		mdl = max(avail*modelsPct/100, minModelsWidth)
+	}
+
+	// Give the chat back its minimum from the side panes' surplus first
+	if short := minChatWidth - (avail - ctx - mdl); short > 0 {
+		if showContext {
+			take := min(short, ctx-minContextWidth)
+			ctx -= take
+			short -= take
+		}
+		if showModels {
+			mdl -= min(short, mdl-minModelsWidth)
+		}
+	}
+
+	return paneWidths{
+		Context: ctx,
+		Chat:    max(avail-ctx-mdl, 0),
+		Models:  mdl,
+	}
+}
@@ -0,0 +1,54 @@
+// internal/ui/layout_test.go
+package ui
+
+import "testing"
+
+func TestComputeLayout(t *testing.T) {
+	tests := []struct {
+		name                 string
+		width                int
+		contextPct, modelPct int
+		want                 paneWidths
+	}{
+		{"80 columns uses minimums", 80, 20, 12, paneWidths{Context: 16, Chat: 44, Models: 14}},
+		{"120 columns", 120, 20, 12, paneWidths{Context: 22, Chat: 78, Models: 14}},
+		{"wide terminal scales side panes", 200, 20, 12, paneWidths{Context: 38, Chat: 133, Models: 23}},
+		{"custom ratios", 200, 30, 20, paneWidths{Context: 58, Chat: 98, Models: 38}},
+		{"zero ratios fall back to defaults", 120, 0, 0, paneWidths{Context: 22, Chat: 78, Models: 14}},
+		{"oversized ratios keep chat minimum", 100, 60, 40, paneWidths{Context: 27, Chat: 30, Models: 37}},
+		{"narrow terminal clamps chat to zero", 20, 20, 12, paneWidths{Context: 16, Chat: 0, Models: 14}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeLayout(tt.width, tt.contextPct, tt.modelPct, true, true)
+			if got != tt.want {
+				t.Errorf("computeLayout(%d, %d, %d) = %+v, want %+v", tt.width, tt.contextPct, tt.modelPct, got, tt.want)
+			}
+			if got.Chat < 0 {
+				t.Errorf("chat width is negative: %d", got.Chat)
+			}
+			// When everything fits, the panes fill the terminal exactly
+			if total := got.Context + got.Chat + got.Models + 3*paneBorder; got.Chat > 0 && total != tt.width {
+				t.Errorf("panes total %d columns, want %d", total, tt.width)
+			}
+		})
+	}
+}
+
+func TestComputeLayout_HiddenPanes(t *testing.T) {
+	both := computeLayout(80, 20, 12, true, true)
+	noContext := computeLayout(80, 20, 12, false, true)
+	noModels := computeLayout(80, 20, 12, true, false)
+	chatOnly := computeLayout(80, 20, 12, false, false)
+
+	if noContext.Context != 0 || noModels.Models != 0 {
+		t.Errorf("hidden panes should have zero width, got %+v and %+v", noContext, noModels)
+	}
+	if noContext.Chat <= both.Chat || noModels.Chat <= both.Chat {
+		t.Errorf("chat should grow when a pane is hidden: both=%d noContext=%d noModels=%d", both.Chat, noContext.Chat, noModels.Chat)
+	}
+	if chatOnly.Chat != 80-paneBorder {
+		t.Errorf("chat-only width = %d, want %d", chatOnly.Chat, 80-paneBorder)
+	}
+}
@@ -0,0 +1,147 @@
+// internal/ui/proposed.go
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ProposedCommand is a shell block a model suggested running
+type ProposedCommand struct {
+	Source      string // Model that proposed it
+	Command     string
+	Destructive bool // Matches a destructivePatterns entry
+}
+
+// shellFenceLangs are the code fence languages treated as shell commands
+var shellFenceLangs = map[string]bool{
+	"bash":    true,
+	"sh":      true,
+	"shell":   true,
+	"zsh":     true,
+	"console": true,
+}
+
+// destructivePatterns flag commands that delete data or rewrite history
+var destructivePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\brm\s+(-[a-zA-Z]*[rf][a-zA-Z]*\s+)+`),
+	regexp.MustCompile(`\bgit\s+(push\s+.*(--force\b|-f\b)|reset\s+--hard|clean\s+-[a-zA-Z]*f)`),
+	regexp.MustCompile(`\b(mkfs(\.\w+)?|shred|wipefs)\b|\bdd\s+.*\bof=`),
+	regexp.MustCompile(`>\s*/dev/(sd|nvme|disk)`),
+	regexp.MustCompile(`\bchmod\s+-R\s+0?777\b|\bchown\s+-R\b`),
+	regexp.MustCompile(`(?i)\b(drop\s+(table|database)|truncate\s+table)\b`),
+	regexp.MustCompile(`:\(\)\s*\{\s*:\|:&\s*\};:`),
+}
+
+// isDestructive reports whether a command looks like it deletes data
+func isDestructive(command string) bool {
+	for _, re := range destructivePatterns {
+		if re.MatchString(command) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractShellBlocks returns the contents of the bash/sh fenced code blocks
+// in content, in order. An unterminated block runs to the end.
+func extractShellBlocks(content string) []string {
+	var blocks []string
+	var current []string
+	inBlock, isShell := false, false
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			if inBlock && isShell {
+				current = append(current, line)
+			}
+			continue
+		}
+		if inBlock {
+			if isShell {
+				if block := strings.TrimSpace(strings.Join(current, "\n")); block != "" {
+					blocks = append(blocks, block)
+				}
+			}
+			inBlock, current = false, nil
+			continue
+		}
+		inBlock = true
+		lang, _, _ := strings.Cut(strings.TrimPrefix(trimmed, "```"), " ")
+		isShell = shellFenceLangs[strings.ToLower(lang)]
+	}
+
+	if inBlock && isShell {
+		if block := strings.TrimSpace(strings.Join(current, "\n")); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// shellCommands returns the commands proposed in one model message
+func shellCommands(source, content string) []ProposedCommand {
+	var cmds []ProposedCommand
+	for _, block := range extractShellBlocks(content) {
+		cmds = append(cmds, ProposedCommand{Source: source, Command: block, Destructive: isDestructive(block)})
+	}
+	return cmds
+}
+
+// ProposedCommands returns the shell commands models have proposed in the
+// loaded messages, oldest first
+func (d *Debate) ProposedCommands() []ProposedCommand {
+	var cmds []ProposedCommand
+	for _, msg := range d.Messages {
+		if isParticipant(msg.Source) {
+			cmds = append(cmds, shellCommands(msg.Source, msg.Content)...)
+		}
+	}
+	return cmds
+}
+
+// commandsNote announces the commands in a just-finished response, or
+// returns "" if it has none
+func commandsNote(source string, cmds []ProposedCommand) string {
+	if len(cmds) == 0 {
+		return ""
+	}
+	destructive := 0
+	for _, c := range cmds {
+		if c.Destructive {
+			destructive++
+		}
+	}
+	note := fmt.Sprintf("%s proposed %d shell command(s)", formatSource(source), len(cmds))
+	if destructive > 0 {
+		note += fmt.Sprintf(", %d possibly destructive", destructive)
+	}
+	return note + ". Use /commands to review them."
+}
+
+// commandsList renders the debate's proposed commands for /commands
+func commandsList(cmds []ProposedCommand) string {
+	if len(cmds) == 0 {
+		return "No shell commands proposed in this debate."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Proposed commands (%d):", len(cmds))
+	for i, c := range cmds {
+		flag := ""
+		if c.Destructive {
+			flag = " [DESTRUCTIVE]"
+		}
+		fmt.Fprintf(&b, "\n%d. %s%s:\n%s", i+1, formatSource(c.Source), flag, indent(c.Command, "   "))
+	}
+	return b.String()
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
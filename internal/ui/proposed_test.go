@@ -0,0 +1,65 @@
+// internal/ui/proposed_test.go
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractShellBlocks(t *testing.T) {
+	response := "ADD: clean the build first.\n\n" +
+		"```bash\nmake clean\nmake test\n```\n\n" +
+		"Then check the config:\n\n" +
+		"```yaml\nport: 8080\n```\n\n" +
+		"```sh\nrm -rf ./dist\n```\n\n" +
+		"```\nnot labelled\n```\n\n" +
+		"```Shell\necho unterminated"
+
+	want := []string{"make clean\nmake test", "rm -rf ./dist", "echo unterminated"}
+	if got := extractShellBlocks(response); !slices.Equal(got, want) {
+		t.Errorf("extractShellBlocks() = %q, want %q", got, want)
+	}
+}
+
+func TestIsDestructive(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"rm -rf ./dist", true},
+		{"rm -f build.log", true},
+		{"rm notes.txt", false},
+		{"git push --force origin main", true},
+		{"git push origin main", false},
+		{"git reset --hard HEAD~1", true},
+		{"dd if=image.iso of=/dev/sdb", true},
+		{"psql -c 'DROP TABLE users'", true},
+		{"go test ./...", false},
+	}
+	for _, tt := range tests {
+		if got := isDestructive(tt.command); got != tt.want {
+			t.Errorf("isDestructive(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestProposedCommands(t *testing.T) {
+	debate := NewDebate("d", "Deploy")
+	debate.AddMessage("user", "```bash\necho from the user\n```")
+	debate.AddMessage("claude", "Run:\n```bash\ngo test ./...\n```")
+	debate.AddMessage("gemini", "OBJECT: first\n```sh\ngit reset --hard\n```")
+
+	cmds := debate.ProposedCommands()
+	if len(cmds) != 2 || cmds[0].Source != "claude" || cmds[1].Source != "gemini" {
+		t.Fatalf("expected claude's and gemini's commands only, got %+v", cmds)
+	}
+	if cmds[0].Destructive || !cmds[1].Destructive {
+		t.Errorf("expected only gemini's command flagged, got %+v", cmds)
+	}
+
+	list := commandsList(cmds)
+	if !strings.Contains(list, "2. Gemini [DESTRUCTIVE]:\n   git reset --hard") {
+		t.Errorf("unexpected list:\n%s", list)
+	}
+}
@@ -0,0 +1,51 @@
+// internal/ui/splash.go
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"roundtable/internal/config"
+)
+
+// updateConfigErrors handles keys on the config error splash
+func (m Model) updateConfigErrors(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "ctrl+q", "q":
+		return m, tea.Quit
+	case "enter", "esc":
+		m.viewMode = ViewNormal
+	}
+	return m, nil
+}
+
+// renderConfigErrors renders the startup splash listing config problems
+func (m Model) renderConfigErrors() string {
+	var content strings.Builder
+	content.WriteString(TitleStyle.Render("ROUNDTABLE - CONFIGURATION PROBLEMS"))
+	content.WriteString("\n\n")
+	content.WriteString(DimStyle.Render(config.ConfigPath()))
+	content.WriteString("\n\n")
+	content.WriteString(ErrorStyle.Render(formatConfigErrors(m.configErrors)))
+	content.WriteString("\n\n")
+
+	if m.registry.Count() == 0 {
+		content.WriteString(StatusWarn.Render("No models are available - fix the config and restart, or use /reload."))
+		content.WriteString("\n\n")
+	}
+	content.WriteString(DimStyle.Render("Enter: continue anyway | q: quit"))
+
+	box := ActiveBox.Padding(1, 2).Render(content.String())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// formatConfigErrors renders config errors as a bulleted list
+func formatConfigErrors(errs []error) string {
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = "- " + err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
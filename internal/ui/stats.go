@@ -0,0 +1,65 @@
+// internal/ui/stats.go
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"roundtable/internal/metrics"
+)
+
+// ModelStats tracks one model's responses over the session
+type ModelStats struct {
+	LastLatency time.Duration // Time to the most recent final response
+	Successes   int
+	Timeouts    int
+	Errors      int
+}
+
+// Record counts a response with outcome (a metrics.Outcome* value)
+func (s *ModelStats) Record(outcome string, latency time.Duration) {
+	switch outcome {
+	case metrics.OutcomeSuccess:
+		s.Successes++
+	case metrics.OutcomeTimeout:
+		s.Timeouts++
+	default:
+		s.Errors++
+	}
+	s.LastLatency = latency
+}
+
+// Total returns how many responses were recorded
+func (s ModelStats) Total() int {
+	return s.Successes + s.Timeouts + s.Errors
+}
+
+// Hint summarizes the stats for the models pane, e.g. "12s, 2/10 timeouts".
+// It's empty until a response is recorded.
+func (s ModelStats) Hint() string {
+	total := s.Total()
+	if total == 0 {
+		return ""
+	}
+	hint := formatElapsedTime(s.LastLatency)
+	if s.Timeouts > 0 {
+		hint += fmt.Sprintf(", %d/%d timeouts", s.Timeouts, total)
+	}
+	if s.Errors > 0 {
+		hint += fmt.Sprintf(", %d/%d errors", s.Errors, total)
+	}
+	return hint
+}
+
+// recordResponse updates modelID's stats, creating them on first use
+func (m *Model) recordResponse(modelID, outcome string, latency time.Duration) {
+	if m.modelStats == nil {
+		m.modelStats = make(map[string]*ModelStats)
+	}
+	stats := m.modelStats[modelID]
+	if stats == nil {
+		stats = &ModelStats{}
+		m.modelStats[modelID] = stats
+	}
+	stats.Record(outcome, latency)
+}
@@ -0,0 +1,60 @@
+// internal/ui/stats_test.go
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestModelStats_Hint(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats ModelStats
+		want  string
+	}{
+		{"no responses", ModelStats{}, ""},
+		{"all good", ModelStats{LastLatency: 3 * time.Second, Successes: 4}, "3s"},
+		{"timeouts", ModelStats{LastLatency: 2 * time.Minute, Successes: 8, Timeouts: 2}, "2m0s, 2/10 timeouts"},
+		{"both", ModelStats{LastLatency: 500 * time.Millisecond, Successes: 1, Timeouts: 1, Errors: 1}, "<1s, 1/3 timeouts, 1/3 errors"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.Hint(); got != tt.want {
+				t.Errorf("Hint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelResponse_UpdatesStats(t *testing.T) {
+	m := newTestModel()
+	_, seq := m.startRound()
+
+	responses := []modelResponseMsg{
+		{modelID: "gpt", started: true},
+		{modelID: "gpt", content: "answer", done: true},
+		{modelID: "gpt", started: true},
+		{modelID: "gpt", err: errors.New("request timed out"), isTimeout: true, done: true},
+		{modelID: "gpt", started: true},
+		{modelID: "gpt", err: errors.New("API error 500"), done: true},
+		{modelID: "claude", content: "answer", done: true},
+		{modelID: "grok", done: true, stopped: true},
+	}
+	for _, msg := range responses {
+		msg.seq = seq
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	gpt := m.modelStats["gpt"]
+	if gpt == nil || gpt.Successes != 1 || gpt.Timeouts != 1 || gpt.Errors != 1 {
+		t.Fatalf("gpt stats = %+v, want 1 success, 1 timeout, 1 error", gpt)
+	}
+	if claude := m.modelStats["claude"]; claude == nil || claude.Successes != 1 || claude.Total() != 1 {
+		t.Errorf("claude stats = %+v, want 1 success", claude)
+	}
+	if grok := m.modelStats["grok"]; grok != nil {
+		t.Errorf("grok stats = %+v, a model stopped early shouldn't be counted", grok)
+	}
+}
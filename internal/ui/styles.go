@@ -1,107 +1,318 @@
 // internal/ui/styles.go
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+
+	"roundtable/internal/config"
+	"roundtable/internal/models"
+)
+
+// Theme is a color palette for the whole UI
+type Theme struct {
+	Name string
+
+	Accent    lipgloss.Color // Titles, active borders and tabs
+	Success   lipgloss.Color
+	Highlight lipgloss.Color // System messages, section headers
+	Warning   lipgloss.Color
+	Danger    lipgloss.Color
+	Secondary lipgloss.Color // Slash commands in help
+	User      lipgloss.Color
+	Dim       lipgloss.Color
+	Text      lipgloss.Color
+
+	// Per-model colors keyed by model ID
+	Models map[string]lipgloss.Color
+}
+
+// Built-in themes
 var (
-	// Colors
-	Cyan     = lipgloss.Color("#00FFFF")
-	Green    = lipgloss.Color("#00FF00")
-	Yellow   = lipgloss.Color("#FFD700")
-	Orange   = lipgloss.Color("#FFA500")
-	Red      = lipgloss.Color("#FF6B6B")
-	Magenta  = lipgloss.Color("#FF00FF")
-	SkyBlue  = lipgloss.Color("#87CEEB")
-	Dim      = lipgloss.Color("#555555")
-	White    = lipgloss.Color("#FFFFFF")
-	DarkGray = lipgloss.Color("#333333")
-
-	// Model colors
-	ClaudeColor = Cyan
-	GPTColor    = Green
-	GeminiColor = Magenta
-	GrokColor   = Orange
-	UserColor   = SkyBlue
-	SystemColor = Yellow
+	DarkTheme = Theme{
+		Name:      "dark",
+		Accent:    "#00FFFF",
+		Success:   "#00FF00",
+		Highlight: "#FFD700",
+		Warning:   "#FFA500",
+		Danger:    "#FF6B6B",
+		Secondary: "#FF00FF",
+		User:      "#87CEEB",
+		Dim:       "#555555",
+		Text:      "#FFFFFF",
+		Models: map[string]lipgloss.Color{
+			"claude": "#00FFFF",
+			"gpt":    "#00FF00",
+			"gemini": "#FF00FF",
+			"grok":   "#FFA500",
+		},
+	}
+
+	LightTheme = Theme{
+		Name:      "light",
+		Accent:    "#006D77",
+		Success:   "#2B7A0B",
+		Highlight: "#9A6700",
+		Warning:   "#B35900",
+		Danger:    "#C62828",
+		Secondary: "#8E24AA",
+		User:      "#1565C0",
+		Dim:       "#8A8A8A",
+		Text:      "#1A1A1A",
+		Models: map[string]lipgloss.Color{
+			"claude": "#006D77",
+			"gpt":    "#2B7A0B",
+			"gemini": "#8E24AA",
+			"grok":   "#B35900",
+		},
+	}
+
+	HighContrastTheme = Theme{
+		Name:      "high-contrast",
+		Accent:    "#FFFF00",
+		Success:   "#00FF00",
+		Highlight: "#FFFFFF",
+		Warning:   "#FF8000",
+		Danger:    "#FF0000",
+		Secondary: "#FF00FF",
+		User:      "#00FFFF",
+		Dim:       "#C0C0C0",
+		Text:      "#FFFFFF",
+		Models: map[string]lipgloss.Color{
+			"claude": "#00FFFF",
+			"gpt":    "#00FF00",
+			"gemini": "#FF00FF",
+			"grok":   "#FF8000",
+		},
+	}
+)
+
+// themes are the built-in themes by name
+var themes = map[string]Theme{
+	DarkTheme.Name:         DarkTheme,
+	LightTheme.Name:        LightTheme,
+	HighContrastTheme.Name: HighContrastTheme,
+}
+
+// ThemeNames returns the built-in theme names, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
 
+// LookupTheme returns the built-in theme with the given name.
+// An empty name means the dark theme.
+func LookupTheme(name string) (Theme, error) {
+	if name == "" {
+		return DarkTheme, nil
+	}
+	t, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	return t, nil
+}
+
+// WithColors returns a copy of t with palette entries overridden by name
+// (accent, success, highlight, warning, danger, secondary, user, dim, text)
+func (t Theme) WithColors(colors map[string]string) (Theme, error) {
+	for key, value := range colors {
+		c := lipgloss.Color(value)
+		switch strings.ToLower(key) {
+		case "accent":
+			t.Accent = c
+		case "success":
+			t.Success = c
+		case "highlight":
+			t.Highlight = c
+		case "warning":
+			t.Warning = c
+		case "danger":
+			t.Danger = c
+		case "secondary":
+			t.Secondary = c
+		case "user":
+			t.User = c
+		case "dim":
+			t.Dim = c
+		case "text":
+			t.Text = c
+		default:
+			return t, fmt.Errorf("unknown theme color %q", key)
+		}
+	}
+	return t, nil
+}
+
+// configTheme returns the theme selected in cfg with its color overrides
+// applied. On error it returns the best usable theme alongside the error.
+func configTheme(cfg *config.Config) (Theme, error) {
+	base, err := LookupTheme(cfg.UI.Theme)
+	if err != nil {
+		return DarkTheme, fmt.Errorf("ui.theme: %w", err)
+	}
+	t, err := base.WithColors(cfg.UI.Colors)
+	if err != nil {
+		return base, fmt.Errorf("ui.colors: %w", err)
+	}
+	return t, nil
+}
+
+// ActiveTheme is the theme the styles below were built from; change it with ApplyTheme
+var ActiveTheme Theme
+
+var (
 	// Box styles
+	ActiveBox   lipgloss.Style
+	InactiveBox lipgloss.Style
+
+	// Text styles
+	TitleStyle  lipgloss.Style
+	UserStyle   lipgloss.Style
+	SystemStyle lipgloss.Style
+	ErrorStyle  lipgloss.Style
+	DimStyle    lipgloss.Style
+
+	// Status indicators
+	StatusOK   lipgloss.Style
+	StatusWarn lipgloss.Style
+	StatusCrit lipgloss.Style
+
+	// Tab styles
+	ActiveTabStyle   lipgloss.Style
+	InactiveTabStyle lipgloss.Style
+)
+
+func init() {
+	ApplyTheme(DarkTheme)
+}
+
+// ApplyTheme makes t the active theme and rebuilds every style from it
+func ApplyTheme(t Theme) {
+	ActiveTheme = t
+	styleGeneration++
+
 	ActiveBox = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(Cyan)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent)
 
 	InactiveBox = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(Dim)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Dim)
 
-	// Text styles
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Cyan)
+		Bold(true).
+		Foreground(t.Accent)
 
 	UserStyle = lipgloss.NewStyle().
-			Foreground(SkyBlue).
-			Bold(true)
+		Foreground(t.User).
+		Bold(true)
 
 	SystemStyle = lipgloss.NewStyle().
-			Foreground(Yellow)
+		Foreground(t.Highlight)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(Red).
-			Bold(true)
+		Foreground(t.Danger).
+		Bold(true)
 
 	DimStyle = lipgloss.NewStyle().
-			Foreground(Dim)
+		Foreground(t.Dim)
 
-	// Status indicators
-	StatusOK   = lipgloss.NewStyle().Foreground(Green).Bold(true)
-	StatusWarn = lipgloss.NewStyle().Foreground(Orange).Bold(true)
-	StatusCrit = lipgloss.NewStyle().Foreground(Red).Bold(true)
+	StatusOK = lipgloss.NewStyle().Foreground(t.Success).Bold(true)
+	StatusWarn = lipgloss.NewStyle().Foreground(t.Warning).Bold(true)
+	StatusCrit = lipgloss.NewStyle().Foreground(t.Danger).Bold(true)
 
-	// Tab styles
 	ActiveTabStyle = lipgloss.NewStyle().
-			Foreground(Cyan).
-			Bold(true)
+		Foreground(t.Accent).
+		Bold(true)
 
 	InactiveTabStyle = lipgloss.NewStyle().
-				Foreground(Dim)
-)
+		Foreground(t.Dim)
 
-// ModelStyle returns the style for a given model ID
-func ModelStyle(modelID string) lipgloss.Style {
+	applyHelpTheme(t)
+}
+
+// styleGeneration counts style changes, so cached renderings made with
+// older styles can be told apart
+var styleGeneration int
+
+// modelColors are per-model colors from outside the theme, set by setModelColors
+var modelColors struct {
+	configured map[string]lipgloss.Color // Set in the config file; win over the theme
+	info       map[string]lipgloss.Color // ModelInfo.Color, for models the theme doesn't know
+}
+
+// setModelColors records the colors configured for models and reported by the
+// registry's models so ModelStyle can use them
+func setModelColors(cfg *config.Config, registry *models.Registry) {
+	styleGeneration++
+	modelColors.configured = make(map[string]lipgloss.Color)
+	modelColors.info = make(map[string]lipgloss.Color)
+	if cfg != nil {
+		for id, c := range cfg.ModelColors() {
+			modelColors.configured[id] = lipgloss.Color(c)
+		}
+	}
+	if registry != nil {
+		for _, m := range registry.All() {
+			if info := m.Info(); info.Color != "" {
+				modelColors.info[info.ID] = lipgloss.Color(info.Color)
+			}
+		}
+	}
+}
+
+// hashPalette holds fallback colors for models with no configured or theme color
+var hashPalette = []lipgloss.Color{
+	"#5FAFFF", "#AF87FF", "#FF87AF", "#87D787",
+	"#D7AF5F", "#5FD7AF", "#FF875F", "#AFAF00",
+}
+
+// hashColor picks a stable color for a model ID from hashPalette
+func hashColor(modelID string) lipgloss.Color {
+	h := fnv.New32a()
+	h.Write([]byte(modelID))
+	return hashPalette[h.Sum32()%uint32(len(hashPalette))]
+}
+
+// ModelColor returns the color for a given model ID: a configured color, then
+// the theme's, then the model's own, then one derived from the ID
+func ModelColor(modelID string) lipgloss.Color {
 	switch modelID {
-	case "claude":
-		return lipgloss.NewStyle().Foreground(ClaudeColor).Bold(true)
-	case "gpt":
-		return lipgloss.NewStyle().Foreground(GPTColor).Bold(true)
-	case "gemini":
-		return lipgloss.NewStyle().Foreground(GeminiColor).Bold(true)
-	case "grok":
-		return lipgloss.NewStyle().Foreground(GrokColor).Bold(true)
 	case "user":
-		return UserStyle
+		return ActiveTheme.User
 	case "system":
-		return SystemStyle
-	default:
-		return lipgloss.NewStyle().Foreground(White)
+		return ActiveTheme.Highlight
+	case moderatorSource:
+		return ActiveTheme.Highlight
+	}
+	if c, ok := modelColors.configured[modelID]; ok {
+		return c
+	}
+	if c, ok := ActiveTheme.Models[modelID]; ok {
+		return c
 	}
+	if c, ok := modelColors.info[modelID]; ok {
+		return c
+	}
+	return hashColor(modelID)
 }
 
-// ModelColor returns the color for a given model ID
-func ModelColor(modelID string) lipgloss.Color {
+// ModelStyle returns the style for a given model ID
+func ModelStyle(modelID string) lipgloss.Style {
 	switch modelID {
-	case "claude":
-		return ClaudeColor
-	case "gpt":
-		return GPTColor
-	case "gemini":
-		return GeminiColor
-	case "grok":
-		return GrokColor
 	case "user":
-		return SkyBlue
+		return UserStyle
 	case "system":
-		return Yellow
-	default:
-		return White
+		return SystemStyle
 	}
+	return lipgloss.NewStyle().Foreground(ModelColor(modelID)).Bold(true)
 }
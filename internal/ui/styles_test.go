@@ -0,0 +1,100 @@
+// internal/ui/styles_test.go
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"roundtable/internal/config"
+	"roundtable/internal/models"
+)
+
+func TestApplyTheme_ChangesRenderedColors(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(termenv.Ascii)
+	defer ApplyTheme(DarkTheme)
+
+	ApplyTheme(DarkTheme)
+	darkTitle := TitleStyle.Render("ROUNDTABLE")
+	darkClaude := ModelStyle("claude").Render("Claude")
+	darkIdle := statusIndicator(models.StatusIdle)
+
+	ApplyTheme(LightTheme)
+	lightTitle := TitleStyle.Render("ROUNDTABLE")
+	lightClaude := ModelStyle("claude").Render("Claude")
+	lightIdle := statusIndicator(models.StatusIdle)
+
+	// #00FFFF and #006D77 as truecolor escape parameters
+	if !strings.Contains(darkTitle, "38;2;0;255;255") {
+		t.Errorf("dark title should use the dark accent, got %q", darkTitle)
+	}
+	if !strings.Contains(lightTitle, "38;2;0;109;119") {
+		t.Errorf("light title should use the light accent, got %q", lightTitle)
+	}
+	if darkClaude == lightClaude {
+		t.Error("model style should change with the theme")
+	}
+	if darkIdle == lightIdle {
+		t.Error("status indicator should change with the theme")
+	}
+}
+
+func TestConfigTheme(t *testing.T) {
+	cfg := config.Default()
+	cfg.UI.Theme = "Light"
+	cfg.UI.Colors = map[string]string{"accent": "#123456"}
+
+	theme, err := configTheme(cfg)
+	if err != nil {
+		t.Fatalf("configTheme() error: %v", err)
+	}
+	if theme.Name != "light" || theme.Accent != "#123456" || theme.Dim != LightTheme.Dim {
+		t.Errorf("configTheme() = %+v, want light with accent override", theme)
+	}
+
+	cfg.UI.Theme = "solarized"
+	if theme, err := configTheme(cfg); err == nil || theme.Name != "dark" {
+		t.Errorf("unknown theme should fall back to dark with an error, got %q, %v", theme.Name, err)
+	}
+
+	cfg.UI.Theme = "dark"
+	cfg.UI.Colors = map[string]string{"accnet": "#123456"}
+	if _, err := configTheme(cfg); err == nil || !strings.Contains(err.Error(), "accnet") {
+		t.Errorf("expected an error naming the unknown color, got %v", err)
+	}
+}
+
+func TestModelColor_ConfiguredAndHashed(t *testing.T) {
+	defer setModelColors(nil, nil)
+
+	cfg := config.Default()
+	cfg.Models.Claude.Color = "#123456"
+	cfg.Models.Exec = []config.ExecModelConfig{
+		{ID: "local", Enabled: true, Command: "true", Color: "#ABCDEF"},
+		{ID: "plain", Enabled: true, Command: "true"},
+	}
+	setModelColors(cfg, models.NewRegistry(cfg))
+
+	// Configured colors win, even over the theme's built-in model colors
+	if got := ModelColor("claude"); got != "#123456" {
+		t.Errorf("claude color = %q, want configured #123456", got)
+	}
+	if got := ModelColor("local"); got != "#ABCDEF" {
+		t.Errorf("local color = %q, want configured #ABCDEF", got)
+	}
+	if got := ModelColor("gemini"); got != ActiveTheme.Models["gemini"] {
+		t.Errorf("gemini color = %q, want theme color %q", got, ActiveTheme.Models["gemini"])
+	}
+
+	// Unconfigured custom IDs get a stable color that isn't the plain text color
+	plain := ModelColor("plain")
+	if plain == ActiveTheme.Text || plain == "" {
+		t.Errorf("plain color = %q, want a distinct fallback", plain)
+	}
+	if again := ModelColor("plain"); again != plain {
+		t.Errorf("fallback color not stable: %q then %q", plain, again)
+	}
+}